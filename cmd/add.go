@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chrisloarryn/ccin/internal/common"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagDir = "dir"
+
+	errorNoProject   = "❌ %v\n"
+	errorAddResource = "❌ Could not add resource: %v\n"
+	helpNoProject    = "💡 Run this command from the root of a project created with ccin generate, or pass --dir"
+)
+
+// addCmd groups the commands that extend an existing project
+var addCmd = &cobra.Command{
+	Use:   "add",
+	Short: "➕ Add code to a project generated by ccin",
+}
+
+// addResourceCmd adds a new domain to an existing project
+var addResourceCmd = &cobra.Command{
+	Use:   "resource [name]",
+	Short: "🧩 Add a domain/resource to an existing project",
+	Long: color.New(color.FgCyan, color.Bold).Sprint("🧩 ADD RESOURCE\n\n") +
+		color.New(color.FgWhite).Sprint("Detects which generator produced the project, renders the per-domain files\n"+
+			"(model, service, handler, ...) for the new resource and registers it in the\n"+
+			"shared files (routes, modules, ...) at their ccin:<region> markers.\n"+
+			"Existing files are never overwritten.\n\n") +
+		color.New(color.FgCyan).Sprint(exampleHeader) + color.New(color.FgWhite, color.Bold).Sprint("ccin add resource invoice --field total:decimal"),
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runAddResource(cmd, args[0])
+	},
+}
+
+// runAddResource detects the project and adds each requested domain to it
+func runAddResource(cmd *cobra.Command, name string) {
+	dir, _ := cmd.Flags().GetString(flagDir)
+
+	generator, config, err := common.DetectProject(dir)
+	if err != nil {
+		color.New(color.FgRed, color.Bold).Printf(errorNoProject, err)
+		color.New(color.FgYellow).Println(helpNoProject)
+		return
+	}
+	if templateSource != "" {
+		config.TemplateSource = templateSource
+	}
+
+	domains, err := resolveDomains(cmd, common.ParseDomainNames([]string{name}), nil)
+	if err != nil {
+		color.New(color.FgRed, color.Bold).Printf(errorInvalidDomains, err)
+		return
+	}
+
+	metadata := generator.GetMetadata()
+	color.New(color.FgCyan, color.Bold).Printf("\n%s Adding to %s project: ", metadata.Icon, metadata.DisplayName)
+	color.New(color.FgWhite, color.Bold).Printf("%s\n", config.ProjectName)
+	color.New(color.FgHiBlack).Println(separatorLine)
+
+	for _, domain := range domains {
+		config.Domains = []common.Domain{domain}
+		result, err := common.AddResource(generator, config)
+		if err != nil {
+			color.New(color.FgRed, color.Bold).Printf(errorAddResource, err)
+			return
+		}
+		printResourceResult(domain, result)
+	}
+}
+
+func printResourceResult(domain common.Domain, result *common.ResourceResult) {
+	color.New(color.FgGreen, color.Bold).Printf("\n✅ Resource '%s' added\n", domain.Name)
+	for _, file := range result.Created {
+		color.New(color.FgGreen).Print("   + ")
+		color.New(color.FgWhite).Println(file)
+	}
+	for _, file := range result.Patched {
+		color.New(color.FgYellow).Print("   ~ ")
+		color.New(color.FgWhite).Println(file)
+	}
+
+	for _, registration := range result.Manual {
+		color.New(color.FgYellow, color.Bold).Printf("\n⚠️  Marker ccin:%s not found in %s, add this by hand:\n", registration.Region, registration.File)
+		color.New(color.FgHiBlack).Println(strings.TrimRight(registration.Snippet, "\n"))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(addCmd)
+	addCmd.AddCommand(addResourceCmd)
+
+	addCmd.PersistentFlags().String(flagDir, ".", "Root directory of the generated project")
+	addTemplateFlags(addCmd.PersistentFlags(), "Read templates from this source instead of the recorded or embedded ones")
+
+	addResourceCmd.Flags().StringArray(flagField, nil, fmt.Sprintf("Entity field as name:type[:unique][:optional], repeatable (types: %s)", strings.Join(common.FieldTypes, ", ")))
+	addResourceCmd.Flags().String(flagEntity, "", "YAML file describing the entity name and fields")
+}
@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/chrisloarryn/ccin/internal/common"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// Settings without a flag of their own
+	settingGenerator = "generator"
+
+	// Flags that also have a setting
+	flagAuthor       = "author"
+	flagLicense      = "license"
+	flagModulePrefix = "module-prefix"
+
+	envPrefix         = "CCIN"
+	defaultConfigFile = ".ccin.yaml"
+
+	errorConfig = "❌ %v\n"
+	helpConfig  = "💡 Known settings: %s\n"
+)
+
+// setting is an organization-wide default read from the config file, a
+// CCIN_* environment variable or the flag of the same name. A flag given on
+// the command line wins over the environment, which wins over the file.
+type setting struct {
+	key   string
+	usage string
+	check func(value string) error // validates values written with `ccin config set`
+}
+
+// settings lists every key the config file and environment may set
+var settings = []setting{
+	{key: settingGenerator, usage: "framework preselected by ccin new", check: checkGenerator},
+	{key: flagGCPProject, usage: "GCP project ID for metrics integration"},
+	{key: flagGRPC, usage: "include gRPC support where the framework offers it (true/false)", check: checkBool},
+	{key: flagTemplate, usage: "template source used instead of the embedded templates"},
+	{key: flagAuthor, usage: "project author written to package manifests"},
+	{key: flagLicense, usage: "license identifier such as MIT or Apache-2.0"},
+	{key: flagModulePrefix, usage: "import path prefix of Go modules (e.g. github.com/acme)", check: checkModulePrefix},
+}
+
+// lookupSetting finds a known setting by key
+func lookupSetting(key string) (setting, error) {
+	for _, s := range settings {
+		if s.key == key {
+			return s, nil
+		}
+	}
+	return setting{}, fmt.Errorf("unknown setting '%s'", key)
+}
+
+// settingKeys lists the keys of the known settings
+func settingKeys() []string {
+	keys := make([]string, len(settings))
+	for i, s := range settings {
+		keys[i] = s.key
+	}
+	return keys
+}
+
+// envName returns the environment variable of a setting (gcp-project is
+// CCIN_GCP_PROJECT)
+func envName(key string) string {
+	return envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
+
+// settingString returns a string flag of cmd, or the configured default when
+// the flag was not given
+func settingString(cmd *cobra.Command, flag string) string {
+	if value, _ := cmd.Flags().GetString(flag); cmd.Flags().Changed(flag) || !viper.IsSet(flag) {
+		return value
+	}
+	return viper.GetString(flag)
+}
+
+// settingBool returns a boolean flag of cmd, or the configured default when
+// the flag was not given
+func settingBool(cmd *cobra.Command, flag string) bool {
+	if value, _ := cmd.Flags().GetBool(flag); cmd.Flags().Changed(flag) || !viper.IsSet(flag) {
+		return value
+	}
+	return viper.GetBool(flag)
+}
+
+// settingTemplateSource returns --template (or --templates-dir) when given,
+// otherwise the configured template source
+func settingTemplateSource(cmd *cobra.Command) string {
+	if cmd.Flags().Changed(flagTemplate) || cmd.Flags().Changed(flagTemplates) {
+		return templateSource
+	}
+	return viper.GetString(flagTemplate)
+}
+
+// settingSource describes where the effective value of a setting comes from
+func settingSource(key string) string {
+	if _, ok := os.LookupEnv(envName(key)); ok {
+		return envName(key)
+	}
+	if viper.InConfig(key) {
+		return "config file"
+	}
+	return "not set"
+}
+
+// configCmd manages the config file
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "⚙️  Manage default settings (~/.ccin.yaml)",
+	Long: color.New(color.FgCyan, color.Bold).Sprint("⚙️  CONFIG\n\n") +
+		color.New(color.FgWhite).Sprint("Defaults for new projects are read from the config file ($HOME/.ccin.yaml or\n"+
+			"--config) and from CCIN_* environment variables (CCIN_GCP_PROJECT, ...).\n"+
+			"Flags win over environment variables, which win over the config file.\n\n") +
+		color.New(color.FgCyan).Sprint(exampleHeader) + color.New(color.FgWhite, color.Bold).Sprint("ccin config set module-prefix github.com/acme"),
+}
+
+// configListCmd prints every setting with its value and where it comes from
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "📋 List the settings and where their values come from",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		color.New(color.FgCyan, color.Bold).Printf("⚙️  Settings (%s)\n", configFilePath())
+		width := 0
+		for _, s := range settings {
+			width = max(width, len(s.key))
+		}
+		for _, s := range settings {
+			color.New(color.FgYellow).Printf("   %-*s ", width, s.key)
+			if source := settingSource(s.key); source != "not set" {
+				color.New(color.FgWhite).Print(viper.GetString(s.key))
+				color.New(color.FgHiBlack).Printf(" (%s)\n", source)
+			} else {
+				color.New(color.FgHiBlack).Printf("- %s\n", s.usage)
+			}
+		}
+	},
+}
+
+// configGetCmd prints the effective value of one setting
+var configGetCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "🔍 Print the value of a setting",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if _, err := lookupSetting(args[0]); err != nil {
+			color.New(color.FgRed, color.Bold).Printf(errorConfig, err)
+			color.New(color.FgYellow).Printf(helpConfig, strings.Join(settingKeys(), ", "))
+			return
+		}
+		fmt.Println(viper.GetString(args[0]))
+	},
+}
+
+// configSetCmd writes one setting to the config file
+var configSetCmd = &cobra.Command{
+	Use:   "set [key] [value]",
+	Short: "✏️  Write a setting to the config file",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		key, value := args[0], args[1]
+		s, err := lookupSetting(key)
+		if err == nil && s.check != nil {
+			err = s.check(value)
+		}
+		if err != nil {
+			color.New(color.FgRed, color.Bold).Printf(errorConfig, err)
+			color.New(color.FgYellow).Printf(helpConfig, strings.Join(settingKeys(), ", "))
+			return
+		}
+
+		path := configFilePath()
+		if err := writeSetting(path, key, value, key == flagGRPC); err != nil {
+			color.New(color.FgRed, color.Bold).Printf(errorConfig, err)
+			return
+		}
+		color.New(color.FgGreen).Printf("✅ %s = %s", key, value)
+		color.New(color.FgHiBlack).Printf(" (%s)\n", path)
+		if _, ok := os.LookupEnv(envName(key)); ok {
+			color.New(color.FgYellow).Printf("⚠️  %s is set and takes precedence\n", envName(key))
+		}
+	},
+}
+
+// configFilePath returns the config file in use: --config, or
+// $HOME/.ccin.yaml
+func configFilePath() string {
+	if cfgFile != "" {
+		return cfgFile
+	}
+	if used := viper.ConfigFileUsed(); used != "" {
+		return used
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return defaultConfigFile
+	}
+	return filepath.Join(home, defaultConfigFile)
+}
+
+// writeSetting sets a key of a YAML config file, keeping the other keys and
+// their comments; the file is created when missing
+func writeSetting(path, key, value string, boolean bool) error {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var doc yaml.Node
+	if len(bytes.TrimSpace(content)) > 0 {
+		if err := yaml.Unmarshal(content, &doc); err != nil {
+			return fmt.Errorf("invalid %s: %w", path, err)
+		}
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("invalid %s: expected key: value pairs", path)
+	}
+
+	tag := "!!str"
+	if boolean {
+		tag = "!!bool"
+	}
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+
+	found := false
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == key {
+			root.Content[i+1], found = node, true
+		}
+	}
+	if !found {
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, node)
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return err
+	}
+	return os.WriteFile(path, out.Bytes(), 0644)
+}
+
+// checkGenerator accepts the names of registered generators
+func checkGenerator(value string) error {
+	if !common.Registry.Has(value) {
+		return fmt.Errorf("unknown framework '%s' (available: %s)", value, strings.Join(common.Registry.List(), ", "))
+	}
+	return nil
+}
+
+// checkBool accepts true and false in the forms strconv understands
+func checkBool(value string) error {
+	if _, err := strconv.ParseBool(value); err != nil {
+		return fmt.Errorf("'%s' is not true or false", value)
+	}
+	return nil
+}
+
+// checkModulePrefix accepts import path prefixes such as github.com/acme
+func checkModulePrefix(value string) error {
+	if value == "" || strings.ContainsAny(value, " \t\\") || slices.Contains(strings.Split(strings.Trim(value, "/"), "/"), "") {
+		return fmt.Errorf("'%s' is not an import path prefix (e.g. github.com/acme)", value)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configListCmd, configGetCmd, configSetCmd)
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/chrisloarryn/ccin/internal/common"
+	"github.com/fatih/color"
+)
+
+// newConflictHandler builds the conflict handler for a --on-conflict strategy,
+// prompting on the terminal and reporting every existing file it resolves
+func newConflictHandler(strategy common.ConflictStrategy) common.ConflictHandler {
+	handler := common.ConflictHandler{
+		Strategy: strategy,
+		Resolved: printConflictResolution,
+	}
+	if strategy == common.ConflictPrompt {
+		handler.Prompt = newConflictPrompt(os.Stdin)
+	}
+	return handler
+}
+
+// newConflictPrompt asks how to resolve each existing file. An upper-case
+// answer applies to all remaining files.
+func newConflictPrompt(input io.Reader) func(path string, existing, rendered []byte) (common.ConflictStrategy, error) {
+	reader := bufio.NewReader(input)
+	answers := map[string]common.ConflictStrategy{
+		"o": common.ConflictOverwrite,
+		"s": common.ConflictSkip,
+		"m": common.ConflictMerge,
+	}
+	var remembered common.ConflictStrategy
+
+	return func(path string, existing, rendered []byte) (common.ConflictStrategy, error) {
+		if remembered != "" {
+			return remembered, nil
+		}
+
+		for {
+			color.New(color.FgYellow, color.Bold).Printf("\n⚠️  %s already exists and differs\n", path)
+			color.New(color.FgWhite).Print("   [o]verwrite, [s]kip, [m]erge, [d]iff (O/S/M for all remaining files): ")
+
+			answer, err := reader.ReadString('\n')
+			answer = strings.TrimSpace(answer)
+			if answer == "" && err != nil {
+				return "", fmt.Errorf("no answer for %s: %w", path, err)
+			}
+
+			if answer == "d" {
+				printDiff(common.UnifiedDiff("a/"+path, "b/"+path, existing, rendered))
+				continue
+			}
+			if strategy, ok := answers[strings.ToLower(answer)]; ok {
+				if answer != strings.ToLower(answer) {
+					remembered = strategy
+				}
+				return strategy, nil
+			}
+			color.New(color.FgRed).Printf("   Unknown answer '%s'\n", answer)
+		}
+	}
+}
+
+// printConflictResolution reports how an existing file was handled
+func printConflictResolution(path string, resolution common.ConflictStrategy, conflicts int) {
+	switch {
+	case resolution == common.ConflictSkip:
+		color.New(color.FgHiBlack).Printf("   = skipped     %s\n", path)
+	case resolution == common.ConflictOverwrite:
+		color.New(color.FgYellow).Printf("   ~ overwritten %s\n", path)
+	case conflicts > 0:
+		color.New(color.FgRed).Printf("   ! merged      %s (%d conflicts to resolve)\n", path, conflicts)
+	default:
+		color.New(color.FgGreen).Printf("   ~ merged      %s\n", path)
+	}
+}
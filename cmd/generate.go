@@ -1,16 +1,17 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"maps"
 	"path/filepath"
+	"strings"
 
 	"github.com/chrisloarryn/ccin/internal/common"
-	_ "github.com/chrisloarryn/ccin/internal/generators/go-fiber"
-	_ "github.com/chrisloarryn/ccin/internal/generators/go-gin"
-	_ "github.com/chrisloarryn/ccin/internal/generators/nestjs"
-	_ "github.com/chrisloarryn/ccin/internal/generators/swift-vapor"
+	_ "github.com/chrisloarryn/ccin/internal/generators"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // Constants for repeated strings to reduce duplication
@@ -21,318 +22,489 @@ const (
 	flagDomain     = "domain"
 	flagGCPProject = "gcp-project"
 	flagGRPC       = "grpc"
-
-	// Generator names
-	generatorNestJS     = "nestjs"
-	generatorGoGin      = "go-gin"
-	generatorGoFiber    = "go-fiber"
-	generatorSwiftVapor = "swift-vapor"
+	flagDatabase   = "database"
+	flagPort       = "port"
+	flagTemplates  = "templates-dir"
+	flagTemplate   = "template"
+	flagField      = "field"
+	flagEntity     = "entity"
+	flagDryRun     = "dry-run"
+	flagDiff       = "diff"
+	flagOnConflict = "on-conflict"
+	flagSet        = "set"
+	flagValues     = "values"
+	flagNoHooks    = "no-hooks"
+	flagFile       = "file"
+	flagOpenAPI    = "from-openapi"
+	flagProto      = "from-proto"
 
 	// Error messages
-	errorGeneratorNotFound  = "❌ Generator Error: %v\n"
 	errorGeneration         = "❌ Generation Error: %v\n"
 	errorInvalidProjectName = "❌ Invalid project name: %v\n"
+	errorInvalidDatabase    = "❌ Invalid database: %s does not support '%s'\n"
+	errorInvalidDomains     = "❌ Invalid domains: %v\n"
+	errorInvalidConflict    = "❌ Invalid --on-conflict: %v\n"
+	errorInvalidVars        = "❌ Invalid template variables: %v\n"
+	errorInvalidContract    = "❌ Invalid API contract: %v\n"
+	warnNotes               = "⚠️  Could not render the template messages: %v\n"
+	warnContract            = "⚠️  %s: %s\n"
+	warnProtoWithoutGRPC    = "⚠️  %s has no gRPC support; only the REST routes of %s are generated\n"
+	warnContractIDs         = "⚠️  %s: %s ids are %s, but %s generates %s ids; its routes reject the contract's ids until you change the model and handlers\n"
+	warnHooksFailed         = "\n⚠️  %d post-generation step(s) failed; the project was kept and the steps can be rerun by hand:\n"
 
 	// Help messages
-	helpAvailableGenerators = "💡 Available generators: nestjs, go-gin, go-fiber, swift-vapor"
-	helpCheckTemplates      = "💡 Check that all template files exist and are accessible"
+	helpCheckTemplates    = "💡 Check that all template files exist and are accessible"
+	helpTemplatesOverride = "🔧 If you use --template, make sure its root contains a directory per generator"
+	helpOutputNotEmpty    = "💡 Use --on-conflict=skip|overwrite|prompt|merge to generate into an existing directory, or --dry-run to preview"
+	helpVars              = "💡 Set template variables with --set name=value (repeatable) or --values file.yaml"
+	helpContract          = "💡 --from-openapi reads OpenAPI 3.0 and 3.1 documents; every schema an operation takes or returns becomes a domain"
+	helpProto             = "💡 --from-proto reads proto3 files; every message an rpc takes or returns, other than its Request and Response wrappers, becomes a domain"
+	helpArtifacts         = "💡 Add templates for the missing files to the template set, or to common/<file>.<set>.tpl in the template root"
+	helpHooks             = "💡 Use --no-hooks to generate without running post-generation steps"
 
 	// Info messages
 	msgProcessingTemplates = "📝 Processing templates..."
+	msgRolledBack          = "🧹 Nothing was written; %s was left as it was\n"
+	msgRunningHooks        = "\n🪝 Running post-generation steps..."
 	nextStepsHeader        = "\n🎯 Next steps:"
+	notesHeader            = "\n📌 Notes:"
 	readmeNote             = "\n📚 Check the README.md for complete documentation"
 	whatYouGetHeader       = "🎯 What you'll get:\n"
 	exampleHeader          = "📋 Example: "
 	domainLabel            = "📊 Domain: "
 	gcpProjectLabel        = "☁️  GCP Project: "
 	grpcEnabledMsg         = "🔗 gRPC support enabled"
+	dryRunMsg              = "🔍 Dry run: rendering in memory, nothing will be written"
 	cdCommand              = "   cd %s\n"
 
 	// Visual elements
 	separatorLine = "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"
 )
 
+// templateSource optionally overrides the embedded templates with a
+// directory, git repository or archive (see common.FetchTemplateSource)
+var templateSource string
+
+// addTemplateFlags registers --template and its older directory-only
+// spelling --templates-dir on a flag set
+func addTemplateFlags(flags *pflag.FlagSet, usage string) {
+	flags.StringVar(&templateSource, flagTemplate, "", usage+": a directory, git+<url>[//subdir][@ref] or a .tar.gz file/URL")
+	flags.StringVar(&templateSource, flagTemplates, "", usage+" (directory form of --template)")
+}
+
 // generateCmd represents the generate command
 var generateCmd = &cobra.Command{
-	Use:   "generate",
-	Short: "🎯 Generate production-ready CRUD applications",
-	Long: color.New(color.FgCyan, color.Bold).Sprint("🚀 GENERATE COMMAND") + color.New(color.FgWhite).Sprint(" - Create complete CRUD applications\n\n") +
-		color.New(color.FgGreen).Sprint("🎯 Available Frameworks:\n") +
-		color.New(color.FgYellow).Sprint("   📦 nestjs") + color.New(color.FgHiBlack).Sprint("   - NestJS with TypeScript, MongoDB, Swagger, Jest\n") +
-		color.New(color.FgYellow).Sprint("   🟢 go-gin") + color.New(color.FgHiBlack).Sprint("  - Go with Gin framework, PostgreSQL, REST/gRPC\n") +
-		color.New(color.FgYellow).Sprint("   ⚡ go-fiber") + color.New(color.FgHiBlack).Sprint(" - Go with Fiber framework (ultra-fast), PostgreSQL, REST/gRPC\n") +
-		color.New(color.FgYellow).Sprint("   🐦 swift-vapor") + color.New(color.FgHiBlack).Sprint(" - Swift with Vapor framework, REST/gRPC\n\n") +
-		color.New(color.FgMagenta).Sprint("💡 Examples:\n") +
-		color.New(color.FgHiBlack).Sprint("   ccin generate nestjs my-api --domain user --gcp-project my-project\n") +
-		color.New(color.FgHiBlack).Sprint("   ccin generate go-gin orders-api --domain order --grpc\n") +
-		color.New(color.FgHiBlack).Sprint("   ccin generate go-fiber products-api --domain product --gcp-project prod\n") +
-		color.New(color.FgHiBlack).Sprint("   ccin generate swift-vapor catalog-api --domain product --grpc\n\n") +
-		color.New(color.FgCyan).Sprint("🔧 Use: ") + color.New(color.FgWhite, color.Bold).Sprint("ccin generate <framework> <project-name> [flags]"),
+	Use:     "generate",
+	Short:   "🎯 Generate production-ready CRUD applications",
 	Aliases: []string{"gen", "g"},
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if specFile, _ := cmd.Flags().GetString(flagFile); specFile != "" {
+			cmd.SilenceUsage, cmd.SilenceErrors = true, true
+			return runSpec(cmd, specFile)
+		}
+		return runWizard(cmd, args) // without a framework, the wizard of `ccin new` asks for everything
+	},
 }
 
-// nestjsCmd generates NestJS CRUD
-var nestjsCmd = &cobra.Command{
-	Use:   "nestjs [project-name]",
-	Short: "📦 Generate NestJS CRUD application",
-	Long: color.New(color.FgMagenta, color.Bold).Sprint("📦 NESTJS GENERATOR\n\n") +
-		color.New(color.FgGreen).Sprint(whatYouGetHeader) +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("NestJS") + color.New(color.FgHiBlack).Sprint(" framework with TypeScript\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("MongoDB") + color.New(color.FgHiBlack).Sprint(" with Mongoose ODM\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("Swagger/OpenAPI") + color.New(color.FgHiBlack).Sprint(" automatic documentation\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("GCP Metrics") + color.New(color.FgHiBlack).Sprint(" interceptors (optional)\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("Docker") + color.New(color.FgHiBlack).Sprint(" multi-stage production build\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("Jest") + color.New(color.FgHiBlack).Sprint(" testing configuration\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("ESLint + Prettier") + color.New(color.FgHiBlack).Sprint(" code quality\n\n") +
-		color.New(color.FgCyan).Sprint(exampleHeader) + color.New(color.FgWhite, color.Bold).Sprint("ccin generate nestjs my-api --domain user --gcp-project my-project"),
-	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		projectName := args[0]
-
-		// Validate project name
-		if err := validateProjectName(projectName); err != nil {
-			color.New(color.FgRed, color.Bold).Printf(errorInvalidProjectName, err)
-			color.New(color.FgYellow).Println("💡 Use a descriptive name like 'my-api', 'user-service', etc.")
-			return
-		}
+// generateLongHelp builds the generate help text from the registered generators
+func generateLongHelp() string {
+	names := common.Registry.List()
 
-		domainName, _ := cmd.Flags().GetString(flagDomain)
-		gcpProject, _ := cmd.Flags().GetString(flagGCPProject)
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
 
-		if domainName == "" {
-			domainName = defaultDomain
+	help := color.New(color.FgCyan, color.Bold).Sprint("🚀 GENERATE COMMAND") + color.New(color.FgWhite).Sprint(" - Create complete CRUD applications\n\n") +
+		color.New(color.FgGreen).Sprint("🎯 Available Frameworks:\n")
+	examples := ""
+	for _, name := range names {
+		generator, _ := common.Registry.Get(name)
+		metadata := generator.GetMetadata()
+		help += color.New(color.FgYellow).Sprintf("   %s %-*s", metadata.Icon, width, name) + color.New(color.FgHiBlack).Sprintf(" - %s\n", metadata.Summary)
+		if metadata.Example != "" {
+			examples += color.New(color.FgHiBlack).Sprintf("   %s\n", metadata.Example)
 		}
+	}
 
-		// Print header
-		printProjectHeader("NestJS", projectName, domainName, gcpProject, false)
+	return help + "\n" +
+		color.New(color.FgMagenta).Sprint("💡 Examples:\n") + examples + "\n" +
+		color.New(color.FgCyan).Sprint("🔧 Use: ") + color.New(color.FgWhite, color.Bold).Sprint("ccin generate <framework> <project-name> [flags]") +
+		color.New(color.FgCyan).Sprint("\n📄 Or from a spec file: ") + color.New(color.FgWhite, color.Bold).Sprint("ccin generate -f service.yaml")
+}
 
-		// Get generator
-		generator, err := common.Registry.Get(generatorNestJS)
-		if err != nil {
-			handleGeneratorError(generatorNestJS, err)
-			return
-		}
+// newGeneratorCommand builds the generate subcommand for a registered generator
+func newGeneratorCommand(generator common.Generator) *cobra.Command {
+	name := generator.GetName()
+	metadata := generator.GetMetadata()
 
-		// Prepare configuration
-		config := &common.GeneratorConfig{
-			ProjectName:  projectName,
-			DomainName:   domainName,
-			GCPProject:   gcpProject,
-			OutputDir:    projectName,
-			TemplateDir:  filepath.Join("templates", generatorNestJS),
-			WithGRPC:     false,
-			DatabaseType: "mongodb",
-			Port:         "3000",
-		}
+	long := color.New(color.FgCyan, color.Bold).Sprintf("%s %s GENERATOR\n\n", metadata.Icon, strings.ToUpper(metadata.DisplayName)) +
+		color.New(color.FgGreen).Sprint(whatYouGetHeader)
+	for _, feature := range metadata.Features {
+		long += color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint(feature.Name) + color.New(color.FgHiBlack).Sprintf(" %s\n", feature.Detail)
+	}
+	if metadata.Example != "" {
+		long += "\n" + color.New(color.FgCyan).Sprint(exampleHeader) + color.New(color.FgWhite, color.Bold).Sprint(metadata.Example)
+	}
 
-		// Generate project
-		color.New(color.FgBlue).Println(msgProcessingTemplates)
-		if err := generator.Generate(config); err != nil {
-			handleGenerationError(err)
-			return
-		}
+	cmd := &cobra.Command{
+		Use:   name + " [project-name]",
+		Short: fmt.Sprintf("%s Generate %s CRUD application", metadata.Icon, metadata.DisplayName),
+		Long:  long,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage, cmd.SilenceErrors = true, true
+			return runGenerator(cmd, generator, args[0])
+		},
+	}
 
-		// Success message
-		printSuccessMessage("NestJS", projectName, []string{"npm install", "npm run start:dev"})
-	},
+	cmd.Flags().StringSliceP(flagDomain, "d", nil, "Domain names for the service, comma separated or repeated (e.g., order,customer,invoice)")
+	cmd.Flags().StringP(flagGCPProject, "p", "", "GCP Project ID for metrics integration")
+	cmd.Flags().String(flagPort, metadata.DefaultPort, "HTTP port of the generated service")
+	cmd.Flags().String(flagDatabase, metadata.DefaultDatabase(), fmt.Sprintf("Database type (%s)", strings.Join(metadata.SupportedDatabases, ", ")))
+	cmd.Flags().StringArray(flagField, nil, fmt.Sprintf("Entity field as name:type[:unique][:optional], repeatable (types: %s)", strings.Join(common.FieldTypes, ", ")))
+	cmd.Flags().String(flagEntity, "", "YAML file describing one entity (name, fields) or several (entities)")
+	cmd.Flags().String(flagOpenAPI, "", "OpenAPI 3.0/3.1 document whose schemas become domains and whose operations become the routes")
+	cmd.Flags().String(flagProto, "", "proto3 file whose messages become domains, with gRPC stubs for its services and REST routes for its rpcs")
+	cmd.MarkFlagsMutuallyExclusive(flagOpenAPI, flagProto)
+	if metadata.SupportsGRPC {
+		cmd.Flags().BoolP(flagGRPC, "g", false, "Include gRPC support")
+	}
+
+	return cmd
 }
 
-// goGinCmd generates Go Gin CRUD
-var goGinCmd = &cobra.Command{
-	Use:   "go-gin [project-name]",
-	Short: "🟢 Generate Go Gin CRUD application",
-	Long: color.New(color.FgGreen, color.Bold).Sprint("🟢 GO GIN GENERATOR\n\n") +
-		color.New(color.FgGreen).Sprint(whatYouGetHeader) +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("Go 1.25.1") + color.New(color.FgHiBlack).Sprint(" with Gin framework\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("PostgreSQL") + color.New(color.FgHiBlack).Sprint(" with GORM\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("REST API") + color.New(color.FgHiBlack).Sprint(" with JSON responses\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("gRPC") + color.New(color.FgHiBlack).Sprint(" support (optional with --grpc)\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("Clean Architecture") + color.New(color.FgHiBlack).Sprint(" layers\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("GCP Metrics") + color.New(color.FgHiBlack).Sprint(" middleware (optional)\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("Docker") + color.New(color.FgHiBlack).Sprint(" multi-stage production build\n\n") +
-		color.New(color.FgCyan).Sprint(exampleHeader) + color.New(color.FgWhite, color.Bold).Sprint("ccin generate go-gin orders-api --domain order --grpc"),
-	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		projectName := args[0]
-
-		// Validate project name
-		if err := validateProjectName(projectName); err != nil {
-			color.New(color.FgRed, color.Bold).Printf(errorInvalidProjectName, err)
-			color.New(color.FgYellow).Println("💡 Use a descriptive name like 'orders-api', 'inventory-service', etc.")
-			return
+// runGenerator validates the flags, prepares the configuration and runs a
+// generator. Errors are printed with help on fixing them before they are returned.
+func runGenerator(cmd *cobra.Command, generator common.Generator, projectName string) error {
+	metadata := generator.GetMetadata()
+
+	// Validate project name
+	if err := validateProjectName(projectName); err != nil {
+		color.New(color.FgRed, color.Bold).Printf(errorInvalidProjectName, err)
+		if len(metadata.NameExamples) > 0 {
+			color.New(color.FgYellow).Printf("💡 Use a descriptive name like '%s', etc.\n", strings.Join(metadata.NameExamples, "', '"))
 		}
+		return reported(err)
+	}
 
-		domainName, _ := cmd.Flags().GetString(flagDomain)
-		gcpProject, _ := cmd.Flags().GetString(flagGCPProject)
-		grpc, _ := cmd.Flags().GetBool(flagGRPC)
+	domainNames, _ := cmd.Flags().GetStringSlice(flagDomain)
+	gcpProject := settingString(cmd, flagGCPProject)
+	port, _ := cmd.Flags().GetString(flagPort)
+	database, _ := cmd.Flags().GetString(flagDatabase)
+	grpc := false
+	if metadata.SupportsGRPC {
+		grpc = settingBool(cmd, flagGRPC)
+	}
 
-		if domainName == "" {
-			domainName = defaultDomain
-		}
+	contract, contractFile, err := loadContract(cmd, metadata)
+	if err != nil {
+		return reported(err)
+	}
+	if contract != nil && contract.Proto != nil && metadata.SupportsGRPC {
+		grpc = true
+	}
 
-		// Print header
-		printProjectHeader("Go Gin", projectName, domainName, gcpProject, grpc)
+	domains, err := resolveDomains(cmd, common.ParseDomainNames(domainNames), contract)
+	if err != nil {
+		color.New(color.FgRed, color.Bold).Printf(errorInvalidDomains, err)
+		color.New(color.FgYellow).Println("💡 Example: --domain order,customer --field price:decimal --field sku:string:unique")
+		return reported(err)
+	}
 
-		// Get generator
-		generator, err := common.Registry.Get(generatorGoGin)
-		if err != nil {
-			handleGeneratorError(generatorGoGin, err)
-			return
-		}
+	vars, err := resolveVars(cmd)
+	if err != nil {
+		color.New(color.FgRed, color.Bold).Printf(errorInvalidVars, err)
+		color.New(color.FgYellow).Println(helpVars)
+		return reported(err)
+	}
 
-		// Prepare configuration
-		config := &common.GeneratorConfig{
-			ProjectName:  projectName,
-			DomainName:   domainName,
-			GCPProject:   gcpProject,
-			OutputDir:    projectName,
-			TemplateDir:  filepath.Join("templates", generatorGoGin),
-			WithGRPC:     grpc,
-			DatabaseType: "postgresql",
-			Port:         "8080",
-		}
+	onConflict, _ := cmd.Flags().GetString(flagOnConflict)
+	strategy, err := common.ParseConflictStrategy(onConflict)
+	if err != nil {
+		color.New(color.FgRed, color.Bold).Printf(errorInvalidConflict, err)
+		return reported(err)
+	}
 
-		// Generate project
-		color.New(color.FgBlue).Println(msgProcessingTemplates)
-		if err := generator.Generate(config); err != nil {
-			handleGenerationError(err)
-			return
-		}
+	// Validate database
+	if !metadata.SupportsDatabase(database) {
+		color.New(color.FgRed, color.Bold).Printf(errorInvalidDatabase, metadata.DisplayName, database)
+		color.New(color.FgYellow).Printf("💡 Supported databases: %s\n", strings.Join(metadata.SupportedDatabases, ", "))
+		return reported(fmt.Errorf("%s does not support the database '%s'", metadata.DisplayName, database))
+	}
 
-		// Success message
-		printSuccessMessage("Go Gin", projectName, []string{"go mod tidy", "make dev"})
-	},
+	// Prepare configuration
+	config := &common.GeneratorConfig{
+		ProjectName:    projectName,
+		Domains:        domains,
+		GCPProject:     gcpProject,
+		OutputDir:      projectName,
+		TemplateSource: settingTemplateSource(cmd),
+		WithGRPC:       grpc,
+		DatabaseType:   database,
+		Port:           port,
+		ModulePrefix:   settingString(cmd, flagModulePrefix),
+		Author:         settingString(cmd, flagAuthor),
+		License:        settingString(cmd, flagLicense),
+		Vars:           vars,
+		Conflicts:      newConflictHandler(strategy),
+	}
+	if contract != nil {
+		config.Contract = filepath.Base(contractFile)
+		config.Proto = contract.Proto
+	}
+	return runGeneration(cmd, generator, config)
 }
 
-// goFiberCmd generates Go Fiber CRUD
-var goFiberCmd = &cobra.Command{
-	Use:   "go-fiber [project-name]",
-	Short: "⚡ Generate Go Fiber CRUD application",
-	Long: color.New(color.FgYellow, color.Bold).Sprint("⚡ GO FIBER GENERATOR\n\n") +
-		color.New(color.FgGreen).Sprint(whatYouGetHeader) +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("Go 1.25.1") + color.New(color.FgHiBlack).Sprint(" with Fiber framework (ultra-fast!)\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("PostgreSQL") + color.New(color.FgHiBlack).Sprint(" with GORM\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("REST API") + color.New(color.FgHiBlack).Sprint(" with lightning-fast JSON responses\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("gRPC") + color.New(color.FgHiBlack).Sprint(" support (optional with --grpc)\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("Clean Architecture") + color.New(color.FgHiBlack).Sprint(" layers\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("CORS") + color.New(color.FgHiBlack).Sprint(" middleware included\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("Docker") + color.New(color.FgHiBlack).Sprint(" multi-stage production build\n\n") +
-		color.New(color.FgCyan).Sprint(exampleHeader) + color.New(color.FgWhite, color.Bold).Sprint("ccin generate go-fiber products-api --domain product --gcp-project prod"),
-	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		projectName := args[0]
-
-		// Validate project name
-		if err := validateProjectName(projectName); err != nil {
-			color.New(color.FgRed, color.Bold).Printf(errorInvalidProjectName, err)
-			color.New(color.FgYellow).Println("💡 Use a descriptive name like 'products-api', 'notification-service', etc.")
-			return
+// loadContract reads the API contract given with --from-openapi or
+// --from-proto and prints what it leaves out. The contract is nil when
+// neither flag is given; a contract that cannot be used is printed and
+// returned as the error.
+func loadContract(cmd *cobra.Command, metadata common.GeneratorMetadata) (contract *common.Contract, file string, err error) {
+	file, _ = cmd.Flags().GetString(flagOpenAPI)
+	load, help := common.LoadOpenAPI, helpContract
+	if protoFile, _ := cmd.Flags().GetString(flagProto); protoFile != "" {
+		file, load, help = protoFile, common.LoadProto, helpProto
+	}
+	if file == "" {
+		return nil, "", nil
+	}
+
+	contract, err = load(file)
+	if err != nil {
+		color.New(color.FgRed, color.Bold).Printf(errorInvalidContract, err)
+		color.New(color.FgYellow).Println(help)
+		return nil, "", err
+	}
+	for _, warning := range contract.Warnings {
+		color.New(color.FgYellow).Printf(warnContract, filepath.Base(file), warning)
+	}
+	for _, domain := range contract.Domains {
+		if idType := contract.IDTypes[domain.Name]; idType != "" && idType != metadata.IDType {
+			color.New(color.FgYellow).Printf(warnContractIDs, filepath.Base(file), domain.Name, idType, metadata.DisplayName, metadata.IDType)
 		}
+	}
+	if contract.Proto != nil && !metadata.SupportsGRPC {
+		color.New(color.FgYellow).Printf(warnProtoWithoutGRPC, metadata.DisplayName, filepath.Base(file))
+	}
+	return contract, file, nil
+}
 
-		domainName, _ := cmd.Flags().GetString(flagDomain)
-		gcpProject, _ := cmd.Flags().GetString(flagGCPProject)
-		grpc, _ := cmd.Flags().GetBool(flagGRPC)
+// runGeneration generates a configured project, or previews it with
+// --dry-run, and runs the post-generation steps unless --no-hooks is given
+func runGeneration(cmd *cobra.Command, generator common.Generator, config *common.GeneratorConfig) error {
+	metadata := generator.GetMetadata()
 
-		if domainName == "" {
-			domainName = defaultDomain
-		}
+	// Print header
+	printProjectHeader(metadata.DisplayName, config.ProjectName, config.Domains, config.GCPProject, config.WithGRPC)
 
-		// Print header
-		printProjectHeader("Go Fiber", projectName, domainName, gcpProject, grpc)
+	if dryRun, _ := cmd.Flags().GetBool(flagDryRun); dryRun {
+		showDiff, _ := cmd.Flags().GetBool(flagDiff)
+		return runDryRun(generator, config, showDiff)
+	}
 
-		// Get generator
-		generator, err := common.Registry.Get(generatorGoFiber)
-		if err != nil {
-			handleGeneratorError(generatorGoFiber, err)
-			return
-		}
+	// Generate project
+	color.New(color.FgBlue).Println(msgProcessingTemplates)
+	if err := common.GenerateProject(generator, config); err != nil {
+		handleGenerationError(err)
+		color.New(color.FgHiBlack).Printf(msgRolledBack, config.OutputDir)
+		return reported(err)
+	}
 
-		// Prepare configuration
-		config := &common.GeneratorConfig{
-			ProjectName:  projectName,
-			DomainName:   domainName,
-			GCPProject:   gcpProject,
-			OutputDir:    projectName,
-			TemplateDir:  filepath.Join("templates", generatorGoFiber),
-			WithGRPC:     grpc,
-			DatabaseType: "postgresql",
-			Port:         "3000",
-		}
+	// Post-generation steps: git, dependencies and formatters
+	nextSteps := common.NextSteps(generator, config)
+	var hookResults []common.HookResult
+	if noHooks, _ := cmd.Flags().GetBool(flagNoHooks); !noHooks {
+		hookResults = runHooks(generator, config)
+		nextSteps = pendingSteps(nextSteps, hookResults)
+	}
+
+	// Messages the template set shows after generation
+	notes, err := common.GenerationNotes(generator, config)
+	if err != nil {
+		color.New(color.FgYellow).Printf(warnNotes, err)
+	}
 
-		// Generate project
-		color.New(color.FgBlue).Println(msgProcessingTemplates)
-		if err := generator.Generate(config); err != nil {
-			handleGenerationError(err)
-			return
+	// Success message
+	printSuccessMessage(metadata.DisplayName, config.ProjectName, nextSteps, notes)
+	printFailedHooks(hookResults)
+	return nil
+}
+
+// runDryRun renders the project in memory and prints what generation would
+// create, modify or leave unchanged in the output directory
+func runDryRun(generator common.Generator, config *common.GeneratorConfig, showDiff bool) error {
+	color.New(color.FgBlue).Println(dryRunMsg)
+
+	files, err := common.RenderProject(generator, config)
+	if err != nil {
+		handleGenerationError(err)
+		return reported(err)
+	}
+	plan, err := common.PlanFiles(config.OutputDir, files)
+	if err != nil {
+		handleGenerationError(err)
+		return reported(err)
+	}
+
+	printPlan(config.OutputDir, plan, showDiff)
+	return nil
+}
+
+// printPlan lists the planned files with their sizes, optionally followed by
+// a unified diff for every file that would change
+func printPlan(outputDir string, plan []common.PlannedFile, showDiff bool) {
+	symbols := map[common.FileStatus]*color.Color{
+		common.StatusCreate:    color.New(color.FgGreen),
+		common.StatusModify:    color.New(color.FgYellow),
+		common.StatusUnchanged: color.New(color.FgHiBlack),
+	}
+	prefixes := map[common.FileStatus]string{
+		common.StatusCreate:    "+",
+		common.StatusModify:    "~",
+		common.StatusUnchanged: "=",
+	}
+
+	color.New(color.FgCyan, color.Bold).Printf("\n📋 Plan for %s:\n", outputDir)
+	counts := make(map[common.FileStatus]int)
+	for _, file := range plan {
+		counts[file.Status]++
+		symbols[file.Status].Printf("   %s %-9s ", prefixes[file.Status], file.Status)
+		color.New(color.FgWhite).Print(filepath.ToSlash(file.Path))
+		color.New(color.FgHiBlack).Printf(" (%s)\n", formatSize(len(file.Content)))
+	}
+
+	if showDiff {
+		for _, file := range plan {
+			if file.Status == common.StatusUnchanged {
+				continue
+			}
+			fmt.Println()
+			printDiff(file.Diff())
 		}
+	}
 
-		// Success message
-		printSuccessMessage("Go Fiber", projectName, []string{"go mod tidy", "make dev"})
-	},
+	color.New(color.FgCyan).Printf("\n📊 %d to create, %d to modify, %d unchanged\n",
+		counts[common.StatusCreate], counts[common.StatusModify], counts[common.StatusUnchanged])
+	color.New(color.FgHiBlack).Println("💡 Run again without --dry-run to write the files")
 }
 
-// swiftVaporCmd generates Swift Vapor CRUD
-var swiftVaporCmd = &cobra.Command{
-	Use:   "swift-vapor [project-name]",
-	Short: "🐦 Generate Swift Vapor backend (REST + gRPC)",
-	Long: color.New(color.FgBlue, color.Bold).Sprint("🐦 SWIFT VAPOR GENERATOR\n\n") +
-		color.New(color.FgGreen).Sprint(whatYouGetHeader) +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("Swift 6.1.2") + color.New(color.FgHiBlack).Sprint(" with Vapor 4 framework\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("REST API") + color.New(color.FgHiBlack).Sprint(" with clean architecture layers (Controllers/Services/Models)\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("gRPC") + color.New(color.FgHiBlack).Sprint(" scaffolding (optional with --grpc)\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("Docker") + color.New(color.FgHiBlack).Sprint(" multi-stage production build\n\n") +
-		color.New(color.FgCyan).Sprint(exampleHeader) + color.New(color.FgWhite, color.Bold).Sprint("ccin generate swift-vapor catalog-api --domain product --grpc"),
-	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		projectName := args[0]
-
-		// Validate project name
-		if err := validateProjectName(projectName); err != nil {
-			color.New(color.FgRed, color.Bold).Printf(errorInvalidProjectName, err)
-			color.New(color.FgYellow).Println("💡 Use a descriptive name like 'catalog-api', 'payment-service', etc.")
-			return
+// printDiff prints a unified diff with added and removed lines colored
+func printDiff(diff string) {
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			color.New(color.FgWhite, color.Bold).Print(line)
+		case strings.HasPrefix(line, "@@"):
+			color.New(color.FgCyan).Print(line)
+		case strings.HasPrefix(line, "+"):
+			color.New(color.FgGreen).Print(line)
+		case strings.HasPrefix(line, "-"):
+			color.New(color.FgRed).Print(line)
+		default:
+			fmt.Print(line)
 		}
+	}
+}
 
-		domainName, _ := cmd.Flags().GetString(flagDomain)
-		gcpProject, _ := cmd.Flags().GetString(flagGCPProject)
-		grpc, _ := cmd.Flags().GetBool(flagGRPC)
+// formatSize formats a file size in bytes for humans
+func formatSize(size int) string {
+	if size < 1024 {
+		return fmt.Sprintf("%d B", size)
+	}
+	return fmt.Sprintf("%.1f KB", float64(size)/1024)
+}
 
-		if domainName == "" {
-			domainName = defaultDomain
+// resolveDomains combines the domains of an API contract (if any), the
+// --entity file, --domain names and --field flags into the project's
+// domains. Domains of the contract come first, then entities from the file,
+// followed by --domain names neither defines; a single unnamed entity takes
+// the first --domain name. Fields from flags are appended to every domain.
+func resolveDomains(cmd *cobra.Command, names []string, contract *common.Contract) ([]common.Domain, error) {
+	entityFile, _ := cmd.Flags().GetString(flagEntity)
+	fieldSpecs, _ := cmd.Flags().GetStringArray(flagField)
+
+	var domains []common.Domain
+	if contract != nil {
+		domains = append(domains, contract.Domains...)
+	}
+	if entityFile != "" {
+		entities, err := common.LoadEntityFile(entityFile)
+		if err != nil {
+			return nil, err
+		}
+		if len(entities) == 1 && entities[0].Name == "" {
+			if len(names) == 0 {
+				return nil, fmt.Errorf("entity in %s has no name; add one or pass --domain", entityFile)
+			}
+			entities[0].Name, names = names[0], names[1:]
 		}
+		domains = append(domains, entities...)
+	}
 
-		// Print header
-		printProjectHeader("Swift Vapor", projectName, domainName, gcpProject, grpc)
+	for _, name := range names {
+		if !containsDomain(domains, name) {
+			domains = append(domains, common.Domain{Name: name})
+		}
+	}
+	if len(domains) == 0 {
+		domains = []common.Domain{{Name: defaultDomain}}
+	}
 
-		// Get generator
-		generator, err := common.Registry.Get(generatorSwiftVapor)
+	var extra []common.Field
+	for _, spec := range fieldSpecs {
+		field, err := common.ParseField(spec)
 		if err != nil {
-			handleGeneratorError(generatorSwiftVapor, err)
-			return
+			return nil, err
 		}
+		extra = append(extra, field)
+	}
 
-		// Prepare configuration
-		config := &common.GeneratorConfig{
-			ProjectName:  projectName,
-			DomainName:   domainName,
-			GCPProject:   gcpProject,
-			OutputDir:    projectName,
-			TemplateDir:  filepath.Join("templates", generatorSwiftVapor),
-			WithGRPC:     grpc,
-			DatabaseType: "none",
-			Port:         "8080",
+	for i := range domains {
+		domains[i].Fields = append(domains[i].Fields, extra...)
+		if len(domains[i].Fields) == 0 {
+			domains[i].Fields = common.DefaultFields()
 		}
+	}
 
-		// Generate project
-		color.New(color.FgBlue).Println(msgProcessingTemplates)
-		if err := generator.Generate(config); err != nil {
-			handleGenerationError(err)
-			return
+	return domains, common.ValidateDomains(domains)
+}
+
+// resolveVars reads the template variables from the --values file and the
+// --set assignments, which take precedence
+func resolveVars(cmd *cobra.Command) (map[string]any, error) {
+	valuesFile, _ := cmd.Flags().GetString(flagValues)
+	assignments, _ := cmd.Flags().GetStringArray(flagSet)
+
+	vars := make(map[string]any)
+	if valuesFile != "" {
+		values, err := common.LoadValuesFile(valuesFile)
+		if err != nil {
+			return nil, err
 		}
+		maps.Copy(vars, values)
+	}
+	set, err := common.ParseVars(assignments)
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(vars, set)
 
-		// Success message
-		printSuccessMessage("Swift Vapor", projectName, []string{"swift build", "swift run"})
-	},
+	if len(vars) == 0 {
+		return nil, nil
+	}
+	return vars, nil
+}
+
+// containsDomain reports whether a domain with the given name (case-insensitive) exists
+func containsDomain(domains []common.Domain, name string) bool {
+	for _, domain := range domains {
+		if strings.EqualFold(domain.Name, name) {
+			return true
+		}
+	}
+	return false
 }
 
 // Helper functions for validation and common operations
@@ -347,11 +519,14 @@ func validateProjectName(name string) error {
 	return nil
 }
 
-func printProjectHeader(framework, projectName, domain, gcpProject string, grpc bool) {
+func printProjectHeader(framework, projectName string, domains []common.Domain, gcpProject string, grpc bool) {
 	color.New(color.FgCyan, color.Bold).Printf("\n🚀 Generating %s CRUD project: ", framework)
 	color.New(color.FgWhite, color.Bold).Printf("%s\n", projectName)
-	color.New(color.FgYellow).Printf(domainLabel)
-	color.New(color.FgWhite).Printf("%s\n", domain)
+	for _, domain := range domains {
+		color.New(color.FgYellow).Printf(domainLabel)
+		color.New(color.FgWhite).Printf("%s ", domain.Name)
+		color.New(color.FgHiBlack).Printf("(%s)\n", common.Fields(domain.Fields))
+	}
 	if gcpProject != "" {
 		color.New(color.FgMagenta).Printf(gcpProjectLabel)
 		color.New(color.FgWhite).Printf("%s\n", gcpProject)
@@ -362,43 +537,72 @@ func printProjectHeader(framework, projectName, domain, gcpProject string, grpc
 	color.New(color.FgHiBlack).Println(separatorLine)
 }
 
-func handleGeneratorError(generatorName string, err error) {
-	color.New(color.FgRed, color.Bold).Printf(errorGeneratorNotFound, err)
-	color.New(color.FgYellow).Println(helpAvailableGenerators)
-	color.New(color.FgHiBlack).Printf("🔍 Looking for generator: %s\n", generatorName)
-}
-
 func handleGenerationError(err error) {
 	color.New(color.FgRed, color.Bold).Printf(errorGeneration, err)
+	if errors.Is(err, common.ErrOutputNotEmpty) {
+		color.New(color.FgYellow).Println(helpOutputNotEmpty)
+		return
+	}
+	if errors.Is(err, common.ErrInvalidVariables) {
+		color.New(color.FgYellow).Println(helpVars)
+		return
+	}
+	if errors.Is(err, common.ErrMissingArtifacts) {
+		color.New(color.FgYellow).Println(helpArtifacts)
+		return
+	}
 	color.New(color.FgYellow).Println(helpCheckTemplates)
-	color.New(color.FgHiBlack).Println("🔧 Make sure you're running from the correct directory")
+	color.New(color.FgHiBlack).Println(helpTemplatesOverride)
 }
 
-func printSuccessMessage(framework, projectName string, commands []string) {
+func printSuccessMessage(framework, projectName string, commands, notes []string) {
 	color.New(color.FgGreen, color.Bold).Printf("\n✅ %s project '%s' generated successfully!\n", framework, projectName)
 	color.New(color.FgCyan).Println(nextStepsHeader)
 	color.New(color.FgWhite).Printf(cdCommand, projectName)
 	for _, cmd := range commands {
 		color.New(color.FgWhite).Printf("   %s\n", cmd)
 	}
+	if len(notes) > 0 {
+		color.New(color.FgCyan).Println(notesHeader)
+		for _, note := range notes {
+			color.New(color.FgWhite).Printf("   • %s\n", note)
+		}
+	}
 	color.New(color.FgHiBlack).Println(readmeNote)
 }
 
 func init() {
 	rootCmd.AddCommand(generateCmd)
-	generateCmd.AddCommand(nestjsCmd)
-	generateCmd.AddCommand(goGinCmd)
-	generateCmd.AddCommand(goFiberCmd)
-	generateCmd.AddCommand(swiftVaporCmd)
+	generateCmd.Long = generateLongHelp()
 
-	// Add flags for all generate commands
-	for _, cmd := range []*cobra.Command{nestjsCmd, goGinCmd, goFiberCmd, swiftVaporCmd} {
-		cmd.Flags().StringP(flagDomain, "d", "", "Domain name for the service (e.g., user, product, order)")
-		cmd.Flags().StringP(flagGCPProject, "p", "", "GCP Project ID for metrics integration")
+	// One subcommand per registered generator
+	for _, name := range common.Registry.List() {
+		generator, err := common.Registry.Get(name)
+		if err != nil {
+			continue
+		}
+		generateCmd.AddCommand(newGeneratorCommand(generator))
 	}
 
-	// Add gRPC flag for Go and Swift Vapor commands
-	goGinCmd.Flags().BoolP(flagGRPC, "g", false, "Include gRPC support")
-	goFiberCmd.Flags().BoolP(flagGRPC, "g", false, "Include gRPC support")
-	swiftVaporCmd.Flags().BoolP(flagGRPC, "g", false, "Include gRPC support")
+	addGenerationFlags(generateCmd.PersistentFlags())
+	generateCmd.Flags().StringP(flagFile, "f", "", "Service spec file declaring the whole project (see schema/service.schema.json)")
+}
+
+// addGenerationFlags registers the flags shared by every generator on a flag
+// set (the persistent flags of generate, and the flags of new)
+func addGenerationFlags(flags *pflag.FlagSet) {
+	// Template authors and teams with their own variants can render from
+	// a local checkout, a git repository or an archive
+	addTemplateFlags(flags, "Read templates from this source instead of the embedded ones")
+	flags.StringArray(flagSet, nil, "Template variable as key=value, available as {{.Vars.key}}; repeatable")
+	flags.String(flagValues, "", "YAML file of template variables (--set takes precedence)")
+	flags.String(flagAuthor, "", "Project author written to package manifests")
+	flags.String(flagLicense, "", "License identifier of the project (e.g. MIT, Apache-2.0)")
+	flags.String(flagModulePrefix, "", "Import path prefix of the Go module (e.g. github.com/acme gives github.com/acme/<project-name>)")
+	flags.Bool(flagNoHooks, false, "Skip the post-generation steps (git init, dependency install, formatters)")
+	flags.String(flagOnConflict, string(common.ConflictAbort), "What to do with existing files: abort (refuse a non-empty directory), skip, overwrite, prompt or merge")
+
+	// Reviewers can see the scaffold before anything lands on disk
+	flags.Bool(flagDryRun, false, "Render in memory and list the files that would be created, modified or left unchanged")
+	flags.Bool(flagDiff, false, "With --dry-run, also print a unified diff for every file that would change")
 }
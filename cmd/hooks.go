@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/chrisloarryn/ccin/internal/common"
+	"github.com/fatih/color"
+)
+
+// runHooks runs the post-generation steps of a generator in the project,
+// streaming their output, and returns their outcomes
+func runHooks(generator common.Generator, config *common.GeneratorConfig) []common.HookResult {
+	color.New(color.FgBlue).Println(msgRunningHooks)
+
+	var output *hookOutput
+	runner := common.HookRunner{
+		Started: func(hook common.Hook) io.Writer {
+			color.New(color.FgCyan).Printf("   ▶ %s", hook.Name)
+			printHookCommand(hook)
+			output = &hookOutput{out: os.Stdout, color: color.New(color.FgHiBlack)}
+			return output
+		},
+		Finished: func(result common.HookResult) {
+			if output != nil {
+				output.Flush()
+				output = nil
+			}
+			printHookResult(result)
+		},
+	}
+	return runner.Run(config.OutputDir, common.ProjectHooks(generator, config))
+}
+
+// printHookResult reports the outcome of a post-generation step
+func printHookResult(result common.HookResult) {
+	switch result.Status {
+	case common.HookSucceeded:
+		color.New(color.FgGreen).Printf("   ✅ %s\n", result.Hook.Name)
+	case common.HookSkipped:
+		color.New(color.FgHiBlack).Printf("   ⏭️  %s skipped: %s\n", result.Hook.Name, result.Reason)
+	case common.HookFailed:
+		color.New(color.FgRed).Printf("   ❌ %s failed: %v\n", result.Hook.Name, result.Err)
+	}
+}
+
+// printFailedHooks lists the steps that failed so they can be rerun by hand
+func printFailedHooks(results []common.HookResult) {
+	var failed []common.HookResult
+	for _, result := range results {
+		if result.Status == common.HookFailed {
+			failed = append(failed, result)
+		}
+	}
+	if len(failed) == 0 {
+		return
+	}
+
+	color.New(color.FgYellow, color.Bold).Printf(warnHooksFailed, len(failed))
+	for _, result := range failed {
+		color.New(color.FgWhite).Printf("   %s", result.Hook.Name)
+		printHookCommand(result.Hook)
+	}
+	color.New(color.FgYellow).Println(helpHooks)
+}
+
+// printHookCommand ends a line naming a step with the command it runs, if
+// it is not one of the built-in steps
+func printHookCommand(hook common.Hook) {
+	if len(hook.Command) == 0 {
+		fmt.Println()
+		return
+	}
+	color.New(color.FgHiBlack).Printf(" (%s)\n", hook)
+}
+
+// pendingSteps drops the suggested commands a post-generation step already ran
+func pendingSteps(commands []string, results []common.HookResult) []string {
+	var pending []string
+	for _, command := range commands {
+		done := slices.ContainsFunc(results, func(result common.HookResult) bool {
+			return result.Status == common.HookSucceeded && result.Hook.String() == command
+		})
+		if !done {
+			pending = append(pending, command)
+		}
+	}
+	return pending
+}
+
+// hookOutput indents and dims the output of a running step line by line
+type hookOutput struct {
+	out     io.Writer
+	color   *color.Color
+	partial []byte // the last line, until its newline arrives
+}
+
+// Write prints every complete line and keeps the rest for later
+func (h *hookOutput) Write(p []byte) (int, error) {
+	h.partial = append(h.partial, p...)
+	for {
+		end := bytes.IndexByte(h.partial, '\n')
+		if end < 0 {
+			return len(p), nil
+		}
+		h.printLine(h.partial[:end])
+		h.partial = h.partial[end+1:]
+	}
+}
+
+// Flush prints an unfinished last line
+func (h *hookOutput) Flush() {
+	if len(h.partial) > 0 {
+		h.printLine(h.partial)
+		h.partial = nil
+	}
+}
+
+// printLine prints one line of output, dropping carriage returns left by
+// progress bars
+func (h *hookOutput) printLine(line []byte) {
+	if i := bytes.LastIndexByte(bytes.TrimRight(line, "\r"), '\r'); i >= 0 {
+		line = line[i+1:]
+	}
+	h.color.Fprintf(h.out, "     │ %s\n", bytes.TrimRight(line, "\r"))
+}
@@ -0,0 +1,431 @@
+package cmd
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/chrisloarryn/ccin/internal/common"
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	errorWizard        = "❌ Wizard cancelled: %v\n"
+	errorMissingInputs = "❌ Missing inputs: stdin is not a terminal, so ccin cannot ask for them"
+	helpMissingInputs  = "💡 Pass everything on the command line: ccin generate <framework> <project-name> --domain <names> [flags]"
+	wizardHeader       = "🧙 New project"
+	summaryHeader      = "\n📋 Summary:"
+)
+
+// errMissingInputs is returned when the wizard needs answers but has no terminal
+var errMissingInputs = errors.New("missing inputs for a non-interactive run")
+
+// newCmd creates a project by asking for everything that was not given
+var newCmd = &cobra.Command{
+	Use:   "new [framework] [project-name]",
+	Short: "🧙 Create a project interactively",
+	Long: color.New(color.FgCyan, color.Bold).Sprint("🧙 NEW PROJECT WIZARD\n\n") +
+		color.New(color.FgWhite).Sprint("Asks for the framework, project name, domains, database, gRPC, GCP project and\n"+
+			"the template variables of the framework, shows a summary and generates the\n"+
+			"project once confirmed. Without a terminal it lists the missing inputs and\n"+
+			"fails, so scripts stay deterministic.\n\n") +
+		color.New(color.FgCyan).Sprint(exampleHeader) + color.New(color.FgWhite, color.Bold).Sprint("ccin new go-gin orders-api"),
+	Args: cobra.MaximumNArgs(2),
+	RunE: runWizard,
+}
+
+// wizardAnswers is what the wizard collected for one project
+type wizardAnswers struct {
+	generator   common.Generator
+	projectName string
+	domains     []string
+	database    string
+	grpc        bool
+	gcpProject  string
+	vars        [][2]string // template variables of the manifest, as name and value
+}
+
+// runWizard asks for the project settings and runs the chosen generator with
+// them. Framework and project name given as arguments are not asked again.
+func runWizard(cmd *cobra.Command, args []string) error {
+	var answers wizardAnswers
+	if len(args) > 0 {
+		generator, err := common.Registry.Get(args[0])
+		if err != nil {
+			return fmt.Errorf("unknown framework '%s' (available: %s)", args[0], strings.Join(common.Registry.List(), ", "))
+		}
+		answers.generator = generator
+	}
+	if len(args) > 1 {
+		if err := validateProjectName(args[1]); err != nil {
+			return fmt.Errorf("invalid project name: %w", err)
+		}
+		answers.projectName = args[1]
+	}
+
+	if !isTerminal(os.Stdin) {
+		printMissingInputs(answers)
+		cmd.SilenceUsage, cmd.SilenceErrors = true, true
+		return errMissingInputs
+	}
+
+	wizard := &wizard{reader: bufio.NewReader(os.Stdin)}
+	color.New(color.FgCyan, color.Bold).Println(wizardHeader)
+	if err := wizard.ask(&answers, cmd); err != nil {
+		color.New(color.FgRed, color.Bold).Printf(errorWizard, err)
+		return nil
+	}
+
+	printWizardSummary(answers)
+	confirmed, err := wizard.confirm("Generate the project?", true)
+	if err != nil || !confirmed {
+		color.New(color.FgHiBlack).Println("👋 Nothing was generated")
+		return nil
+	}
+
+	// Run the generator command as if the answers had been passed as flags,
+	// together with the flags given to this command (--set, --dry-run, ...)
+	generatorCmd := newGeneratorCommand(answers.generator)
+	generatorCmd.Flags().AddFlagSet(cmd.Flags())
+	flags := map[string]string{
+		flagDomain:     strings.Join(answers.domains, ","),
+		flagDatabase:   answers.database,
+		flagGCPProject: answers.gcpProject,
+	}
+	if answers.generator.GetMetadata().SupportsGRPC {
+		flags[flagGRPC] = strconv.FormatBool(answers.grpc)
+	}
+	for name, value := range flags {
+		if err := generatorCmd.Flags().Set(name, value); err != nil {
+			return err
+		}
+	}
+	for _, variable := range answers.vars {
+		if err := generatorCmd.Flags().Set(flagSet, variable[0]+"="+variable[1]); err != nil {
+			return err
+		}
+	}
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	return runGenerator(generatorCmd, answers.generator, answers.projectName)
+}
+
+// printMissingInputs lists what a non-interactive run has to be given
+func printMissingInputs(answers wizardAnswers) {
+	color.New(color.FgRed, color.Bold).Println(errorMissingInputs)
+	if answers.generator == nil {
+		color.New(color.FgWhite).Printf("   • framework: one of %s\n", strings.Join(common.Registry.List(), ", "))
+	}
+	if answers.projectName == "" {
+		color.New(color.FgWhite).Println("   • project name: at least 2 characters")
+	}
+	color.New(color.FgWhite).Println("   • domains: --domain (e.g. order,customer)")
+	if answers.generator != nil && answers.projectName != "" {
+		color.New(color.FgYellow).Printf("💡 Run: ccin generate %s %s --domain <names> [flags]\n", answers.generator.GetName(), answers.projectName)
+		return
+	}
+	color.New(color.FgYellow).Println(helpMissingInputs)
+}
+
+// printWizardSummary shows the collected answers before confirmation
+func printWizardSummary(answers wizardAnswers) {
+	metadata := answers.generator.GetMetadata()
+	rows := [][2]string{
+		{"Framework", metadata.Icon + " " + metadata.DisplayName},
+		{"Project", answers.projectName},
+		{"Domains", strings.Join(answers.domains, ", ")},
+		{"Database", answers.database},
+	}
+	if metadata.SupportsGRPC {
+		grpc := "no"
+		if answers.grpc {
+			grpc = "yes"
+		}
+		rows = append(rows, [2]string{"gRPC", grpc})
+	}
+	gcpProject := answers.gcpProject
+	if gcpProject == "" {
+		gcpProject = "none"
+	}
+	rows = append(rows, [2]string{"GCP project", gcpProject})
+	rows = append(rows, answers.vars...)
+
+	color.New(color.FgCyan).Println(summaryHeader)
+	for _, row := range rows {
+		color.New(color.FgHiBlack).Printf("   %-12s ", row[0]+":")
+		color.New(color.FgWhite).Println(row[1])
+	}
+	fmt.Println()
+}
+
+// isTerminal reports whether a file is an interactive terminal
+func isTerminal(file *os.File) bool {
+	return isatty.IsTerminal(file.Fd()) || isatty.IsCygwinTerminal(file.Fd())
+}
+
+// wizard asks questions on the terminal, one line per answer
+type wizard struct {
+	reader *bufio.Reader
+}
+
+// ask fills in the answers that are still missing, offering the configured
+// defaults (see settings) and the flags of cmd
+func (w *wizard) ask(answers *wizardAnswers, cmd *cobra.Command) error {
+	var err error
+	if answers.generator == nil {
+		if answers.generator, err = w.chooseGenerator(viper.GetString(settingGenerator)); err != nil {
+			return err
+		}
+	}
+	metadata := answers.generator.GetMetadata()
+
+	if answers.projectName == "" {
+		onConflict, _ := cmd.Flags().GetString(flagOnConflict)
+		answers.projectName, err = w.question("Project name", "", func(name string) error {
+			return checkProjectDir(name, onConflict)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	domains, err := w.question("Domains, comma separated", defaultDomain, func(value string) error {
+		return checkDomains(common.ParseDomainNames([]string{value}))
+	})
+	if err != nil {
+		return err
+	}
+	answers.domains = common.ParseDomainNames([]string{domains})
+
+	answers.database = metadata.DefaultDatabase()
+	if len(metadata.SupportedDatabases) > 1 {
+		answers.database, err = w.question(fmt.Sprintf("Database (%s)", strings.Join(metadata.SupportedDatabases, ", ")), answers.database, func(value string) error {
+			if !metadata.SupportsDatabase(value) {
+				return fmt.Errorf("%s does not support '%s'", metadata.DisplayName, value)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if metadata.SupportsGRPC {
+		if answers.grpc, err = w.confirm("Include gRPC support?", settingBool(cmd, flagGRPC)); err != nil {
+			return err
+		}
+	}
+
+	if answers.gcpProject, err = w.question("GCP project for metrics (blank for none)", settingString(cmd, flagGCPProject), nil); err != nil {
+		return err
+	}
+	return w.askVariables(answers, cmd)
+}
+
+// askVariables asks for the template variables declared in the manifest of
+// the chosen framework that --set and --values did not give, validating
+// each answer as generation will
+func (w *wizard) askVariables(answers *wizardAnswers, cmd *cobra.Command) error {
+	config := &common.GeneratorConfig{TemplateSource: settingTemplateSource(cmd)}
+	templates, err := config.Templates(answers.generator.GetName())
+	if err != nil {
+		return err
+	}
+	manifest, err := common.LoadManifest(templates)
+	if err != nil {
+		return err
+	}
+	given, err := resolveVars(cmd)
+	if err != nil {
+		return err
+	}
+
+	for _, variable := range manifest.Variables {
+		if _, ok := given[variable.Name]; ok {
+			continue
+		}
+		label := cmp.Or(variable.Prompt, variable.Name)
+
+		if variable.Type == common.VariableBool {
+			defaultValue, _ := variable.Default.(bool)
+			value, err := w.confirm(label+"?", defaultValue)
+			if err != nil {
+				return err
+			}
+			answers.vars = append(answers.vars, [2]string{variable.Name, strconv.FormatBool(value)})
+			continue
+		}
+
+		if len(variable.Choices) > 0 {
+			label += fmt.Sprintf(" (%s)", strings.Join(variable.Choices, ", "))
+		}
+		defaultValue := ""
+		if variable.Default != nil {
+			defaultValue = fmt.Sprint(variable.Default)
+		}
+		check := func(value string) error {
+			_, err := variable.Check(value)
+			return err
+		}
+		if !variable.Required && variable.Default == nil {
+			// An optional variable is left unset on a blank answer
+			label += " (blank for none)"
+			check = func(value string) error {
+				if value == "" {
+					return nil
+				}
+				_, err := variable.Check(value)
+				return err
+			}
+		}
+		value, err := w.question(label, defaultValue, check)
+		if err != nil {
+			return err
+		}
+		if value != "" {
+			answers.vars = append(answers.vars, [2]string{variable.Name, value})
+		}
+	}
+	return nil
+}
+
+// chooseGenerator lists the registered generators and asks for one by
+// number or name; defaultName is taken on an empty answer when set
+func (w *wizard) chooseGenerator(defaultName string) (common.Generator, error) {
+	names := common.Registry.List()
+	color.New(color.FgGreen).Println("\n🎯 Available frameworks:")
+	for i, name := range names {
+		generator, _ := common.Registry.Get(name)
+		metadata := generator.GetMetadata()
+		color.New(color.FgYellow).Printf("   %d) %s %s", i+1, metadata.Icon, name)
+		color.New(color.FgHiBlack).Printf(" - %s\n", metadata.Summary)
+	}
+
+	if !slices.Contains(names, defaultName) {
+		defaultName = ""
+	}
+	answer, err := w.question("Framework (number or name)", defaultName, func(value string) error {
+		if number, err := strconv.Atoi(value); err == nil && number >= 1 && number <= len(names) {
+			return nil
+		}
+		if !slices.Contains(names, value) {
+			return fmt.Errorf("unknown framework '%s'", value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if number, err := strconv.Atoi(answer); err == nil {
+		answer = names[number-1]
+	}
+	return common.Registry.Get(answer)
+}
+
+// question asks until the answer passes check; an empty answer takes the
+// default, and is refused when there is none unless check accepts it
+func (w *wizard) question(label, defaultValue string, check func(string) error) (string, error) {
+	for {
+		color.New(color.FgWhite, color.Bold).Printf("? %s", label)
+		if defaultValue != "" {
+			color.New(color.FgHiBlack).Printf(" [%s]", defaultValue)
+		}
+		fmt.Print(": ")
+
+		answer, err := w.readLine()
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			answer = defaultValue
+		}
+		if answer == "" && check != nil && check(answer) != nil {
+			color.New(color.FgRed).Println("   An answer is required")
+			continue
+		}
+		if check != nil {
+			if err := check(answer); err != nil {
+				color.New(color.FgRed).Printf("   %v\n", err)
+				continue
+			}
+		}
+		return answer, nil
+	}
+}
+
+// confirm asks a yes/no question
+func (w *wizard) confirm(label string, defaultValue bool) (bool, error) {
+	hint := "y/N"
+	if defaultValue {
+		hint = "Y/n"
+	}
+	for {
+		color.New(color.FgWhite, color.Bold).Printf("? %s", label)
+		color.New(color.FgHiBlack).Printf(" [%s]", hint)
+		fmt.Print(": ")
+
+		answer, err := w.readLine()
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return defaultValue, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		color.New(color.FgRed).Printf("   Unknown answer '%s'\n", answer)
+	}
+}
+
+// readLine reads one trimmed answer; the end of the input cancels the wizard
+func (w *wizard) readLine() (string, error) {
+	line, err := w.reader.ReadString('\n')
+	if err == io.EOF && line == "" {
+		fmt.Println()
+		return "", errors.New("no more input")
+	}
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// checkProjectDir validates a project name and refuses an existing non-empty
+// directory unless another --on-conflict strategy was chosen
+func checkProjectDir(name, onConflict string) error {
+	if err := validateProjectName(name); err != nil {
+		return err
+	}
+	if onConflict != string(common.ConflictAbort) {
+		return nil
+	}
+	if entries, err := os.ReadDir(name); err == nil && len(entries) > 0 {
+		return fmt.Errorf("directory %s already exists and is not empty", name)
+	}
+	return nil
+}
+
+// checkDomains validates domain names as the generators will
+func checkDomains(names []string) error {
+	domains := make([]common.Domain, len(names))
+	for i, name := range names {
+		domains[i] = common.Domain{Name: name, Fields: common.DefaultFields()}
+	}
+	return common.ValidateDomains(domains)
+}
+
+func init() {
+	rootCmd.AddCommand(newCmd)
+	addGenerationFlags(newCmd.Flags())
+}
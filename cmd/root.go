@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"errors"
 	"os"
+	"strings"
 
+	"github.com/chrisloarryn/ccin/internal/common"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -10,15 +13,27 @@ import (
 
 var cfgFile string
 
+// Build information, set by main from the release ldflags
+var (
+	buildCommit = "none"
+	buildDate   = "unknown"
+)
+
+// SetVersionInfo records the version, commit and build date of the binary.
+// The version is also recorded in the lock file of generated projects.
+func SetVersionInfo(version, commit, date string) {
+	common.Version = version
+	buildCommit = commit
+	buildDate = date
+}
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "ccin",
 	Short: "🚀 Advanced CLI for generating modern CRUD applications",
 	Long: color.New(color.FgCyan, color.Bold).Sprint("🎯 CCIN CLI") + color.New(color.FgWhite).Sprint(" - ChrisLoarryn's Comprehensive Code Integration & Initialization Tool\n\n") +
 		color.New(color.FgGreen).Sprint("✨ Generate production-ready CRUD applications with multiple frameworks:\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("NestJS") + color.New(color.FgHiBlack).Sprint(" (Node.js 24.2.0 + TypeScript + MongoDB)\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("Go + Gin") + color.New(color.FgHiBlack).Sprint(" (REST/gRPC + PostgreSQL + Clean Architecture)\n") +
-		color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint("Go + Fiber") + color.New(color.FgHiBlack).Sprint(" (Ultra-fast REST/gRPC + PostgreSQL)\n\n") +
+		frameworkList() + "\n" +
 		color.New(color.FgMagenta).Sprint("🎁 What you get:\n") +
 		color.New(color.FgHiGreen).Sprint("   ✅ Complete CRUD operations\n") +
 		color.New(color.FgHiGreen).Sprint("   ✅ Production-ready Docker configuration\n") +
@@ -30,10 +45,27 @@ var rootCmd = &cobra.Command{
 		color.New(color.FgCyan).Sprint("🚀 Quick start: ") + color.New(color.FgWhite, color.Bold).Sprint("ccin generate --help"),
 }
 
+// frameworkList renders one bullet per registered generator
+func frameworkList() string {
+	list := ""
+	for _, name := range common.Registry.List() {
+		generator, err := common.Registry.Get(name)
+		if err != nil {
+			continue
+		}
+		metadata := generator.GetMetadata()
+		list += color.New(color.FgYellow).Sprint("   • ") + color.New(color.FgWhite).Sprint(metadata.DisplayName) + color.New(color.FgHiBlack).Sprintf(" - %s\n", metadata.Summary)
+	}
+	return list
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	err := rootCmd.Execute()
+	if errors.As(err, new(reportedError)) {
+		os.Exit(1)
+	}
 	if err != nil {
 		color.New(color.FgRed, color.Bold).Fprintf(os.Stderr, "\n❌ Command Error: %v\n", err)
 		color.New(color.FgYellow).Fprint(os.Stderr, "💡 Quick help: ")
@@ -45,6 +77,18 @@ func Execute() {
 	}
 }
 
+// reportedError is an error a command has already printed together with
+// help on fixing it, so Execute only sets the exit status
+type reportedError struct{ error }
+
+// reported marks err as printed; nil stays nil
+func reported(err error) error {
+	if err == nil {
+		return nil
+	}
+	return reportedError{err}
+}
+
 func init() {
 	cobra.OnInitialize(initConfig)
 
@@ -57,13 +101,14 @@ func init() {
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	rootCmd.Flags().BoolP("version", "v", false, "Show version information")
-	
+
 	// Handle version flag
 	rootCmd.Run = func(cmd *cobra.Command, args []string) {
 		version, _ := cmd.Flags().GetBool("version")
 		if version {
 			color.New(color.FgCyan, color.Bold).Println("🎯 CCIN CLI - ChrisLoarryn's Comprehensive Code Integration & Initialization Tool")
-			color.New(color.FgWhite).Println("Version: 1.0.0")
+			color.New(color.FgWhite).Printf("Version: %s\n", common.Version)
+			color.New(color.FgHiBlack).Printf("Commit: %s (built %s)\n", buildCommit, buildDate)
 			color.New(color.FgHiBlack).Println("Author: Chris Loarryn (@chrisloarryn)")
 			color.New(color.FgHiBlack).Println("Repository: https://github.com/chrisloarryn/homebrew-ccin")
 			color.New(color.FgGreen).Println("\n✨ Generate production-ready CRUD applications with modern frameworks!")
@@ -89,7 +134,10 @@ func initConfig() {
 		viper.SetConfigName(".ccin")
 	}
 
-	viper.AutomaticEnv() // read in environment variables that match
+	// CCIN_GCP_PROJECT and friends override the config file
+	viper.SetEnvPrefix(envPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	viper.AutomaticEnv()
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
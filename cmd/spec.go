@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"errors"
+	"maps"
+
+	"github.com/chrisloarryn/ccin/internal/common"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const (
+	errorSpec = "❌ Invalid spec file %s:\n"
+	helpSpec  = "💡 The spec format is published as schema/service.schema.json; point your editor at it for completion"
+)
+
+// runSpec generates the service declared in a spec file. Settings from the
+// config file and environment are not applied, so the same spec always
+// gives the same project; --template, --set and --values given on the
+// command line still apply. Errors are printed before they are returned.
+func runSpec(cmd *cobra.Command, file string) error {
+	spec, err := common.LoadSpec(file)
+	if err != nil {
+		printSpecError(err)
+		return reported(err)
+	}
+	generator, err := common.Registry.Get(spec.Generator)
+	if err != nil {
+		printSpecError(err)
+		return reported(err)
+	}
+
+	vars, err := resolveVars(cmd)
+	if err != nil {
+		color.New(color.FgRed, color.Bold).Printf(errorInvalidVars, err)
+		color.New(color.FgYellow).Println(helpVars)
+		return reported(err)
+	}
+
+	onConflict, _ := cmd.Flags().GetString(flagOnConflict)
+	strategy, err := common.ParseConflictStrategy(onConflict)
+	if err != nil {
+		color.New(color.FgRed, color.Bold).Printf(errorInvalidConflict, err)
+		return reported(err)
+	}
+
+	config := spec.Config()
+	config.Conflicts = newConflictHandler(strategy)
+	if cmd.Flags().Changed(flagTemplate) || cmd.Flags().Changed(flagTemplates) {
+		config.TemplateSource = templateSource
+	}
+	if len(vars) > 0 {
+		if config.Vars == nil {
+			config.Vars = make(map[string]any, len(vars))
+		}
+		maps.Copy(config.Vars, vars)
+	}
+
+	return runGeneration(cmd, generator, config)
+}
+
+// printSpecError reports a spec that cannot be used, one problem per line
+func printSpecError(err error) {
+	var specErr *common.SpecError
+	if !errors.As(err, &specErr) {
+		color.New(color.FgRed, color.Bold).Printf(errorConfig, err)
+		return
+	}
+	color.New(color.FgRed, color.Bold).Printf(errorSpec, specErr.File)
+	for _, problem := range specErr.Problems {
+		color.New(color.FgWhite).Printf("   %s:%s\n", specErr.File, problem)
+	}
+	color.New(color.FgYellow).Println(helpSpec)
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/chrisloarryn/ccin/internal/common"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagJSON = "json"
+	flagAll  = "all"
+
+	errorStatus = "❌ Could not check the project: %v\n"
+)
+
+// statusCmd reports how far a project has drifted from its templates
+var statusCmd = &cobra.Command{
+	Use:     "status",
+	Aliases: []string{"diff"},
+	Short:   "🔎 Compare a generated project with its templates",
+	Long: color.New(color.FgCyan, color.Bold).Sprint("🔎 STATUS\n\n") +
+		color.New(color.FgWhite).Sprint("Re-renders the project in memory with the configuration recorded in ccin.lock\n"+
+			"and reports every file as pristine, outdated (untouched, but the templates\n"+
+			"changed), modified, missing or extra. `ccin diff` also prints the differences.\n\n") +
+		color.New(color.FgCyan).Sprint(exampleHeader) + color.New(color.FgWhite, color.Bold).Sprint("ccin status --dir orders-api"),
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runStatus(cmd)
+	},
+}
+
+// runStatus prints the drift report of a project
+func runStatus(cmd *cobra.Command) {
+	dir, _ := cmd.Flags().GetString(flagDir)
+	showDiff, _ := cmd.Flags().GetBool(flagDiff)
+	showAll, _ := cmd.Flags().GetBool(flagAll)
+	asJSON, _ := cmd.Flags().GetBool(flagJSON)
+	showDiff = showDiff || cmd.CalledAs() == "diff"
+
+	report, err := common.Status(dir, templateSource)
+	if err != nil {
+		color.New(color.FgRed, color.Bold).Printf(errorStatus, err)
+		color.New(color.FgYellow).Println(helpLockedProject)
+		return
+	}
+
+	if asJSON {
+		content, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			color.New(color.FgRed, color.Bold).Printf(errorStatus, err)
+			return
+		}
+		fmt.Println(string(content))
+		return
+	}
+
+	color.New(color.FgCyan, color.Bold).Printf("\n🔎 %s ", dir)
+	color.New(color.FgHiBlack).Printf("(%s, generated with ccin %s)\n", report.Generator, report.Version)
+	color.New(color.FgHiBlack).Println(separatorLine)
+
+	styles := map[common.DriftStatus]struct {
+		prefix string
+		color  *color.Color
+	}{
+		common.DriftPristine: {"=", color.New(color.FgHiBlack)},
+		common.DriftOutdated: {"↑", color.New(color.FgCyan)},
+		common.DriftModified: {"~", color.New(color.FgYellow)},
+		common.DriftMissing:  {"-", color.New(color.FgRed)},
+		common.DriftExtra:    {"+", color.New(color.FgGreen)},
+	}
+	for _, file := range report.Files {
+		if file.Status == common.DriftPristine && !showAll {
+			continue
+		}
+		style := styles[file.Status]
+		style.color.Printf("   %s %-8s %s\n", style.prefix, file.Status, file.Path)
+	}
+
+	if showDiff {
+		for _, file := range report.Files {
+			if file.Status == common.DriftModified || file.Status == common.DriftOutdated || file.Status == common.DriftMissing {
+				fmt.Println()
+				printDiff(file.Diff())
+			}
+		}
+	}
+
+	summary := ""
+	for i, status := range common.DriftStatuses {
+		if i > 0 {
+			summary += ", "
+		}
+		summary += fmt.Sprintf("%d %s", report.Count(status), status)
+	}
+	color.New(color.FgCyan).Printf("\n📊 %s\n", summary)
+	if report.Count(common.DriftOutdated) > 0 {
+		color.New(color.FgHiBlack).Println("💡 Run ccin upgrade to update the outdated files")
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().String(flagDir, ".", "Root directory of the generated project")
+	addTemplateFlags(statusCmd.Flags(), "Compare with the templates from this source instead of the recorded ones")
+	statusCmd.Flags().Bool(flagDiff, false, "Print a unified diff (templates -> project) for every changed or missing file")
+	statusCmd.Flags().Bool(flagAll, false, "Also list pristine files")
+	statusCmd.Flags().Bool(flagJSON, false, "Print the report as JSON")
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"github.com/chrisloarryn/ccin/internal/common"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagBaseTemplates = "base-templates"
+
+	errorUpgrade      = "❌ Upgrade failed: %v\n"
+	helpLockedProject = "💡 Run this command from the root of a project that has a ccin.lock, or pass --dir"
+)
+
+// upgradeCmd re-applies the current templates to an existing project
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "⬆️  Re-apply newer templates to a project generated by ccin",
+	Long: color.New(color.FgCyan, color.Bold).Sprint("⬆️  UPGRADE\n\n") +
+		color.New(color.FgWhite).Sprint("Re-renders the project with the configuration recorded in ccin.lock and the\n"+
+			"templates of this ccin version. Files you have not touched are updated;\n"+
+			"files you changed are three-way merged against their original rendering,\n"+
+			"marking conflicts in-line like git does. The original rendering comes from\n"+
+			"--base-templates or, when the project is a git repository, from its history;\n"+
+			"without it a file is merged two-way and every line both sides changed\n"+
+			"becomes a conflict.\n\n") +
+		color.New(color.FgCyan).Sprint(exampleHeader) + color.New(color.FgWhite, color.Bold).Sprint("ccin upgrade --dir orders-api --dry-run"),
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runUpgrade(cmd)
+	},
+}
+
+// runUpgrade upgrades the project and reports every file that changed
+func runUpgrade(cmd *cobra.Command) {
+	dir, _ := cmd.Flags().GetString(flagDir)
+	baseTemplates, _ := cmd.Flags().GetString(flagBaseTemplates)
+	dryRun, _ := cmd.Flags().GetBool(flagDryRun)
+
+	if dryRun {
+		color.New(color.FgBlue).Println(dryRunMsg)
+	}
+	result, err := common.Upgrade(dir, common.UpgradeOptions{
+		TemplateSource:     templateSource,
+		BaseTemplateSource: baseTemplates,
+		DryRun:             dryRun,
+	})
+	if err != nil {
+		color.New(color.FgRed, color.Bold).Printf(errorUpgrade, err)
+		color.New(color.FgYellow).Println(helpLockedProject)
+		return
+	}
+
+	name := result.Generator
+	if generator, err := common.Registry.Get(result.Generator); err == nil {
+		name = generator.GetMetadata().DisplayName
+	}
+	color.New(color.FgCyan, color.Bold).Printf("\n⬆️  Upgrading %s project in %s ", name, dir)
+	color.New(color.FgHiBlack).Printf("(generated with ccin %s, now %s)\n", result.FromVersion, common.Version)
+	color.New(color.FgHiBlack).Println(separatorLine)
+
+	counts := make(map[common.UpgradeAction]int)
+	noBase := 0
+	for _, file := range result.Files {
+		counts[file.Action]++
+		if file.NoBase {
+			noBase++
+		}
+		printUpgradeFile(file)
+	}
+
+	color.New(color.FgCyan).Printf("\n📊 %d updated, %d created, %d merged, %d with conflicts, %d removed, %d kept, %d left deleted, %d unchanged\n",
+		counts[common.UpgradeUpdated], counts[common.UpgradeCreated], counts[common.UpgradeMerged], counts[common.UpgradeConflict],
+		counts[common.UpgradeRemoved], counts[common.UpgradeKept], counts[common.UpgradeDeleted], counts[common.UpgradeUnchanged])
+	if noBase > 0 {
+		color.New(color.FgYellow).Printf("⚠️  %d files were merged two-way: their original rendering is neither in --base-templates nor in the project's git history, so every line both you and the templates changed is a conflict\n", noBase)
+		color.New(color.FgYellow).Println("💡 Pass --base-templates with the templates the project was generated with, or commit generated projects, to get three-way merges")
+	}
+	if counts[common.UpgradeConflict] > 0 {
+		color.New(color.FgYellow).Println("💡 Resolve the <<<<<<< existing / >>>>>>> ccin markers, then build and test the project")
+	}
+	if dryRun {
+		color.New(color.FgHiBlack).Println("💡 Run again without --dry-run to apply the upgrade")
+	}
+}
+
+// printUpgradeFile prints one file of the upgrade result; unchanged files are only counted
+func printUpgradeFile(file common.UpgradeFile) {
+	switch file.Action {
+	case common.UpgradeCreated:
+		color.New(color.FgGreen).Printf("   + %-9s %s\n", file.Action, file.Path)
+	case common.UpgradeUpdated:
+		color.New(color.FgYellow).Printf("   ~ %-9s %s\n", file.Action, file.Path)
+	case common.UpgradeMerged:
+		color.New(color.FgYellow).Printf("   ~ %-9s %s%s\n", file.Action, file.Path, twoWay(file))
+	case common.UpgradeConflict:
+		color.New(color.FgRed).Printf("   ! %-9s %s (%d conflicts)%s\n", file.Action, file.Path, file.Conflicts, twoWay(file))
+	case common.UpgradeRemoved:
+		color.New(color.FgRed).Printf("   - %-9s %s\n", file.Action, file.Path)
+	case common.UpgradeKept:
+		color.New(color.FgHiBlack).Printf("   = %-9s %s (no longer generated, kept your changes)\n", file.Action, file.Path)
+	case common.UpgradeDeleted:
+		color.New(color.FgHiBlack).Printf("   = %-9s %s (deleted by you, kept deleted)\n", file.Action, file.Path)
+	}
+}
+
+// twoWay notes a merge made without the file's original rendering
+func twoWay(file common.UpgradeFile) string {
+	if file.NoBase {
+		return " (two-way, no original rendering)"
+	}
+	return ""
+}
+
+func init() {
+	rootCmd.AddCommand(upgradeCmd)
+
+	upgradeCmd.Flags().String(flagDir, ".", "Root directory of the generated project")
+	addTemplateFlags(upgradeCmd.Flags(), "Upgrade to the templates from this source instead of the recorded or embedded ones")
+	upgradeCmd.Flags().String(flagBaseTemplates, "", "Template source the project was generated with (e.g. git+https://...@v1.0.0), used to re-render the merge base")
+	upgradeCmd.Flags().Bool(flagDryRun, false, "Show what would change without writing anything")
+}
@@ -0,0 +1,137 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ConflictStrategy decides what happens to generated files that already exist
+type ConflictStrategy string
+
+// Conflict strategies accepted by --on-conflict
+const (
+	ConflictAbort     ConflictStrategy = "abort"     // refuse to generate into a non-empty directory
+	ConflictSkip      ConflictStrategy = "skip"      // keep existing files
+	ConflictOverwrite ConflictStrategy = "overwrite" // replace existing files
+	ConflictPrompt    ConflictStrategy = "prompt"    // ask for every existing file
+	ConflictMerge     ConflictStrategy = "merge"     // merge into existing files, marking conflicts
+)
+
+// ConflictStrategies lists the strategies in help order
+var ConflictStrategies = []ConflictStrategy{ConflictAbort, ConflictSkip, ConflictOverwrite, ConflictPrompt, ConflictMerge}
+
+// ErrOutputNotEmpty is returned when generating into a non-empty directory
+// with the default ConflictAbort strategy
+var ErrOutputNotEmpty = errors.New("output directory is not empty")
+
+// ParseConflictStrategy validates a --on-conflict value
+func ParseConflictStrategy(value string) (ConflictStrategy, error) {
+	for _, strategy := range ConflictStrategies {
+		if string(strategy) == value {
+			return strategy, nil
+		}
+	}
+
+	names := make([]string, len(ConflictStrategies))
+	for i, strategy := range ConflictStrategies {
+		names[i] = string(strategy)
+	}
+	return "", fmt.Errorf("unknown conflict strategy '%s' (supported: %s)", value, strings.Join(names, ", "))
+}
+
+// ConflictHandler resolves generated files that already exist in the output
+// directory. The zero value aborts on a non-empty directory.
+type ConflictHandler struct {
+	Strategy ConflictStrategy
+
+	// Prompt is asked for every existing file when Strategy is ConflictPrompt
+	// and returns ConflictSkip, ConflictOverwrite or ConflictMerge
+	Prompt func(path string, existing, rendered []byte) (ConflictStrategy, error)
+
+	// Resolved, when set, is told how each existing file that differed was
+	// resolved and how many conflicts a merge left in it
+	Resolved func(path string, resolution ConflictStrategy, conflicts int)
+
+	// target is the directory holding the existing files when the project
+	// is generated into a staging directory (see GenerateProject)
+	target string
+}
+
+// strategy returns the configured strategy, aborting by default
+func (h ConflictHandler) strategy() ConflictStrategy {
+	if h.Strategy == "" {
+		return ConflictAbort
+	}
+	return h.Strategy
+}
+
+// checkOutputDir refuses a non-empty output directory when aborting on conflicts
+func (h ConflictHandler) checkOutputDir(outputDir string) error {
+	if h.strategy() != ConflictAbort {
+		return nil
+	}
+
+	if h.target != "" {
+		outputDir = h.target
+	}
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("%w: %s", ErrOutputNotEmpty, outputDir)
+	}
+	return nil
+}
+
+// resolve returns the content to write for a generated file, or nil to leave
+// the file on disk as it is
+func (h ConflictHandler) resolve(outputPath, relPath string, rendered []byte) ([]byte, error) {
+	if h.target != "" {
+		outputPath = filepath.Join(h.target, relPath)
+	}
+	existing, err := os.ReadFile(outputPath)
+	if os.IsNotExist(err) {
+		return rendered, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(existing, rendered) {
+		return nil, nil
+	}
+
+	resolution := h.strategy()
+	if resolution == ConflictPrompt {
+		if h.Prompt == nil {
+			return nil, fmt.Errorf("%s already exists and no prompt is available", relPath)
+		}
+		if resolution, err = h.Prompt(relPath, existing, rendered); err != nil {
+			return nil, err
+		}
+	}
+
+	var content []byte
+	conflicts := 0
+	switch resolution {
+	case ConflictSkip:
+	case ConflictOverwrite:
+		content = rendered
+	case ConflictMerge:
+		content, conflicts = MergeFiles(existing, rendered)
+	default:
+		return nil, fmt.Errorf("%s already exists", relPath)
+	}
+
+	if h.Resolved != nil {
+		h.Resolved(relPath, resolution, conflicts)
+	}
+	return content, nil
+}
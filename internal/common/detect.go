@@ -0,0 +1,84 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// DetectProject finds the generator that produced the project in dir and
+// rebuilds the configuration it was generated with. The project's lock file
+// is used when present; otherwise the configuration is read back from the
+// project files as far as possible.
+func DetectProject(dir string) (Generator, *GeneratorConfig, error) {
+	if lock, err := ReadLock(dir); err == nil {
+		generator, err := Registry.Get(lock.Generator)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s in %s: %w", LockFile, dir, err)
+		}
+		return generator, lock.GeneratorConfig(dir), nil
+	} else if !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+
+	for _, name := range Registry.List() {
+		generator, err := Registry.Get(name)
+		if err != nil {
+			continue
+		}
+
+		metadata := generator.GetMetadata()
+		if len(metadata.Detect) == 0 || !matchesAll(dir, metadata.Detect) {
+			continue
+		}
+
+		match, ok := metadata.ProjectName.match(dir)
+		if !ok || len(match) < 2 {
+			return nil, nil, fmt.Errorf("found a %s project in %s but could not read its name from %s", metadata.DisplayName, dir, metadata.ProjectName.File)
+		}
+
+		config := &GeneratorConfig{
+			ProjectName:  match[1],
+			OutputDir:    dir,
+			DatabaseType: metadata.DefaultDatabase(),
+			Port:         metadata.DefaultPort,
+		}
+		if metadata.GRPCMarker.File != "" {
+			_, config.WithGRPC = metadata.GRPCMarker.match(dir)
+		}
+
+		return generator, config, nil
+	}
+
+	return nil, nil, fmt.Errorf("no project generated by ccin found in %s", dir)
+}
+
+// matchesAll reports whether every marker matches
+func matchesAll(dir string, markers []ProjectMarker) bool {
+	for _, marker := range markers {
+		if _, ok := marker.match(dir); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// match checks the marker against the project in dir and returns the
+// pattern's submatches (nil when the marker has no pattern)
+func (m ProjectMarker) match(dir string) ([]string, bool) {
+	content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(m.File)))
+	if err != nil {
+		return nil, false
+	}
+	if m.Pattern == "" {
+		return nil, true
+	}
+
+	pattern, err := regexp.Compile(m.Pattern)
+	if err != nil {
+		return nil, false
+	}
+	match := pattern.FindStringSubmatch(string(content))
+	return match, match != nil
+}
@@ -0,0 +1,167 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// editKind is the operation of one line in a line diff
+type editKind int
+
+const (
+	editEqual editKind = iota
+	editDelete
+	editInsert
+)
+
+// edit is one line of a line diff; lines keep their trailing newline
+type edit struct {
+	kind editKind
+	line string
+}
+
+// splitLines splits content into lines, keeping each line's newline
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns the edits turning a into b, based on their longest
+// common subsequence. Generated files are small, so the quadratic table is fine.
+func diffLines(a, b []string) []edit {
+	// Common prefix and suffix need no table
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]edit, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		edits = append(edits, edit{editEqual, line})
+	}
+
+	x, y := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			edits = append(edits, edit{editEqual, x[i]})
+			i++
+			j++
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{editDelete, x[i]})
+			i++
+		default:
+			edits = append(edits, edit{editInsert, y[j]})
+			j++
+		}
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, edit{editEqual, line})
+	}
+	return edits
+}
+
+// UnifiedDiff returns a unified diff (as produced by diff -u) turning from
+// into to, or an empty string when both are equal
+func UnifiedDiff(fromName, toName string, from, to []byte) string {
+	if bytes.Equal(from, to) {
+		return ""
+	}
+	edits := diffLines(splitLines(from), splitLines(to))
+
+	// Line numbers in from and to before each edit
+	fromLine := make([]int, len(edits)+1)
+	toLine := make([]int, len(edits)+1)
+	for i, e := range edits {
+		fromLine[i+1], toLine[i+1] = fromLine[i], toLine[i]
+		if e.kind != editInsert {
+			fromLine[i+1]++
+		}
+		if e.kind != editDelete {
+			toLine[i+1]++
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+
+	for i := 0; i < len(edits); {
+		if edits[i].kind == editEqual {
+			i++
+			continue
+		}
+
+		// Grow the hunk while the next change is close enough to share context
+		start := max(i-diffContext, 0)
+		end := i
+		for next := i; next < len(edits); next++ {
+			if edits[next].kind == editEqual {
+				continue
+			}
+			if next-end-1 > 2*diffContext {
+				break
+			}
+			end = next
+		}
+		end = min(end+diffContext+1, len(edits))
+
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n",
+			hunkRange(fromLine[start], fromLine[end]-fromLine[start]),
+			hunkRange(toLine[start], toLine[end]-toLine[start]))
+		for _, e := range edits[start:end] {
+			prefix := " "
+			switch e.kind {
+			case editDelete:
+				prefix = "-"
+			case editInsert:
+				prefix = "+"
+			}
+			out.WriteString(prefix + e.line)
+			if !strings.HasSuffix(e.line, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = end
+	}
+
+	return out.String()
+}
+
+// hunkRange formats the start,length pair of a hunk header; empty ranges
+// start at the line before them
+func hunkRange(before, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	return fmt.Sprintf("%d,%d", before+1, length)
+}
@@ -0,0 +1,100 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var domainNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+// Domain is a resource of the generated service. Per-domain templates are
+// rendered once for every domain of a project.
+type Domain struct {
+	Name       string     `yaml:"name" json:"name"`
+	Fields     []Field    `yaml:"fields" json:"fields"`                             // DefaultFields() when empty
+	Operations Operations `yaml:"operations,omitempty" json:"operations,omitempty"` // from an API contract, see Operation
+}
+
+// entityFile is the YAML accepted by --entity: either a single entity
+// (name + fields) or a list of them under "entities"
+type entityFile struct {
+	Domain   `yaml:",inline"`
+	Entities []Domain `yaml:"entities"`
+}
+
+// LoadEntityFile reads entity definitions from a YAML file. A single entity
+// may omit its name, in which case the caller supplies it.
+func LoadEntityFile(path string) ([]Domain, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file entityFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("invalid entity file %s: %w", path, err)
+	}
+
+	domains := file.Entities
+	if len(domains) == 0 {
+		domains = []Domain{file.Domain}
+	} else if file.Name != "" || len(file.Fields) > 0 {
+		return nil, fmt.Errorf("invalid entity file %s: use either name/fields or entities, not both", path)
+	}
+
+	for i := range domains {
+		for j, field := range domains[i].Fields {
+			normalized, err := field.normalize()
+			if err != nil {
+				return nil, fmt.Errorf("invalid entity file %s: %w", path, err)
+			}
+			domains[i].Fields[j] = normalized
+		}
+		if len(domains) > 1 && domains[i].Name == "" {
+			return nil, fmt.Errorf("invalid entity file %s: entity %d has no name", path, i+1)
+		}
+	}
+
+	return domains, nil
+}
+
+// ParseDomainNames splits a comma separated domain list (e.g. "order,customer")
+func ParseDomainNames(values []string) []string {
+	var names []string
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// ValidateDomains checks domain names and their fields
+func ValidateDomains(domains []Domain) error {
+	if len(domains) == 0 {
+		return fmt.Errorf("at least one domain is required")
+	}
+
+	seen := make(map[string]bool, len(domains))
+	for _, domain := range domains {
+		if !domainNamePattern.MatchString(domain.Name) {
+			return fmt.Errorf("invalid domain name '%s': use letters and digits, starting with a letter", domain.Name)
+		}
+		key := strings.ToLower(domain.Name)
+		if seen[key] {
+			return fmt.Errorf("duplicate domain '%s'", domain.Name)
+		}
+		seen[key] = true
+
+		if err := ValidateFields(domain.Fields); err != nil {
+			return fmt.Errorf("domain '%s': %w", domain.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,447 @@
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Supported field types
+const (
+	FieldString   = "string"
+	FieldText     = "text"
+	FieldInt      = "int"
+	FieldFloat    = "float"
+	FieldDecimal  = "decimal"
+	FieldBool     = "bool"
+	FieldDateTime = "datetime"
+	FieldUUID     = "uuid"
+)
+
+// FieldTypes lists the supported field types in display order
+var FieldTypes = []string{FieldString, FieldText, FieldInt, FieldFloat, FieldDecimal, FieldBool, FieldDateTime, FieldUUID}
+
+// reservedFields are generated for every entity and cannot be redeclared
+var reservedFields = map[string]bool{"id": true, "created_at": true, "updated_at": true}
+
+var fieldNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Field describes a typed attribute of a domain entity
+type Field struct {
+	Name     string `yaml:"name" json:"name"`
+	Type     string `yaml:"type" json:"type"`
+	Unique   bool   `yaml:"unique,omitempty" json:"unique,omitempty"`
+	Optional bool   `yaml:"optional,omitempty" json:"optional,omitempty"`
+	Position int    `yaml:"-" json:"-"` // 1-based position, set by PrepareTemplateData
+}
+
+// Fields is an ordered list of entity fields with helpers for SQL templates
+type Fields []Field
+
+// DefaultFields returns the fields used when none are declared
+func DefaultFields() []Field {
+	return []Field{
+		{Name: "name", Type: FieldString},
+		{Name: "description", Type: FieldText, Optional: true},
+	}
+}
+
+// ParseField parses a field flag of the form name:type[:modifier...]
+// where modifiers are "unique" and "optional" (e.g. "sku:string:unique")
+func ParseField(spec string) (Field, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) < 2 {
+		return Field{}, fmt.Errorf("invalid field '%s': expected name:type[:unique][:optional]", spec)
+	}
+
+	field := Field{Name: parts[0], Type: parts[1]}
+	for _, modifier := range parts[2:] {
+		switch strings.ToLower(modifier) {
+		case "unique":
+			field.Unique = true
+		case "optional":
+			field.Optional = true
+		default:
+			return Field{}, fmt.Errorf("invalid field '%s': unknown modifier '%s'", spec, modifier)
+		}
+	}
+
+	return field.normalize()
+}
+
+// ValidateFields checks a field list for duplicate names
+func ValidateFields(fields []Field) error {
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if seen[field.Name] {
+			return fmt.Errorf("duplicate field '%s'", field.Name)
+		}
+		seen[field.Name] = true
+	}
+	return nil
+}
+
+// normalize converts the name to snake_case and validates name and type
+func (f Field) normalize() (Field, error) {
+	f.Name = toSnake(f.Name)
+	f.Type = strings.ToLower(f.Type)
+
+	if !fieldNamePattern.MatchString(f.Name) {
+		return f, fmt.Errorf("invalid field name '%s': use letters, digits and underscores", f.Name)
+	}
+	if reservedFields[f.Name] {
+		return f, fmt.Errorf("field '%s' is generated automatically", f.Name)
+	}
+
+	for _, supported := range FieldTypes {
+		if f.Type == supported {
+			return f, nil
+		}
+	}
+	return f, fmt.Errorf("field '%s' has unsupported type '%s' (supported: %s)", f.Name, f.Type, strings.Join(FieldTypes, ", "))
+}
+
+// String returns the field in the name:type[:unique][:optional] flag syntax
+func (f Field) String() string {
+	spec := f.Name + ":" + f.Type
+	if f.Unique {
+		spec += ":unique"
+	}
+	if f.Optional {
+		spec += ":optional"
+	}
+	return spec
+}
+
+// Snake returns the field name in snake_case (database columns, JSON in Go/Rust)
+func (f Field) Snake() string {
+	return f.Name
+}
+
+// Pascal returns the field name in PascalCase (Go struct fields)
+func (f Field) Pascal() string {
+	return Pascal(f.Name)
+}
+
+// Camel returns the field name in camelCase (TypeScript and Swift properties)
+func (f Field) Camel() string {
+	return Camel(f.Name)
+}
+
+// IsString reports whether the field holds text and can be checked for emptiness
+func (f Field) IsString() bool {
+	return f.Type == FieldString || f.Type == FieldText || f.Type == FieldUUID
+}
+
+// GoType returns the Go type of the field
+func (f Field) GoType() string {
+	switch f.Type {
+	case FieldInt:
+		return "int64"
+	case FieldFloat, FieldDecimal:
+		return "float64"
+	case FieldBool:
+		return "bool"
+	case FieldDateTime:
+		return "time.Time"
+	default:
+		return "string"
+	}
+}
+
+// GoFieldType returns the Go type used in models, a pointer when optional
+func (f Field) GoFieldType() string {
+	if f.Optional {
+		return "*" + f.GoType()
+	}
+	return f.GoType()
+}
+
+// SQLType returns the PostgreSQL column type
+func (f Field) SQLType() string {
+	switch f.Type {
+	case FieldText:
+		return "TEXT"
+	case FieldInt:
+		return "BIGINT"
+	case FieldFloat:
+		return "DOUBLE PRECISION"
+	case FieldDecimal:
+		return "NUMERIC(12,2)"
+	case FieldBool:
+		return "BOOLEAN"
+	case FieldDateTime:
+		return "TIMESTAMP"
+	case FieldUUID:
+		return "UUID"
+	default:
+		return "VARCHAR(255)"
+	}
+}
+
+// SQLColumn returns the column definition used in CREATE TABLE
+func (f Field) SQLColumn() string {
+	column := f.Name + " " + f.SQLType()
+	if !f.Optional {
+		column += " NOT NULL"
+	}
+	if f.Unique {
+		column += " UNIQUE"
+	}
+	return column
+}
+
+// TSType returns the TypeScript type of the field
+func (f Field) TSType() string {
+	switch f.Type {
+	case FieldInt, FieldFloat, FieldDecimal:
+		return "number"
+	case FieldBool:
+		return "boolean"
+	case FieldDateTime:
+		return "Date"
+	default:
+		return "string"
+	}
+}
+
+// TSValidator returns the class-validator decorator for the field
+func (f Field) TSValidator() string {
+	switch f.Type {
+	case FieldInt:
+		return "IsInt"
+	case FieldFloat, FieldDecimal:
+		return "IsNumber"
+	case FieldBool:
+		return "IsBoolean"
+	case FieldDateTime:
+		return "IsDateString"
+	case FieldUUID:
+		return "IsUUID"
+	default:
+		return "IsString"
+	}
+}
+
+// TSDtoType returns the TypeScript type used in DTOs (dates arrive as ISO strings)
+func (f Field) TSDtoType() string {
+	if f.Type == FieldDateTime {
+		return "string"
+	}
+	return f.TSType()
+}
+
+// MongoPropOptions returns the options passed to the Mongoose @Prop() decorator
+func (f Field) MongoPropOptions() string {
+	var options []string
+	if !f.Optional {
+		options = append(options, "required: true")
+	}
+	if f.Unique {
+		options = append(options, "unique: true")
+	}
+	if len(options) == 0 {
+		return ""
+	}
+	return "{ " + strings.Join(options, ", ") + " }"
+}
+
+// RustType returns the Rust type of the field
+func (f Field) RustType() string {
+	switch f.Type {
+	case FieldInt:
+		return "i64"
+	case FieldFloat, FieldDecimal:
+		return "f64"
+	case FieldBool:
+		return "bool"
+	default:
+		return "String"
+	}
+}
+
+// RustFieldType returns the Rust type used in structs, an Option when optional
+func (f Field) RustFieldType() string {
+	if f.Optional {
+		return "Option<" + f.RustType() + ">"
+	}
+	return f.RustType()
+}
+
+// RustExample returns a Rust expression holding a sample value for the field
+func (f Field) RustExample() string {
+	if f.Optional {
+		return "None"
+	}
+	switch f.Type {
+	case FieldInt:
+		return "0"
+	case FieldFloat, FieldDecimal:
+		return "0.0"
+	case FieldBool:
+		return "false"
+	default:
+		return fmt.Sprintf("%q.to_string()", "sample-"+f.Name)
+	}
+}
+
+// SwiftType returns the Swift type of the field
+func (f Field) SwiftType() string {
+	switch f.Type {
+	case FieldInt:
+		return "Int"
+	case FieldFloat:
+		return "Double"
+	case FieldDecimal:
+		return "Decimal"
+	case FieldBool:
+		return "Bool"
+	case FieldDateTime:
+		return "Date"
+	case FieldUUID:
+		return "UUID"
+	default:
+		return "String"
+	}
+}
+
+// SwiftFieldType returns the Swift type used in models, an Optional when optional
+func (f Field) SwiftFieldType() string {
+	if f.Optional {
+		return f.SwiftType() + "?"
+	}
+	return f.SwiftType()
+}
+
+// ProtoType returns the protobuf scalar type of the field
+func (f Field) ProtoType() string {
+	switch f.Type {
+	case FieldInt:
+		return "int64"
+	case FieldFloat, FieldDecimal:
+		return "double"
+	case FieldBool:
+		return "bool"
+	default:
+		return "string"
+	}
+}
+
+// ProtoNumber returns the protobuf field number (1 is reserved for the id)
+func (f Field) ProtoNumber() int {
+	return f.Position + 1
+}
+
+// Example returns a JSON literal usable in example requests
+func (f Field) Example() string {
+	switch f.Type {
+	case FieldInt:
+		return "1"
+	case FieldFloat:
+		return "1.5"
+	case FieldDecimal:
+		return "9.99"
+	case FieldBool:
+		return "true"
+	case FieldDateTime:
+		return `"2025-01-01T00:00:00Z"`
+	case FieldUUID:
+		return `"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	default:
+		return `"Example ` + f.Name + `"`
+	}
+}
+
+// String returns the fields as a comma separated list of flag specs
+func (fs Fields) String() string {
+	specs := make([]string, len(fs))
+	for i, field := range fs {
+		specs[i] = field.String()
+	}
+	return strings.Join(specs, ", ")
+}
+
+// Columns returns the comma separated column names
+func (fs Fields) Columns() string {
+	names := make([]string, len(fs))
+	for i, field := range fs {
+		names[i] = field.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// Placeholders returns PostgreSQL placeholders starting at $start
+func (fs Fields) Placeholders(start int) string {
+	placeholders := make([]string, len(fs))
+	for i := range fs {
+		placeholders[i] = fmt.Sprintf("$%d", start+i)
+	}
+	return strings.Join(placeholders, ", ")
+}
+
+// Assignments returns "column = $n" pairs starting at $start for UPDATE statements
+func (fs Fields) Assignments(start int) string {
+	assignments := make([]string, len(fs))
+	for i, field := range fs {
+		assignments[i] = fmt.Sprintf("%s = $%d", field.Name, start+i)
+	}
+	return strings.Join(assignments, ", ")
+}
+
+// Next returns the placeholder number offset positions after the last field
+// (e.g. Next(1) is the first placeholder free after Placeholders(1))
+func (fs Fields) Next(offset int) int {
+	return len(fs) + offset
+}
+
+// TSValidatorImports returns the class-validator decorators used by the
+// fields plus any extra ones, without duplicates, for an import statement
+func (fs Fields) TSValidatorImports(extra ...string) string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range extra {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, field := range fs {
+		if name := field.TSValidator(); !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// ExampleJSON returns an example request body containing every field
+func (fs Fields) ExampleJSON() string {
+	pairs := make([]string, len(fs))
+	for i, field := range fs {
+		pairs[i] = fmt.Sprintf(`"%s": %s`, field.Name, field.Example())
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// toSnake converts camelCase, PascalCase or kebab-case names to snake_case
+func toSnake(name string) string {
+	var b strings.Builder
+	runes := []rune(strings.TrimSpace(name))
+	for i, r := range runes {
+		switch {
+		case r == '-' || r == ' ':
+			b.WriteRune('_')
+		case unicode.IsUpper(r):
+			if i > 0 && runes[i-1] != '_' && runes[i-1] != '-' && !unicode.IsUpper(runes[i-1]) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
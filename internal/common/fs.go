@@ -0,0 +1,337 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/chrisloarryn/ccin/templates"
+	"gopkg.in/yaml.v3"
+)
+
+// EmbeddedTemplates is the template tree compiled into the binary
+var EmbeddedTemplates fs.FS = templates.FS
+
+// PartialsDir holds partials: templates of {{define "name"}} blocks that
+// every template can call with {{template "name" .}}. A template root has a
+// shared one, and a template set can add its own or override shared files.
+const PartialsDir = "_partials"
+
+// SharedDir holds generator-specific files kept outside the template sets,
+// named <file>.<set>.tpl (e.g. Dockerfile.go-core.tpl). They are rendered as
+// <file> by the named set and by the sets that extend it, unless the set has
+// a <file>.tpl of its own.
+const SharedDir = "common"
+
+// LoadTemplateFS returns the template set for a generator. When source is
+// empty the embedded templates are used; otherwise the source (a directory,
+// git repository or archive, see FetchTemplateSource) is resolved to a
+// template root and the set is read from <root>/<generator>, so template
+// authors can iterate without rebuilding. The set is composed with the base
+// sets its manifest extends and the shared partials of the root.
+func LoadTemplateFS(source, generator string) (fs.FS, error) {
+	if source == "" {
+		if _, err := fs.Stat(EmbeddedTemplates, generator); err != nil {
+			return nil, fmt.Errorf("no embedded templates for generator '%s'", generator)
+		}
+		return loadTemplateSet(EmbeddedTemplates, generator)
+	}
+
+	dir, err := FetchTemplateSource(source)
+	if err != nil {
+		return nil, err
+	}
+
+	root := filepath.Join(dir, generator)
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("template directory %s: %w", root, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("template directory %s is not a directory", root)
+	}
+
+	return loadTemplateSet(os.DirFS(dir), generator)
+}
+
+// templateLayer is one template set of a composed templateSet
+type templateLayer struct {
+	name string
+	fs.FS
+}
+
+// templateSet reads a template set together with the sets it extends as one
+// file system: a file of the set shadows the file at the same path in its
+// base, and directories list the files of every layer. Each set is followed
+// by its files from the shared directory, and the shared partials of the
+// template root are the bottom layer.
+type templateSet struct {
+	layers []templateLayer // the set, its shared files, then its bases likewise, then the root partials
+}
+
+// loadTemplateSet composes the named set of a template root with the chain
+// of base sets declared by "extends" in their manifests
+func loadTemplateSet(root fs.FS, name string) (*templateSet, error) {
+	set := &templateSet{}
+	for name != "" {
+		chain := set.names()
+		if slices.Contains(chain, name) {
+			return nil, fmt.Errorf("template sets extend each other in a cycle: %s -> %s", strings.Join(chain, " -> "), name)
+		}
+
+		sub, err := fs.Sub(root, name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := fs.Stat(sub, "."); err != nil {
+			if len(chain) == 0 {
+				return nil, fmt.Errorf("template set '%s' does not exist", name)
+			}
+			return nil, fmt.Errorf("template set '%s' extends '%s', which does not exist", chain[len(chain)-1], name)
+		}
+		shared, err := loadSharedFiles(root, name)
+		if err != nil {
+			return nil, err
+		}
+		set.layers = append(set.layers, templateLayer{name: name, FS: sub}, templateLayer{name: SharedDir, FS: shared})
+
+		base, err := manifestExtends(sub)
+		if err != nil {
+			return nil, fmt.Errorf("template set '%s': %w", name, err)
+		}
+		name = base
+	}
+
+	set.layers = append(set.layers, templateLayer{name: PartialsDir, FS: partialsOnly{root}})
+	return set, nil
+}
+
+// names lists the names of the composed sets, the set first
+func (s *templateSet) names() []string {
+	var names []string
+	for _, layer := range s.layers {
+		if layer.name != SharedDir && layer.name != PartialsDir {
+			names = append(names, layer.name)
+		}
+	}
+	return names
+}
+
+// manifestExtends reads the base set a template set's manifest extends
+func manifestExtends(templates fs.FS) (string, error) {
+	content, err := fs.ReadFile(templates, ManifestFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var manifest struct {
+		Extends string `yaml:"extends"`
+	}
+	if err := yaml.Unmarshal(content, &manifest); err != nil {
+		return "", fmt.Errorf("invalid %s: %w", ManifestFile, err)
+	}
+	if manifest.Extends != "" && (!fs.ValidPath(manifest.Extends) || strings.Contains(manifest.Extends, "/")) {
+		return "", fmt.Errorf("invalid %s: extends '%s' is not a template set name", ManifestFile, manifest.Extends)
+	}
+	return manifest.Extends, nil
+}
+
+// Open opens the file of the topmost layer that has it
+func (s *templateSet) Open(name string) (fs.File, error) {
+	for _, layer := range s.layers {
+		file, err := layer.Open(name)
+		if err == nil || !errors.Is(err, fs.ErrNotExist) {
+			return file, err
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir lists a directory across all layers, sorted by name
+func (s *templateSet) ReadDir(name string) ([]fs.DirEntry, error) {
+	var entries []fs.DirEntry
+	seen := make(map[string]bool)
+	found := false
+	for _, layer := range s.layers {
+		layerEntries, err := fs.ReadDir(layer.FS, name)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		found = true
+		for _, entry := range layerEntries {
+			if !seen[entry.Name()] {
+				seen[entry.Name()] = true
+				entries = append(entries, entry)
+			}
+		}
+	}
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return entries, nil
+}
+
+// manifestLayers returns the layers of a template set that can hold a
+// manifest, bases first; a plain file system is a single layer
+func manifestLayers(templates fs.FS) []templateLayer {
+	set, ok := templates.(*templateSet)
+	if !ok {
+		return []templateLayer{{FS: templates}}
+	}
+
+	var layers []templateLayer
+	for _, layer := range slices.Backward(set.layers) {
+		if layer.name != SharedDir && layer.name != PartialsDir {
+			layers = append(layers, layer)
+		}
+	}
+	return layers
+}
+
+// partialFiles lists the partial templates of a template set in parse
+// order: the layers from the bottom up, so a set's definitions override
+// those of its bases and of the shared partials. Shadowed files are left out.
+func partialFiles(templates fs.FS) ([]string, error) {
+	set, ok := templates.(*templateSet)
+	if !ok {
+		set = &templateSet{layers: []templateLayer{{FS: templates}}}
+	}
+
+	var files []string
+	seen := make(map[string]bool)
+	for _, layer := range set.layers {
+		var layerFiles []string
+		err := fs.WalkDir(layer.FS, PartialsDir, func(partialPath string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || seen[partialPath] {
+				return err
+			}
+			seen[partialPath] = true
+			layerFiles = append(layerFiles, partialPath)
+			return nil
+		})
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		files = append(layerFiles, files...)
+	}
+	return files, nil
+}
+
+// isPartial reports whether a template path is inside the partials directory
+func isPartial(templatePath string) bool {
+	return templatePath == PartialsDir || strings.HasPrefix(templatePath, PartialsDir+"/")
+}
+
+// partialsOnly exposes only the partials directory of a template root
+type partialsOnly struct {
+	root fs.FS
+}
+
+// Open opens a file of the partials directory
+func (p partialsOnly) Open(name string) (fs.File, error) {
+	if name != "." && !isPartial(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return p.root.Open(name)
+}
+
+// ReadDir lists a directory of the partials directory; the root lists only it
+func (p partialsOnly) ReadDir(name string) ([]fs.DirEntry, error) {
+	if name != "." {
+		if !isPartial(name) {
+			return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+		}
+		return fs.ReadDir(p.root, name)
+	}
+
+	entries, err := fs.ReadDir(p.root, ".")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.Name() == PartialsDir && entry.IsDir() {
+			return []fs.DirEntry{entry}, nil
+		}
+	}
+	return nil, nil
+}
+
+// sharedFiles exposes the files of the shared directory that belong to one
+// template set under the names they are rendered as
+type sharedFiles struct {
+	root  fs.FS
+	files map[string]string // rendered template name (Dockerfile.tpl) to path in the root
+}
+
+// loadSharedFiles finds the files of the shared directory named for a set
+func loadSharedFiles(root fs.FS, set string) (sharedFiles, error) {
+	shared := sharedFiles{root: root, files: make(map[string]string)}
+	entries, err := fs.ReadDir(root, SharedDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return shared, nil
+	}
+	if err != nil {
+		return shared, err
+	}
+
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".tpl")
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".tpl") || !strings.HasSuffix(name, "."+set) {
+			continue
+		}
+		shared.files[strings.TrimSuffix(name, "."+set)+".tpl"] = SharedDir + "/" + entry.Name()
+	}
+	return shared, nil
+}
+
+// Open opens a shared file by the name it is rendered as
+func (s sharedFiles) Open(name string) (fs.File, error) {
+	if name == "." {
+		return s.root.Open(SharedDir)
+	}
+	if source, ok := s.files[name]; ok {
+		return s.root.Open(source)
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir lists the shared files of the set; they all sit at the root
+func (s sharedFiles) ReadDir(name string) ([]fs.DirEntry, error) {
+	if name != "." {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	entries := make([]fs.DirEntry, 0, len(s.files))
+	for name, source := range s.files {
+		info, err := fs.Stat(s.root, source)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, renamedEntry{fs.FileInfoToDirEntry(info), name})
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return entries, nil
+}
+
+// renamedEntry is a directory entry listed under another name
+type renamedEntry struct {
+	fs.DirEntry
+	name string
+}
+
+// Name returns the name the entry is listed under
+func (e renamedEntry) Name() string {
+	return e.name
+}
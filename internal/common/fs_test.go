@@ -0,0 +1,184 @@
+package common
+
+import (
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// layeredRoot is a template root where go extends base, with shared files
+// and partials at every level
+var layeredRoot = fstest.MapFS{
+	"base/template.yaml":             {Data: []byte("variables:\n  - name: team\n")},
+	"base/README.md.tpl":             {Data: []byte("base readme")},
+	"base/Makefile.tpl":              {Data: []byte("base makefile")},
+	"base/Dockerfile.tpl":            {Data: []byte("base dockerfile")},
+	"base/docs/base.md.tpl":          {Data: []byte("base docs")},
+	"base/_partials/service.tpl":     {Data: []byte("base service")},
+	"base/_partials/handler.tpl":     {Data: []byte("base handler")},
+	"go/template.yaml":               {Data: []byte("extends: base\n")},
+	"go/main.go.tpl":                 {Data: []byte("go main")},
+	"go/README.md.tpl":               {Data: []byte("go readme")},
+	"go/docs/go.md.tpl":              {Data: []byte("go docs")},
+	"go/_partials/service.tpl":       {Data: []byte("go service")},
+	"common/Dockerfile.go.tpl":       {Data: []byte("shared go dockerfile")},
+	"common/Makefile.go.tpl":         {Data: []byte("shared go makefile")},
+	"common/Makefile.rust.tpl":       {Data: []byte("shared rust makefile")},
+	"common/.gitignore.base.tpl":     {Data: []byte("shared base gitignore")},
+	"_partials/service.tpl":          {Data: []byte("root service")},
+	"_partials/handler.tpl":          {Data: []byte("root handler")},
+	"_partials/shared/logging.tpl":   {Data: []byte("root logging")},
+	"rust/template.yaml":             {Data: []byte("extends: missing\n")},
+	"loop-a/template.yaml":           {Data: []byte("extends: loop-b\n")},
+	"loop-b/template.yaml":           {Data: []byte("extends: loop-a\n")},
+	"nested/template.yaml":           {Data: []byte("extends: base/docs\n")},
+	"broken/template.yaml":           {Data: []byte("extends: [\n")},
+	"self/template.yaml":             {Data: []byte("extends: self\n")},
+	"standalone/main.go.tpl":         {Data: []byte("standalone main")},
+	"standalone/_partials/other.tpl": {Data: []byte("standalone other")},
+}
+
+func TestTemplateSetLayers(t *testing.T) {
+	set, err := loadTemplateSet(layeredRoot, "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var layers []string
+	for _, layer := range set.layers {
+		layers = append(layers, layer.name)
+	}
+	if want := []string{"go", SharedDir, "base", SharedDir, PartialsDir}; !reflect.DeepEqual(layers, want) {
+		t.Errorf("layers = %q, want %q", layers, want)
+	}
+
+	// The topmost layer that has a file wins
+	for name, want := range map[string]string{
+		"main.go.tpl":           "go main",
+		"README.md.tpl":         "go readme",            // the set over its base
+		"Makefile.tpl":          "shared go makefile",   // the set's shared file over the base
+		"Dockerfile.tpl":        "shared go dockerfile", // likewise
+		".gitignore.tpl":        "shared base gitignore",
+		"docs/base.md.tpl":      "base docs",
+		"template.yaml":         "extends: base\n",
+		"_partials/service.tpl": "go service",
+		"_partials/handler.tpl": "base handler",
+	} {
+		content, err := fs.ReadFile(set, name)
+		if err != nil || string(content) != want {
+			t.Errorf("ReadFile(%q) = %q, %v, want %q", name, content, err, want)
+		}
+	}
+	if _, err := fs.ReadFile(set, "Makefile.rust.tpl"); err == nil {
+		t.Error("the shared file of another set is visible")
+	}
+
+	// Directories list the files of every layer
+	var names []string
+	err = fs.WalkDir(set, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			names = append(names, name)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		".gitignore.tpl", "Dockerfile.tpl", "Makefile.tpl", "README.md.tpl",
+		"_partials/handler.tpl", "_partials/service.tpl", "_partials/shared/logging.tpl",
+		"docs/base.md.tpl", "docs/go.md.tpl", "main.go.tpl", "template.yaml",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("files = %q, want %q", names, want)
+	}
+
+	// Manifests are read bases first
+	var manifests []string
+	for _, layer := range manifestLayers(set) {
+		manifests = append(manifests, layer.name)
+	}
+	if want := []string{"base", "go"}; !reflect.DeepEqual(manifests, want) {
+		t.Errorf("manifest layers = %q, want %q", manifests, want)
+	}
+}
+
+func TestPartialFiles(t *testing.T) {
+	tests := []struct {
+		set  string
+		want []string // file: content, in parse order, so later definitions win
+	}{
+		{
+			set: "go",
+			want: []string{
+				"_partials/shared/logging.tpl: root logging",
+				"_partials/handler.tpl: base handler", // base's shadows the root's
+				"_partials/service.tpl: go service",   // go's shadows base's and the root's
+			},
+		},
+		{
+			set: "base",
+			want: []string{
+				"_partials/shared/logging.tpl: root logging",
+				"_partials/handler.tpl: base handler",
+				"_partials/service.tpl: base service",
+			},
+		},
+		{
+			set: "standalone",
+			want: []string{
+				"_partials/handler.tpl: root handler",
+				"_partials/service.tpl: root service",
+				"_partials/shared/logging.tpl: root logging",
+				"_partials/other.tpl: standalone other",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.set, func(t *testing.T) {
+			set, err := loadTemplateSet(layeredRoot, test.set)
+			if err != nil {
+				t.Fatal(err)
+			}
+			files, err := partialFiles(set)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, name := range files {
+				content, err := fs.ReadFile(set, name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, name+": "+string(content))
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("partials = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLoadTemplateSetErrors(t *testing.T) {
+	tests := []struct {
+		set     string
+		wantErr string
+	}{
+		{"kotlin", "template set 'kotlin' does not exist"},
+		{"rust", "template set 'rust' extends 'missing', which does not exist"},
+		{"loop-a", "template sets extend each other in a cycle: loop-a -> loop-b -> loop-a"},
+		{"self", "template sets extend each other in a cycle: self -> self"},
+		{"nested", "template set 'nested': invalid template.yaml: extends 'base/docs' is not a template set name"},
+		{"broken", "template set 'broken': invalid template.yaml"},
+	}
+	for _, test := range tests {
+		_, err := loadTemplateSet(layeredRoot, test.set)
+		if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+			t.Errorf("loadTemplateSet(%q) error = %v, want one containing %q", test.set, err, test.wantErr)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package common
+
+import (
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// TemplateFuncs are the functions available to every template, manifest
+// condition and message:
+//
+//	{{camel "order_item"}}        orderItem
+//	{{pascal "order_item"}}       OrderItem
+//	{{snake "orderItem"}}         order_item
+//	{{kebab "orderItem"}}         order-item
+//	{{screaming "orderItem"}}     ORDER_ITEM
+//	{{pluralize "category"}}      categories
+//	{{singularize "addresses"}}   address
+//	{{goIdent "type"}}            type_
+//	{{rustIdent "type"}}          r#type
+//	{{swiftIdent "case"}}         `case`
+//	{{tsIdent "delete"}}          delete_
+//
+// They compose in pipelines, e.g. {{.DomainName | snake | pluralize}} for a
+// table name.
+var TemplateFuncs = template.FuncMap{
+	"lower":       strings.ToLower,
+	"upper":       strings.ToUpper,
+	"camel":       Camel,
+	"pascal":      Pascal,
+	"snake":       Snake,
+	"kebab":       Kebab,
+	"screaming":   Screaming,
+	"pluralize":   Pluralize,
+	"singularize": Singularize,
+	"goIdent":     GoIdent,
+	"rustIdent":   RustIdent,
+	"swiftIdent":  SwiftIdent,
+	"tsIdent":     TSIdent,
+}
+
+// splitWords splits an identifier into words at separators, lower to upper
+// case changes and the end of an acronym ("HTTPServer" is HTTP, Server)
+func splitWords(s string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0:
+			previous := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+	return words
+}
+
+// capitalize upper-cases the first letter of a word and lower-cases the rest
+func capitalize(word string) string {
+	runes := []rune(strings.ToLower(word))
+	if len(runes) > 0 {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+	return string(runes)
+}
+
+// Pascal converts an identifier to PascalCase
+func Pascal(s string) string {
+	words := splitWords(s)
+	for i, word := range words {
+		words[i] = capitalize(word)
+	}
+	return strings.Join(words, "")
+}
+
+// Camel converts an identifier to camelCase
+func Camel(s string) string {
+	words := splitWords(s)
+	for i, word := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(word)
+		} else {
+			words[i] = capitalize(word)
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// Snake converts an identifier to snake_case
+func Snake(s string) string {
+	return strings.ToLower(strings.Join(splitWords(s), "_"))
+}
+
+// Kebab converts an identifier to kebab-case
+func Kebab(s string) string {
+	return strings.ToLower(strings.Join(splitWords(s), "-"))
+}
+
+// Screaming converts an identifier to SCREAMING_SNAKE_CASE
+func Screaming(s string) string {
+	return strings.ToUpper(strings.Join(splitWords(s), "_"))
+}
+
+// inflection is a suffix rule of English plural or singular forms
+type inflection struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// rules builds inflections from pattern/replacement pairs, most specific first
+func rules(pairs ...string) []inflection {
+	inflections := make([]inflection, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		inflections = append(inflections, inflection{regexp.MustCompile(pairs[i]), pairs[i+1]})
+	}
+	return inflections
+}
+
+var (
+	// uncountables have the same singular and plural form
+	uncountables = map[string]bool{
+		"data": true, "deer": true, "equipment": true, "feedback": true, "fish": true,
+		"information": true, "media": true, "metadata": true, "money": true, "news": true,
+		"rice": true, "series": true, "sheep": true, "software": true, "species": true,
+		"staff": true,
+	}
+
+	// irregulars maps singular to plural forms no suffix rule covers
+	irregulars = map[string]string{
+		"person": "people", "man": "men", "woman": "women", "child": "children",
+		"tooth": "teeth", "foot": "feet", "mouse": "mice", "goose": "geese",
+		"ox": "oxen", "criterion": "criteria", "datum": "data", "medium": "media",
+		"leaf": "leaves", "life": "lives", "knife": "knives", "wife": "wives",
+		"half": "halves", "shelf": "shelves", "wolf": "wolves", "thief": "thieves",
+		"movie": "movies", "cookie": "cookies", "cache": "caches", "quiz": "quizzes",
+		"alias": "aliases", "atlas": "atlases", "canvas": "canvases", "gas": "gases",
+	}
+
+	// singulars is irregulars reversed
+	singulars = func() map[string]string {
+		reversed := make(map[string]string, len(irregulars))
+		for singular, plural := range irregulars {
+			reversed[plural] = singular
+		}
+		return reversed
+	}()
+
+	pluralRules = rules(
+		`(matr|vert|ind)(ix|ex)$`, "${1}ices",
+		`(analy|ba|diagno|parenthe|progno|synop|the)sis$`, "${1}ses",
+		`(x|ch|ss|sh|zz|us)$`, "${1}es",
+		`([^aeiouy]|qu)y$`, "${1}ies",
+		`(buffal|tomat|potat|her|ech)o$`, "${1}oes",
+		`$`, "s",
+	)
+
+	singularRules = rules(
+		`(matr)ices$`, "${1}ix",
+		`(vert|ind)ices$`, "${1}ex",
+		`(analy|ba|diagno|parenthe|progno|synop|the)ses$`, "${1}sis",
+		`(status|bus|campus|census|bonus|virus|focus)es$`, "${1}",
+		`(x|ch|ss|sh|zz)es$`, "${1}",
+		`([^aeiouy]|qu)ies$`, "${1}y",
+		`(buffal|tomat|potat|her|ech)oes$`, "${1}o",
+		`(ss|us|is)$`, "${1}",
+		`s$`, "",
+	)
+)
+
+// Pluralize returns the English plural of the last word of an identifier,
+// keeping its case: category -> categories, orderItem -> orderItems,
+// person -> people. Words that are already plural are left alone.
+func Pluralize(s string) string {
+	return inflectLastWord(s, func(word string) string {
+		if plural, ok := irregulars[word]; ok {
+			return plural
+		}
+		if _, ok := singulars[word]; ok {
+			return word
+		}
+		if singular := applyRules(singularRules, word); singular != word && applyRules(pluralRules, singular) == word {
+			return word
+		}
+		return applyRules(pluralRules, word)
+	})
+}
+
+// Singularize returns the English singular of the last word of an
+// identifier, keeping its case: categories -> category, people -> person
+func Singularize(s string) string {
+	return inflectLastWord(s, func(word string) string {
+		if singular, ok := singulars[word]; ok {
+			return singular
+		}
+		if _, ok := irregulars[word]; ok {
+			return word
+		}
+		return applyRules(singularRules, word)
+	})
+}
+
+// applyRules applies the first matching rule to a lower-case word
+func applyRules(inflections []inflection, word string) string {
+	if uncountables[word] {
+		return word
+	}
+	for _, rule := range inflections {
+		if rule.pattern.MatchString(word) {
+			return rule.pattern.ReplaceAllString(word, rule.replacement)
+		}
+	}
+	return word
+}
+
+// inflectLastWord applies an inflection to the last word of s, matching the
+// case of the original word
+func inflectLastWord(s string, inflect func(word string) string) string {
+	words := splitWords(s)
+	if len(words) == 0 {
+		return s
+	}
+	last := words[len(words)-1]
+	start := strings.LastIndex(s, last)
+
+	inflected := inflect(strings.ToLower(last))
+	switch {
+	case len(last) > 1 && last == strings.ToUpper(last):
+		inflected = strings.ToUpper(inflected)
+	case unicode.IsUpper([]rune(last)[0]):
+		inflected = capitalize(inflected)
+	}
+	return s[:start] + inflected + s[start+len(last):]
+}
+
+// Reserved words of the generated languages
+var (
+	goKeywords = keywordSet("break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var")
+
+	rustKeywords = keywordSet("as async await break const continue crate dyn else enum extern false fn for if impl in let loop match mod move mut pub ref return self Self static struct super trait true type unsafe use where while abstract become box do final macro override priv try typeof unsized virtual yield")
+
+	// rustUnrawable cannot be written as raw identifiers (r#self is invalid)
+	rustUnrawable = keywordSet("crate self Self super")
+
+	swiftKeywords = keywordSet("associatedtype class deinit enum extension fileprivate func import init inout internal let open operator private precedencegroup protocol public rethrows static struct subscript typealias var break case catch continue default defer do else fallthrough for guard if in repeat return throw switch where while Any as await false is nil self Self super throws true try")
+
+	tsKeywords = keywordSet("break case catch class const continue debugger default delete do else enum export extends false finally for function if import in instanceof new null return super switch this throw true try typeof var void while with implements interface let package private protected public static yield await")
+)
+
+// keywordSet builds a set from a space separated word list
+func keywordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
+
+// GoIdent makes an identifier usable in Go by appending an underscore to keywords
+func GoIdent(s string) string {
+	if goKeywords[s] {
+		return s + "_"
+	}
+	return s
+}
+
+// RustIdent makes an identifier usable in Rust as a raw identifier (r#type),
+// or with an underscore for the keywords that cannot be raw
+func RustIdent(s string) string {
+	switch {
+	case rustUnrawable[s]:
+		return s + "_"
+	case rustKeywords[s]:
+		return "r#" + s
+	}
+	return s
+}
+
+// SwiftIdent makes an identifier usable in Swift by quoting keywords in backticks
+func SwiftIdent(s string) string {
+	if swiftKeywords[s] {
+		return "`" + s + "`"
+	}
+	return s
+}
+
+// TSIdent makes an identifier usable as a TypeScript variable by appending
+// an underscore to reserved words
+func TSIdent(s string) string {
+	if tsKeywords[s] {
+		return s + "_"
+	}
+	return s
+}
@@ -0,0 +1,124 @@
+package common
+
+import "testing"
+
+func TestCaseConversions(t *testing.T) {
+	tests := []struct {
+		in                                     string
+		camel, pascal, snake, kebab, screaming string
+	}{
+		{"order", "order", "Order", "order", "order", "ORDER"},
+		{"order_item", "orderItem", "OrderItem", "order_item", "order-item", "ORDER_ITEM"},
+		{"orderItem", "orderItem", "OrderItem", "order_item", "order-item", "ORDER_ITEM"},
+		{"OrderItem", "orderItem", "OrderItem", "order_item", "order-item", "ORDER_ITEM"},
+		{"order-item", "orderItem", "OrderItem", "order_item", "order-item", "ORDER_ITEM"},
+		{"ORDER_ITEM", "orderItem", "OrderItem", "order_item", "order-item", "ORDER_ITEM"},
+		{"HTTPServer", "httpServer", "HttpServer", "http_server", "http-server", "HTTP_SERVER"},
+		{"userID", "userId", "UserId", "user_id", "user-id", "USER_ID"},
+		{"address2Line", "address2Line", "Address2Line", "address2_line", "address2-line", "ADDRESS2_LINE"},
+		{"  list orders ", "listOrders", "ListOrders", "list_orders", "list-orders", "LIST_ORDERS"},
+		{"_", "", "", "", "", ""},
+	}
+	for _, test := range tests {
+		for _, c := range []struct {
+			name      string
+			got, want string
+		}{
+			{"Camel", Camel(test.in), test.camel},
+			{"Pascal", Pascal(test.in), test.pascal},
+			{"Snake", Snake(test.in), test.snake},
+			{"Kebab", Kebab(test.in), test.kebab},
+			{"Screaming", Screaming(test.in), test.screaming},
+		} {
+			if c.got != c.want {
+				t.Errorf("%s(%q) = %q, want %q", c.name, test.in, c.got, c.want)
+			}
+		}
+	}
+}
+
+func TestInflections(t *testing.T) {
+	tests := []struct {
+		singular, plural string
+	}{
+		{"order", "orders"},
+		{"category", "categories"},
+		{"day", "days"},
+		{"address", "addresses"},
+		{"box", "boxes"},
+		{"branch", "branches"},
+		{"status", "statuses"},
+		{"bus", "buses"},
+		{"quiz", "quizzes"},
+		{"analysis", "analyses"},
+		{"matrix", "matrices"},
+		{"index", "indices"},
+		{"tomato", "tomatoes"},
+		{"photo", "photos"},
+		{"person", "people"},
+		{"child", "children"},
+		{"mouse", "mice"},
+		{"leaf", "leaves"},
+		{"criterion", "criteria"},
+		{"movie", "movies"},
+		{"alias", "aliases"},
+		{"news", "news"},
+		{"series", "series"},
+		{"metadata", "metadata"},
+		{"orderItem", "orderItems"},
+		{"order_category", "order_categories"},
+		{"SalesPerson", "SalesPeople"},
+		{"USER", "USERS"},
+	}
+	for _, test := range tests {
+		if got := Pluralize(test.singular); got != test.plural {
+			t.Errorf("Pluralize(%q) = %q, want %q", test.singular, got, test.plural)
+		}
+		if got := Singularize(test.plural); got != test.singular {
+			t.Errorf("Singularize(%q) = %q, want %q", test.plural, got, test.singular)
+		}
+		// Inflecting twice changes nothing
+		if got := Pluralize(test.plural); got != test.plural {
+			t.Errorf("Pluralize(%q) = %q, want it unchanged", test.plural, got)
+		}
+		if got := Singularize(test.singular); got != test.singular {
+			t.Errorf("Singularize(%q) = %q, want it unchanged", test.singular, got)
+		}
+	}
+}
+
+func TestIdents(t *testing.T) {
+	tests := []struct {
+		in                                      string
+		goIdent, rustIdent, swiftIdent, tsIdent string
+	}{
+		{"name", "name", "name", "name", "name"},
+		{"type", "type_", "r#type", "type", "type"},
+		{"func", "func_", "func", "`func`", "func"},
+		{"match", "match", "r#match", "match", "match"},
+		{"self", "self", "self_", "`self`", "self"},
+		{"Self", "Self", "Self_", "`Self`", "Self"},
+		{"super", "super", "super_", "`super`", "super_"},
+		{"crate", "crate", "crate_", "crate", "crate"},
+		{"case", "case_", "case", "`case`", "case_"},
+		{"delete", "delete", "delete", "delete", "delete_"},
+		{"default", "default_", "default", "`default`", "default_"},
+		{"async", "async", "r#async", "async", "async"},
+		{"module", "module", "module", "module", "module"},
+	}
+	for _, test := range tests {
+		for _, c := range []struct {
+			name      string
+			got, want string
+		}{
+			{"GoIdent", GoIdent(test.in), test.goIdent},
+			{"RustIdent", RustIdent(test.in), test.rustIdent},
+			{"SwiftIdent", SwiftIdent(test.in), test.swiftIdent},
+			{"TSIdent", TSIdent(test.in), test.tsIdent},
+		} {
+			if c.got != c.want {
+				t.Errorf("%s(%q) = %q, want %q", c.name, test.in, c.got, c.want)
+			}
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package common
 
 import (
+	"fmt"
+	"io/fs"
 	"strings"
 )
 
@@ -9,46 +11,179 @@ type Generator interface {
 	Generate(config *GeneratorConfig) error
 	GetName() string
 	GetDescription() string
+	GetMetadata() GeneratorMetadata
+}
+
+// Feature is a highlighted capability listed in a generator's help text
+type Feature struct {
+	Name   string
+	Detail string
+	Files  []string // files every generated project gets for the feature (e.g. Dockerfile), checked on generation
+}
+
+// GeneratorMetadata describes a generator so the CLI can build its command
+type GeneratorMetadata struct {
+	DisplayName        string    // Human readable name (e.g. "Go Gin")
+	Icon               string    // Emoji shown in help output
+	Summary            string    // One-line stack summary for generator listings
+	DefaultPort        string    // Port used when none is given
+	SupportedDatabases []string  // Supported database types; the first one is the default
+	SupportsGRPC       bool      // Whether the --grpc flag is available
+	IDType             string    // Field type of the ids of generated entities (e.g. int, uuid)
+	NextSteps          []string  // Commands suggested after generation
+	Hooks              []Hook    // Steps run in the project after generation, see ProjectHooks
+	ProtoHooks         []Hook    // Steps run before Hooks in projects generated from a .proto file (e.g. compiling it)
+	Features           []Feature // "What you'll get" section of the help text
+	Example            string    // Example invocation
+	NameExamples       []string  // Suggested project names for validation hints
+
+	// Detection of projects generated by this generator (used by `ccin add`)
+	Detect      []ProjectMarker // all must match for a directory to be such a project
+	ProjectName ProjectMarker   // first capture group of Pattern is the project name
+	GRPCMarker  ProjectMarker   // matches when the project was generated with --grpc
+}
+
+// ProjectMarker matches a file of a generated project
+type ProjectMarker struct {
+	File    string // path relative to the project root
+	Pattern string // optional regular expression the file content must match
+}
+
+// Artifacts lists the files the generator's features promise, in feature order
+func (m GeneratorMetadata) Artifacts() []string {
+	var artifacts []string
+	for _, feature := range m.Features {
+		artifacts = append(artifacts, feature.Files...)
+	}
+	return artifacts
+}
+
+// DefaultDatabase returns the database used when none is given
+func (m GeneratorMetadata) DefaultDatabase() string {
+	if len(m.SupportedDatabases) == 0 {
+		return "none"
+	}
+	return m.SupportedDatabases[0]
+}
+
+// ApplyDefaults fills the port and database type of config when unset
+func (m GeneratorMetadata) ApplyDefaults(config *GeneratorConfig) {
+	if config.Port == "" {
+		config.Port = m.DefaultPort
+	}
+	if config.DatabaseType == "" {
+		config.DatabaseType = m.DefaultDatabase()
+	}
+}
+
+// SupportsDatabase reports whether the generator can target the database type
+func (m GeneratorMetadata) SupportsDatabase(database string) bool {
+	for _, supported := range m.SupportedDatabases {
+		if supported == database {
+			return true
+		}
+	}
+	return false
 }
 
 // GeneratorConfig holds configuration for generators
 type GeneratorConfig struct {
-	ProjectName  string
-	DomainName   string
-	GCPProject   string
-	OutputDir    string
-	TemplateDir  string
-	WithGRPC     bool
-	DatabaseType string
-	Port         string
+	ProjectName    string
+	Domains        []Domain // resources rendered by the per-domain templates
+	GCPProject     string
+	OutputDir      string
+	TemplateSource string // optional template root (directory, git or archive source) overriding the embedded templates
+	WithGRPC       bool
+	DatabaseType   string
+	Port           string
+	ModulePrefix   string          // prefix of the project's import path (e.g. github.com/acme)
+	Author         string          // project author recorded in package manifests
+	License        string          // license identifier (e.g. MIT)
+	Contract       string          // file name of the API contract the domains' operations come from, if any
+	Proto          *ProtoContract  // services of the contract when it is a .proto file
+	Vars           map[string]any  // custom template variables (--set, --values), see Manifest
+	Conflicts      ConflictHandler // handling of files that already exist in OutputDir
+}
+
+// Templates returns the template set for the named generator, read from
+// TemplateSource when set and from the embedded templates otherwise
+func (c *GeneratorConfig) Templates(generator string) (fs.FS, error) {
+	return LoadTemplateFS(c.TemplateSource, generator)
 }
 
 // PrepareTemplateData prepares data for template processing
 func PrepareTemplateData(config *GeneratorConfig) *TemplateData {
-	return &TemplateData{
+	data := &TemplateData{
 		ProjectName:  config.ProjectName,
-		DomainName:   config.DomainName,
-		DomainTitle:  strings.Title(config.DomainName),
-		DomainUpper:  strings.ToUpper(config.DomainName),
-		DomainLower:  strings.ToLower(config.DomainName),
 		GCPProject:   config.GCPProject,
 		WithGRPC:     config.WithGRPC,
 		Port:         config.Port,
 		DatabaseType: config.DatabaseType,
+		ModulePath:   config.ProjectName,
+		Author:       config.Author,
+		License:      config.License,
+		Contract:     config.Contract,
+		Proto:        config.Proto,
+		Vars:         config.Vars,
+	}
+	if prefix := strings.Trim(config.ModulePrefix, "/"); prefix != "" {
+		data.ModulePath = prefix + "/" + config.ProjectName
+	}
+
+	for _, domain := range config.Domains {
+		// Domains the contract does not declare get CRUD operations, so
+		// every route of the project is rendered the same way
+		if config.Contract != "" && len(domain.Operations) == 0 {
+			domain.Operations = DefaultOperations(domain.Name)
+		}
+		data.Domains = append(data.Domains, prepareDomain(domain))
+	}
+	if len(data.Domains) > 0 {
+		data.DomainData = data.Domains[0]
+	}
+
+	return data
+}
+
+// prepareDomain derives the naming variants of a domain and numbers its fields
+func prepareDomain(domain Domain) DomainData {
+	return DomainData{
+		DomainName:  domain.Name,
+		DomainTitle: Pascal(domain.Name),
+		DomainUpper: strings.ToUpper(domain.Name),
+		DomainLower: strings.ToLower(domain.Name),
+		Fields:      prepareFields(domain.Fields),
+		Operations:  domain.Operations,
 	}
 }
 
+// prepareFields copies the configured fields (or the defaults) and numbers them
+func prepareFields(configured []Field) Fields {
+	if len(configured) == 0 {
+		configured = DefaultFields()
+	}
+
+	fields := make(Fields, len(configured))
+	for i, field := range configured {
+		field.Position = i + 1
+		fields[i] = field
+	}
+	return fields
+}
+
 // BaseGenerator provides common functionality for all generators
 type BaseGenerator struct {
 	name        string
 	description string
+	metadata    GeneratorMetadata
 }
 
 // NewBaseGenerator creates a new base generator
-func NewBaseGenerator(name, description string) *BaseGenerator {
+func NewBaseGenerator(name, description string, metadata GeneratorMetadata) *BaseGenerator {
 	return &BaseGenerator{
 		name:        name,
 		description: description,
+		metadata:    metadata,
 	}
 }
 
@@ -61,3 +196,45 @@ func (bg *BaseGenerator) GetName() string {
 func (bg *BaseGenerator) GetDescription() string {
 	return bg.description
 }
+
+// GetMetadata returns the generator metadata
+func (bg *BaseGenerator) GetMetadata() GeneratorMetadata {
+	return bg.metadata
+}
+
+// Generate renders the generator's templates into config.OutputDir and
+// records how the project was generated in its lock file
+func (bg *BaseGenerator) Generate(config *GeneratorConfig) error {
+	templates, processor, err := newProjectProcessor(bg, config)
+	if err != nil {
+		return err
+	}
+	processor.SetConflictHandler(config.Conflicts)
+
+	files, err := processor.ProcessDirectory(PrepareTemplateData(config))
+	if err != nil {
+		return fmt.Errorf("failed to process %s templates: %w", bg.metadata.DisplayName, err)
+	}
+
+	// Record how the project was generated
+	if err := WriteLock(bg.name, config, templates, files); err != nil {
+		return fmt.Errorf("failed to write %s: %w", LockFile, err)
+	}
+	return nil
+}
+
+// newProjectProcessor applies the generator's defaults to config and
+// returns its templates (embedded unless overridden) with a processor
+// rendering them into config.OutputDir
+func newProjectProcessor(generator Generator, config *GeneratorConfig) (fs.FS, *TemplateProcessor, error) {
+	metadata := generator.GetMetadata()
+	metadata.ApplyDefaults(config)
+
+	templates, err := config.Templates(generator.GetName())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load %s templates: %w", metadata.DisplayName, err)
+	}
+	processor := NewTemplateProcessor(templates, config.OutputDir)
+	processor.SetRequiredFiles(metadata.Artifacts())
+	return templates, processor, nil
+}
@@ -0,0 +1,245 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Hook is a post-generation step run in the generated project, such as
+// installing dependencies or formatting the code
+type Hook struct {
+	Name    string   // what the step does, e.g. "Install dependencies"
+	Command []string // program and arguments, run without a shell
+
+	run func(dir string, output io.Writer) error // built-in steps run instead of Command
+}
+
+// String returns the command line of the hook
+func (h Hook) String() string {
+	if h.run != nil {
+		return h.Name
+	}
+	return strings.Join(h.Command, " ")
+}
+
+// HookStatus is the outcome of a hook
+type HookStatus string
+
+// Hook outcomes reported by HookRunner
+const (
+	HookSucceeded HookStatus = "succeeded"
+	HookFailed    HookStatus = "failed"  // the project is left as the step left it
+	HookSkipped   HookStatus = "skipped" // the program is not installed, or there was nothing to do
+)
+
+// HookResult is the outcome of one hook
+type HookResult struct {
+	Hook   Hook
+	Status HookStatus
+	Reason string // why the hook was skipped
+	Err    error  // why the hook failed
+}
+
+// skipHook is returned by a step that had nothing to do
+type skipHook string
+
+func (s skipHook) Error() string {
+	return string(s)
+}
+
+// HookRunner runs post-generation hooks one after the other in a project
+// directory. A failed step is reported and the following steps still run;
+// the generated files are never removed.
+type HookRunner struct {
+	// Started, when set, is called before each step and returns where the
+	// step's output is streamed while it runs
+	Started func(hook Hook) io.Writer
+
+	// Finished, when set, is told the outcome of each step
+	Finished func(result HookResult)
+}
+
+// Run runs the hooks in dir and returns their outcomes in order
+func (r HookRunner) Run(dir string, hooks []Hook) []HookResult {
+	results := make([]HookResult, 0, len(hooks))
+	for _, hook := range hooks {
+		result := r.runHook(dir, hook)
+		if r.Finished != nil {
+			r.Finished(result)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// runHook runs one hook, streaming its output to the Started writer
+func (r HookRunner) runHook(dir string, hook Hook) HookResult {
+	result := HookResult{Hook: hook, Status: HookSucceeded}
+
+	if hook.run == nil {
+		if len(hook.Command) == 0 {
+			result.Status, result.Err = HookFailed, fmt.Errorf("hook '%s' has no command", hook.Name)
+			return result
+		}
+		if _, err := exec.LookPath(hook.Command[0]); err != nil {
+			result.Status, result.Reason = HookSkipped, hook.Command[0]+" is not installed"
+			return result
+		}
+	}
+
+	output := io.Discard
+	if r.Started != nil {
+		if writer := r.Started(hook); writer != nil {
+			output = writer
+		}
+	}
+
+	var err error
+	if hook.run != nil {
+		err = hook.run(dir, output)
+	} else {
+		err = runCommand(dir, output, hook.Command...)
+	}
+
+	var skip skipHook
+	switch {
+	case errors.As(err, &skip):
+		result.Status, result.Reason = HookSkipped, skip.Error()
+	case err != nil:
+		result.Status, result.Err = HookFailed, err
+	}
+	return result
+}
+
+// runCommand runs a program in dir with its output sent to output
+func runCommand(dir string, output io.Writer, args ...string) error {
+	command := exec.Command(args[0], args[1:]...)
+	command.Dir = dir
+	command.Stdout = output
+	command.Stderr = output
+	if err := command.Run(); err != nil {
+		return fmt.Errorf("%s: %w", strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+// Commit messages of the git hooks
+const (
+	gitInitialCommit = "Initial commit (generated by ccin)"
+	gitHooksCommit   = "Apply post-generation hooks"
+)
+
+// ProjectHooks returns the post-generation steps for a generator: a git
+// repository with the project as generated is created first, then the
+// generator's own hooks run, and what they changed is committed on top. The
+// first commit holds exactly the files recorded in ccin.lock, so upgrades
+// find the original rendering in the project's history.
+func ProjectHooks(generator Generator, config *GeneratorConfig) []Hook {
+	hooks := []Hook{{Name: "Initialize git repository", run: gitInit}}
+	hooks = append(hooks, generatorHooks(generator, config)...)
+	return append(hooks, Hook{Name: "Commit post-generation changes", run: gitCommitChanges})
+}
+
+// NextSteps returns the commands suggested after generating a project: the
+// generator's hooks for a .proto contract, if any, then its next steps
+func NextSteps(generator Generator, config *GeneratorConfig) []string {
+	metadata := generator.GetMetadata()
+	var steps []string
+	if config.Proto != nil {
+		for _, hook := range metadata.ProtoHooks {
+			steps = append(steps, hook.String())
+		}
+	}
+	return append(steps, metadata.NextSteps...)
+}
+
+// generatorHooks returns the generator's own hooks for a project
+func generatorHooks(generator Generator, config *GeneratorConfig) []Hook {
+	metadata := generator.GetMetadata()
+	if config.Proto == nil {
+		return metadata.Hooks
+	}
+	return slices.Concat(metadata.ProtoHooks, metadata.Hooks)
+}
+
+// gitInit creates a repository in dir with the generated files committed,
+// unless dir already belongs to a repository
+func gitInit(dir string, output io.Writer) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return skipHook("git is not installed")
+	}
+	if _, ok := gitTopLevel(dir); ok {
+		return skipHook("already inside a git repository")
+	}
+
+	for _, args := range [][]string{
+		{"git", "init", "--quiet"},
+		{"git", "add", "--all"},
+		{"git", "commit", "--quiet", "-m", gitInitialCommit},
+	} {
+		if err := runCommand(dir, output, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gitCommitChanges commits what the hooks changed in the repository gitInit
+// created; a project inside another repository is left uncommitted
+func gitCommitChanges(dir string, output io.Writer) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return skipHook("git is not installed")
+	}
+	topLevel, ok := gitTopLevel(dir)
+	if absolute, err := filepath.Abs(dir); !ok || err != nil || !samePath(topLevel, absolute) {
+		return skipHook("the project has no git repository of its own")
+	}
+
+	if exec.Command("git", "-C", dir, "rev-parse", "--verify", "--quiet", "HEAD").Run() != nil {
+		return skipHook("the initial commit is missing")
+	}
+
+	status, err := exec.Command("git", "-C", dir, "status", "--porcelain").Output()
+	if err != nil {
+		return fmt.Errorf("git status: %w", err)
+	}
+	if len(strings.TrimSpace(string(status))) == 0 {
+		return skipHook("nothing changed")
+	}
+
+	for _, args := range [][]string{
+		{"git", "add", "--all"},
+		{"git", "commit", "--quiet", "-m", gitHooksCommit},
+	} {
+		if err := runCommand(dir, output, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gitTopLevel returns the root of the git repository dir belongs to
+func gitTopLevel(dir string) (string, bool) {
+	output, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(output)), true
+}
+
+// samePath reports whether two paths name the same directory, resolving
+// symbolic links (e.g. /tmp on macOS)
+func samePath(a, b string) bool {
+	if resolved, err := filepath.EvalSymlinks(a); err == nil {
+		a = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(b); err == nil {
+		b = resolved
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
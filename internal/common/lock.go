@@ -0,0 +1,228 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LockFile is the manifest written into every generated project
+const LockFile = "ccin.lock"
+
+// lockFormat is bumped when the lock file layout changes incompatibly
+const lockFormat = 1
+
+// Version is the ccin version recorded in lock files, set at startup from
+// the build information
+var Version = "dev"
+
+// Lock records how a project was generated: the generator, the ccin version,
+// the configuration and a hash of every generated file as rendered by the
+// templates. It is the reference for upgrades and drift detection.
+type Lock struct {
+	Format    int               `json:"format"`
+	Version   string            `json:"ccin_version"`
+	Generator string            `json:"generator"`
+	Config    LockConfig        `json:"config"`
+	Templates string            `json:"templates"` // digest of the template set
+	Files     map[string]string `json:"files"`     // slash-separated path -> content hash
+}
+
+// LockConfig is the part of GeneratorConfig needed to render the project again
+type LockConfig struct {
+	ProjectName    string         `json:"project_name"`
+	Domains        []Domain       `json:"domains"`
+	GCPProject     string         `json:"gcp_project,omitempty"`
+	TemplateSource string         `json:"template_source,omitempty"`
+	WithGRPC       bool           `json:"with_grpc"`
+	DatabaseType   string         `json:"database_type"`
+	Port           string         `json:"port"`
+	ModulePrefix   string         `json:"module_prefix,omitempty"`
+	Author         string         `json:"author,omitempty"`
+	License        string         `json:"license,omitempty"`
+	Contract       string         `json:"contract,omitempty"`
+	Proto          *ProtoContract `json:"proto,omitempty"`
+	Vars           map[string]any `json:"vars,omitempty"`
+}
+
+// NewLock builds the lock for files rendered from templates with config
+func NewLock(generator string, config *GeneratorConfig, templates fs.FS, files []RenderedFile) (*Lock, error) {
+	digest, err := TemplatesDigest(templates)
+	if err != nil {
+		return nil, err
+	}
+	source, err := AbsTemplateSource(config.TemplateSource)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &Lock{
+		Format:    lockFormat,
+		Version:   Version,
+		Generator: generator,
+		Config: LockConfig{
+			ProjectName:    config.ProjectName,
+			Domains:        config.Domains,
+			GCPProject:     config.GCPProject,
+			TemplateSource: source,
+			WithGRPC:       config.WithGRPC,
+			DatabaseType:   config.DatabaseType,
+			Port:           config.Port,
+			ModulePrefix:   config.ModulePrefix,
+			Author:         config.Author,
+			License:        config.License,
+			Contract:       config.Contract,
+			Proto:          config.Proto,
+			Vars:           config.Vars,
+		},
+		Templates: digest,
+		Files:     make(map[string]string, len(files)),
+	}
+	for _, file := range files {
+		lock.Files[filepath.ToSlash(file.Path)] = HashContent(file.Content)
+	}
+	return lock, nil
+}
+
+// GeneratorConfig returns the recorded configuration for the project in dir
+func (l *Lock) GeneratorConfig(dir string) *GeneratorConfig {
+	return &GeneratorConfig{
+		ProjectName:    l.Config.ProjectName,
+		Domains:        l.Config.Domains,
+		GCPProject:     l.Config.GCPProject,
+		OutputDir:      dir,
+		TemplateSource: l.Config.TemplateSource,
+		WithGRPC:       l.Config.WithGRPC,
+		DatabaseType:   l.Config.DatabaseType,
+		Port:           l.Config.Port,
+		ModulePrefix:   l.Config.ModulePrefix,
+		Author:         l.Config.Author,
+		License:        l.Config.License,
+		Contract:       l.Config.Contract,
+		Proto:          l.Config.Proto,
+		Vars:           l.Config.Vars,
+	}
+}
+
+// Marshal returns the lock file content
+func (l *Lock) Marshal() ([]byte, error) {
+	content, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(content, '\n'), nil
+}
+
+// File returns the lock as a generated file
+func (l *Lock) File() (RenderedFile, error) {
+	content, err := l.Marshal()
+	return RenderedFile{Path: LockFile, Content: content}, err
+}
+
+// WriteLock records in config.OutputDir how files were generated from templates
+func WriteLock(generator string, config *GeneratorConfig, templates fs.FS, files []RenderedFile) error {
+	lock, err := NewLock(generator, config, templates, files)
+	if err != nil {
+		return err
+	}
+	file, err := lock.File()
+	if err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(config.OutputDir, file.Path), file.Content)
+}
+
+// ReadLock reads the lock file of the project in dir
+func ReadLock(dir string) (*Lock, error) {
+	content, err := os.ReadFile(filepath.Join(dir, LockFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var lock Lock
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", LockFile, err)
+	}
+	if lock.Format > lockFormat {
+		return nil, fmt.Errorf("%s was written by a newer ccin (%s), please upgrade", LockFile, lock.Version)
+	}
+	return &lock, nil
+}
+
+// Project is a generated project opened through its lock file, with the
+// templates it renders from today
+type Project struct {
+	Dir       string
+	Lock      *Lock
+	Generator Generator
+	Config    *GeneratorConfig // recorded configuration
+	Templates fs.FS
+}
+
+// OpenProject loads the project in dir from its lock file. The templates are
+// read from templateSource when set, otherwise from the recorded template
+// source or the embedded templates.
+func OpenProject(dir, templateSource string) (*Project, error) {
+	lock, err := ReadLock(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no %s in %s; the project was not generated by this version of ccin", LockFile, dir)
+		}
+		return nil, err
+	}
+
+	generator, err := Registry.Get(lock.Generator)
+	if err != nil {
+		return nil, err
+	}
+
+	config := lock.GeneratorConfig(dir)
+	if templateSource != "" {
+		if config.TemplateSource, err = AbsTemplateSource(templateSource); err != nil {
+			return nil, err
+		}
+	}
+	templates, err := config.Templates(generator.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Project{Dir: dir, Lock: lock, Generator: generator, Config: config, Templates: templates}, nil
+}
+
+// Render renders the project's templates in memory with the recorded configuration
+func (p *Project) Render() ([]RenderedFile, error) {
+	return NewTemplateProcessor(p.Templates, p.Dir).RenderDirectory(PrepareTemplateData(p.Config))
+}
+
+// HashContent returns the hash recorded for a generated file
+func HashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// TemplatesDigest hashes every template path and content of a template set,
+// so a lock tells whether the templates changed since generation
+func TemplatesDigest(templates fs.FS) (string, error) {
+	hash := sha256.New()
+	err := fs.WalkDir(templates, ".", func(templatePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		content, err := fs.ReadFile(templates, templatePath)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(hash, "%s\x00%d\x00", templatePath, len(content))
+		hash.Write(content)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,483 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFile is the optional manifest at the root of a template set. It is
+// read by the template processor and never rendered into a project.
+const ManifestFile = "template.yaml"
+
+// VariableType is the type of a template variable
+type VariableType string
+
+// Variable types a manifest can declare
+const (
+	VariableString VariableType = "string"
+	VariableBool   VariableType = "bool"
+	VariableInt    VariableType = "int"
+	VariableFloat  VariableType = "float"
+)
+
+// VariableTypes lists the supported variable types
+var VariableTypes = []VariableType{VariableString, VariableBool, VariableInt, VariableFloat}
+
+// Manifest describes a template set: the custom variables its templates read
+// from .Vars, which files are rendered under which conditions, and messages
+// shown once the project is generated. A set can extend a base set of the
+// same template root, overriding the base's files with its own.
+//
+//	extends: go-core
+//	variables:
+//	  - name: team
+//	    prompt: Owning team
+//	    pattern: ^[a-z][a-z0-9-]*$
+//	    required: true
+//	  - name: replicas
+//	    type: int
+//	    default: 2
+//	    min: 1
+//	files:
+//	  - include: [internal/grpc/**]
+//	    when: .WithGRPC
+//	  - exclude: [docs/**]
+//	    when: not .Vars.docs
+//	messages:
+//	  - text: Register the service with team {{.Vars.team}}
+type Manifest struct {
+	Name        string     `yaml:"name"`
+	Description string     `yaml:"description"`
+	Extends     string     `yaml:"extends"` // base template set whose files this set overrides
+	Variables   []Variable `yaml:"variables"`
+	Files       []FileRule `yaml:"files"`
+	Messages    []Message  `yaml:"messages"`
+}
+
+// Variable is a custom template variable
+type Variable struct {
+	Name     string       `yaml:"name"`
+	Type     VariableType `yaml:"type"`     // string when empty
+	Default  any          `yaml:"default"`  // used when no value is given
+	Prompt   string       `yaml:"prompt"`   // question asked for the value
+	Required bool         `yaml:"required"` // a value or default must be present
+	Pattern  string       `yaml:"pattern"`  // regular expression a string must match
+	Choices  []string     `yaml:"choices"`  // allowed string values
+	Min      *float64     `yaml:"min"`      // lower bound of a number
+	Max      *float64     `yaml:"max"`      // upper bound of a number
+
+	pattern *regexp.Regexp
+}
+
+// FileRule conditions template files on the template data. Files matching
+// Include are rendered only when the condition holds; files matching Exclude
+// are skipped when it holds. A rule without a condition always holds.
+// Patterns match template paths without the .tpl suffix; "**" matches any
+// number of directories.
+type FileRule struct {
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+	When    string   `yaml:"when"` // template condition, e.g. ".WithGRPC" or "eq .Vars.ci \"github\""
+
+	condition *template.Template
+}
+
+// Message is shown after generation when its condition holds. The text is a
+// template rendered with the project's template data.
+type Message struct {
+	Text string `yaml:"text"`
+	When string `yaml:"when"`
+
+	text      *template.Template
+	condition *template.Template
+}
+
+// LoadManifest reads and checks the manifest of a template set. A template
+// set without one gets an empty manifest. The manifest of a set that extends
+// a base set is merged onto the base's, see Manifest.merge.
+func LoadManifest(templates fs.FS) (*Manifest, error) {
+	manifest := &Manifest{}
+	for _, layer := range manifestLayers(templates) {
+		content, err := fs.ReadFile(layer, ManifestFile)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		file := ManifestFile
+		if layer.name != "" {
+			file = layer.name + "/" + ManifestFile
+		}
+		var layerManifest Manifest
+		if err := yaml.Unmarshal(content, &layerManifest); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", file, err)
+		}
+		if err := layerManifest.compile(); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", file, err)
+		}
+		manifest.merge(&layerManifest)
+	}
+	return manifest, nil
+}
+
+// merge applies the manifest of a set onto the manifest of its base: the
+// set's variables replace base variables of the same name, its file rules
+// apply after the base's, and its messages follow the base's
+func (m *Manifest) merge(set *Manifest) {
+	if set.Name != "" {
+		m.Name = set.Name
+	}
+	if set.Description != "" {
+		m.Description = set.Description
+	}
+	m.Extends = set.Extends
+
+	for _, variable := range set.Variables {
+		index := slices.IndexFunc(m.Variables, func(v Variable) bool { return v.Name == variable.Name })
+		if index >= 0 {
+			m.Variables[index] = variable
+		} else {
+			m.Variables = append(m.Variables, variable)
+		}
+	}
+	m.Files = append(m.Files, set.Files...)
+	m.Messages = append(m.Messages, set.Messages...)
+}
+
+// compile checks the declarations and parses patterns and conditions
+func (m *Manifest) compile() error {
+	seen := make(map[string]bool)
+	for i := range m.Variables {
+		variable := &m.Variables[i]
+		if variable.Name == "" {
+			return fmt.Errorf("variable %d has no name", i+1)
+		}
+		if seen[variable.Name] {
+			return fmt.Errorf("variable '%s' is declared twice", variable.Name)
+		}
+		seen[variable.Name] = true
+
+		if variable.Type == "" {
+			variable.Type = VariableString
+		}
+		if !slices.Contains(VariableTypes, variable.Type) {
+			return fmt.Errorf("variable '%s' has unknown type '%s' (use %s)", variable.Name, variable.Type, joinTypes(VariableTypes))
+		}
+		if variable.Pattern != "" {
+			pattern, err := regexp.Compile(variable.Pattern)
+			if err != nil {
+				return fmt.Errorf("variable '%s': invalid pattern: %w", variable.Name, err)
+			}
+			variable.pattern = pattern
+		}
+		if variable.Default != nil {
+			value, err := variable.Check(variable.Default)
+			if err != nil {
+				return fmt.Errorf("default of %w", err)
+			}
+			variable.Default = value
+		}
+	}
+
+	for i := range m.Files {
+		rule := &m.Files[i]
+		if len(rule.Include) == 0 && len(rule.Exclude) == 0 {
+			return fmt.Errorf("file rule %d has neither include nor exclude patterns", i+1)
+		}
+		for _, pattern := range append(slices.Clone(rule.Include), rule.Exclude...) {
+			if _, err := path.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+				return fmt.Errorf("file rule %d: invalid pattern '%s'", i+1, pattern)
+			}
+		}
+		condition, err := parseCondition(rule.When)
+		if err != nil {
+			return fmt.Errorf("file rule %d: %w", i+1, err)
+		}
+		rule.condition = condition
+	}
+
+	for i := range m.Messages {
+		message := &m.Messages[i]
+		text, err := template.New("message").Funcs(TemplateFuncs).Parse(message.Text)
+		if err != nil {
+			return fmt.Errorf("message %d: %w", i+1, err)
+		}
+		condition, err := parseCondition(message.When)
+		if err != nil {
+			return fmt.Errorf("message %d: %w", i+1, err)
+		}
+		message.text, message.condition = text, condition
+	}
+	return nil
+}
+
+// Check validates a value for the variable and returns it with the
+// variable's Go type (string, bool, int or float64). Strings, as given with
+// --set, are parsed for bool and number variables.
+func (v *Variable) Check(value any) (any, error) {
+	switch v.Type {
+	case VariableBool:
+		switch b := value.(type) {
+		case bool:
+			return b, nil
+		case string:
+			if parsed, err := strconv.ParseBool(b); err == nil {
+				return parsed, nil
+			}
+		}
+	case VariableInt:
+		switch n := value.(type) {
+		case int:
+			return n, v.checkRange(float64(n))
+		case float64:
+			if n == float64(int(n)) {
+				return int(n), v.checkRange(n)
+			}
+		case string:
+			if parsed, err := strconv.Atoi(n); err == nil {
+				return parsed, v.checkRange(float64(parsed))
+			}
+		}
+	case VariableFloat:
+		switch n := value.(type) {
+		case int:
+			return float64(n), v.checkRange(float64(n))
+		case float64:
+			return n, v.checkRange(n)
+		case string:
+			if parsed, err := strconv.ParseFloat(n, 64); err == nil {
+				return parsed, v.checkRange(parsed)
+			}
+		}
+	default:
+		switch s := value.(type) {
+		case string:
+			return s, v.checkString(s)
+		case bool, int, float64:
+			// e.g. "team: 42" in a values file
+			return fmt.Sprint(s), v.checkString(fmt.Sprint(s))
+		}
+	}
+	return nil, fmt.Errorf("variable '%s': %v is not a valid %s", v.Name, value, v.Type)
+}
+
+// checkString applies the pattern and choices of a string variable
+func (v *Variable) checkString(value string) error {
+	if len(v.Choices) > 0 && !slices.Contains(v.Choices, value) {
+		return fmt.Errorf("variable '%s': '%s' is not one of %s", v.Name, value, strings.Join(v.Choices, ", "))
+	}
+	if v.pattern != nil && !v.pattern.MatchString(value) {
+		return fmt.Errorf("variable '%s': '%s' does not match %s", v.Name, value, v.Pattern)
+	}
+	return nil
+}
+
+// checkRange applies the bounds of a number variable
+func (v *Variable) checkRange(value float64) error {
+	if v.Min != nil && value < *v.Min {
+		return fmt.Errorf("variable '%s': %v is less than %v", v.Name, value, *v.Min)
+	}
+	if v.Max != nil && value > *v.Max {
+		return fmt.Errorf("variable '%s': %v is greater than %v", v.Name, value, *v.Max)
+	}
+	return nil
+}
+
+// Resolve returns a copy of data whose Vars hold every declared variable:
+// given values are validated and converted to the declared type, missing
+// ones take their default. Values of undeclared variables that read as a
+// bool or number are converted to one.
+func (m *Manifest) Resolve(data *TemplateData) (*TemplateData, error) {
+	resolved := *data
+	resolved.Vars = make(map[string]any, len(data.Vars)+len(m.Variables))
+	for name, value := range data.Vars {
+		resolved.Vars[name] = coerceValue(value)
+	}
+
+	var errs []error
+	for i := range m.Variables {
+		variable := &m.Variables[i]
+		value, ok := data.Vars[variable.Name]
+		switch {
+		case ok:
+			checked, err := variable.Check(value)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			resolved.Vars[variable.Name] = checked
+		case variable.Default != nil:
+			resolved.Vars[variable.Name] = variable.Default
+		case variable.Required:
+			errs = append(errs, fmt.Errorf("variable '%s' is required", variable.Name))
+		default:
+			resolved.Vars[variable.Name] = variable.zero()
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidVariables, errors.Join(errs...))
+	}
+	return &resolved, nil
+}
+
+// zero returns the value of an optional variable without default
+func (v *Variable) zero() any {
+	switch v.Type {
+	case VariableBool:
+		return false
+	case VariableInt:
+		return 0
+	case VariableFloat:
+		return 0.0
+	default:
+		return ""
+	}
+}
+
+// Includes reports whether the template at templatePath is rendered for data
+func (m *Manifest) Includes(templatePath string, data *TemplateData) (bool, error) {
+	if templatePath == ManifestFile || isPartial(templatePath) {
+		return false, nil
+	}
+
+	name := strings.TrimSuffix(templatePath, ".tpl")
+	for _, rule := range m.Files {
+		included := matchAny(rule.Include, name)
+		excluded := matchAny(rule.Exclude, name)
+		if !included && !excluded {
+			continue
+		}
+
+		holds, err := evalCondition(rule.condition, data)
+		if err != nil {
+			return false, fmt.Errorf("%s: condition '%s': %w", ManifestFile, rule.When, err)
+		}
+		if (included && !holds) || (excluded && holds) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Notes renders the post-generation messages whose condition holds for data
+func (m *Manifest) Notes(data *TemplateData) ([]string, error) {
+	var notes []string
+	for _, message := range m.Messages {
+		holds, err := evalCondition(message.condition, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: condition '%s': %w", ManifestFile, message.When, err)
+		}
+		if !holds {
+			continue
+		}
+
+		var text bytes.Buffer
+		if err := message.text.Execute(&text, data); err != nil {
+			return nil, fmt.Errorf("%s: message: %w", ManifestFile, err)
+		}
+		if note := strings.TrimSpace(text.String()); note != "" {
+			notes = append(notes, note)
+		}
+	}
+	return notes, nil
+}
+
+// GenerationNotes returns the post-generation messages of the generator's
+// template set for config
+func GenerationNotes(generator Generator, config *GeneratorConfig) ([]string, error) {
+	templates, err := config.Templates(generator.GetName())
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := LoadManifest(templates)
+	if err != nil {
+		return nil, err
+	}
+	data, err := manifest.Resolve(PrepareTemplateData(config))
+	if err != nil {
+		return nil, err
+	}
+	return manifest.Notes(data)
+}
+
+// parseCondition parses a condition as the pipeline of an {{if}} action, or
+// returns nil for an empty condition
+func parseCondition(condition string) (*template.Template, error) {
+	if strings.TrimSpace(condition) == "" {
+		return nil, nil
+	}
+	if strings.Contains(condition, "{{") || strings.Contains(condition, "}}") {
+		return nil, fmt.Errorf("invalid condition '%s': write the pipeline without {{ }}", condition)
+	}
+	tmpl, err := template.New("when").Funcs(TemplateFuncs).Option("missingkey=zero").Parse("{{if " + condition + "}}true{{end}}")
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition '%s': %w", condition, err)
+	}
+	return tmpl, nil
+}
+
+// evalCondition reports whether a parsed condition holds for data
+func evalCondition(condition *template.Template, data *TemplateData) (bool, error) {
+	if condition == nil {
+		return true, nil
+	}
+	var result bytes.Buffer
+	if err := condition.Execute(&result, data); err != nil {
+		return false, err
+	}
+	return result.String() == "true", nil
+}
+
+// matchAny reports whether a slash-separated path matches one of the patterns
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(strings.Split(pattern, "/"), strings.Split(name, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches path segments against pattern segments, where a "**"
+// segment matches zero or more path segments
+func matchGlob(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := len(name); i >= 0; i-- {
+				if matchGlob(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// joinTypes formats variable types for error messages
+func joinTypes(types []VariableType) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = string(t)
+	}
+	return strings.Join(names, ", ")
+}
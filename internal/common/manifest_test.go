@@ -0,0 +1,294 @@
+package common
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadManifest(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     []Variable // name, type and default of the variables
+		wantErr  string
+	}{
+		{
+			name: "variables",
+			manifest: `
+variables:
+  - name: team
+    pattern: ^[a-z]+$
+    required: true
+  - name: replicas
+    type: int
+    default: "2"
+  - name: ratio
+    type: float
+    default: 1
+  - name: docs
+    type: bool
+    default: "true"
+  - name: ci
+    choices: [github, gitlab]
+    default: github`,
+			want: []Variable{
+				{Name: "team", Type: VariableString},
+				{Name: "replicas", Type: VariableInt, Default: 2},
+				{Name: "ratio", Type: VariableFloat, Default: 1.0},
+				{Name: "docs", Type: VariableBool, Default: true},
+				{Name: "ci", Type: VariableString, Default: "github"},
+			},
+		},
+		{
+			name:     "no manifest content",
+			manifest: "",
+		},
+		{
+			name:     "invalid YAML",
+			manifest: "variables: [",
+			wantErr:  "invalid template.yaml",
+		},
+		{
+			name:     "variable without a name",
+			manifest: "variables:\n  - type: int",
+			wantErr:  "variable 1 has no name",
+		},
+		{
+			name:     "variable declared twice",
+			manifest: "variables:\n  - name: team\n  - name: team",
+			wantErr:  "variable 'team' is declared twice",
+		},
+		{
+			name:     "unknown type",
+			manifest: "variables:\n  - name: team\n    type: list",
+			wantErr:  "variable 'team' has unknown type 'list' (use string, bool, int, float)",
+		},
+		{
+			name:     "invalid pattern",
+			manifest: "variables:\n  - name: team\n    pattern: '['",
+			wantErr:  "variable 'team': invalid pattern",
+		},
+		{
+			name:     "default not among the choices",
+			manifest: "variables:\n  - name: ci\n    choices: [github]\n    default: jenkins",
+			wantErr:  "default of variable 'ci': 'jenkins' is not one of github",
+		},
+		{
+			name:     "default out of range",
+			manifest: "variables:\n  - name: replicas\n    type: int\n    min: 1\n    default: 0",
+			wantErr:  "default of variable 'replicas': 0 is less than 1",
+		},
+		{
+			name:     "file rule without patterns",
+			manifest: "files:\n  - when: .WithGRPC",
+			wantErr:  "file rule 1 has neither include nor exclude patterns",
+		},
+		{
+			name:     "invalid file pattern",
+			manifest: "files:\n  - include: ['internal/[']",
+			wantErr:  "file rule 1: invalid pattern 'internal/['",
+		},
+		{
+			name:     "condition in braces",
+			manifest: "files:\n  - include: [docs/**]\n    when: '{{.Vars.docs}}'",
+			wantErr:  "write the pipeline without {{ }}",
+		},
+		{
+			name:     "invalid message",
+			manifest: "messages:\n  - text: '{{.Vars.team'",
+			wantErr:  "message 1:",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			manifest, err := LoadManifest(fstest.MapFS{ManifestFile: {Data: []byte(test.manifest)}})
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("LoadManifest error = %v, want one containing %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadManifest: %v", err)
+			}
+
+			var got []Variable
+			for _, variable := range manifest.Variables {
+				got = append(got, Variable{Name: variable.Name, Type: variable.Type, Default: variable.Default})
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("variables = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestVariableCheck(t *testing.T) {
+	one, ten := 1.0, 10.0
+	tests := []struct {
+		variable Variable
+		value    any
+		want     any
+		wantErr  string
+	}{
+		{Variable{Type: VariableBool}, true, true, ""},
+		{Variable{Type: VariableBool}, "false", false, ""},
+		{Variable{Type: VariableBool}, "yes", nil, "yes is not a valid bool"},
+		{Variable{Type: VariableBool}, 1, nil, "1 is not a valid bool"},
+		{Variable{Type: VariableInt}, 3, 3, ""},
+		{Variable{Type: VariableInt}, 3.0, 3, ""},
+		{Variable{Type: VariableInt}, "42", 42, ""},
+		{Variable{Type: VariableInt}, 3.5, nil, "3.5 is not a valid int"},
+		{Variable{Type: VariableInt}, "three", nil, "three is not a valid int"},
+		{Variable{Type: VariableInt, Min: &one, Max: &ten}, 0, 0, "0 is less than 1"},
+		{Variable{Type: VariableInt, Min: &one, Max: &ten}, "11", 11, "11 is greater than 10"},
+		{Variable{Type: VariableFloat}, 2, 2.0, ""},
+		{Variable{Type: VariableFloat}, "2.5", 2.5, ""},
+		{Variable{Type: VariableFloat}, "2,5", nil, "2,5 is not a valid float"},
+		{Variable{Type: VariableFloat, Max: &one}, 1.5, 1.5, "1.5 is greater than 1"},
+		{Variable{Type: VariableString}, "platform", "platform", ""},
+		{Variable{Type: VariableString}, 42, "42", ""},
+		{Variable{Type: VariableString}, true, "true", ""},
+		{Variable{Type: VariableString}, []any{"a"}, nil, "[a] is not a valid string"},
+		{Variable{Type: VariableString, Choices: []string{"github", "gitlab"}}, "gitlab", "gitlab", ""},
+		{Variable{Type: VariableString, Choices: []string{"github", "gitlab"}}, "jenkins", "jenkins", "'jenkins' is not one of github, gitlab"},
+		{Variable{Type: VariableString, Pattern: "^[a-z]+$"}, "Platform", "Platform", "'Platform' does not match ^[a-z]+$"},
+	}
+
+	for _, test := range tests {
+		test.variable.Name = "v"
+		manifest := &Manifest{Variables: []Variable{test.variable}}
+		if err := manifest.compile(); err != nil {
+			t.Fatal(err)
+		}
+		got, err := manifest.Variables[0].Check(test.value)
+		if test.wantErr != "" {
+			if err == nil || err.Error() != "variable 'v': "+test.wantErr {
+				t.Errorf("Check(%v) of a %s error = %v, want %q", test.value, test.variable.Type, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != test.want {
+			t.Errorf("Check(%v) of a %s = %v (%T), %v, want %v (%T)", test.value, test.variable.Type, got, got, err, test.want, test.want)
+		}
+	}
+}
+
+func TestManifestResolve(t *testing.T) {
+	manifest, err := LoadManifest(fstest.MapFS{ManifestFile: {Data: []byte(`
+variables:
+  - name: team
+    required: true
+  - name: replicas
+    type: int
+    default: 2
+  - name: docs
+    type: bool
+  - name: ci
+    choices: [github, gitlab]
+`)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := manifest.Resolve(&TemplateData{Vars: map[string]any{"team": "platform", "ci": "gitlab", "extra": "3", "flag": "true"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"team": "platform", "replicas": 2, "docs": false, "ci": "gitlab", "extra": 3, "flag": true}
+	if !reflect.DeepEqual(data.Vars, want) {
+		t.Errorf("Vars = %v, want %v", data.Vars, want)
+	}
+
+	// Every invalid variable is reported
+	_, err = manifest.Resolve(&TemplateData{Vars: map[string]any{"replicas": "many", "ci": "jenkins"}})
+	if !errors.Is(err, ErrInvalidVariables) {
+		t.Fatalf("Resolve error = %v, want ErrInvalidVariables", err)
+	}
+	for _, problem := range []string{"variable 'team' is required", "variable 'replicas': many is not a valid int", "variable 'ci': 'jenkins' is not one of github, gitlab"} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("Resolve error = %v, want it to report %q", err, problem)
+		}
+	}
+}
+
+func TestManifestIncludes(t *testing.T) {
+	manifest, err := LoadManifest(fstest.MapFS{ManifestFile: {Data: []byte(`
+files:
+  - include: [internal/grpc/**, "**/*.proto"]
+    when: .WithGRPC
+  - exclude: [docs/**]
+    when: not .Vars.docs
+  - include: [.github/**]
+    when: eq .Vars.ci "github"
+  - exclude: [LICENSE]
+`)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		data TemplateData
+		want bool
+	}{
+		{"main.go.tpl", TemplateData{}, true},
+		{"internal/grpc/server.go.tpl", TemplateData{}, false},
+		{"internal/grpc/server.go.tpl", TemplateData{WithGRPC: true}, true},
+		{"api/v1/order.proto.tpl", TemplateData{}, false},
+		{"api/v1/order.proto.tpl", TemplateData{WithGRPC: true}, true},
+		{"docs/api.md.tpl", TemplateData{}, false},
+		{"docs/api.md.tpl", TemplateData{Vars: map[string]any{"docs": true}}, true},
+		{".github/workflows/ci.yml.tpl", TemplateData{Vars: map[string]any{"ci": "github"}}, true},
+		{".github/workflows/ci.yml.tpl", TemplateData{Vars: map[string]any{"ci": "gitlab"}}, false},
+		{"LICENSE.tpl", TemplateData{}, false},
+		{ManifestFile, TemplateData{}, false},
+		{PartialsDir + "/go/service.tpl", TemplateData{}, false},
+	}
+	for _, test := range tests {
+		got, err := manifest.Includes(test.path, &test.data)
+		if err != nil {
+			t.Fatalf("Includes(%q): %v", test.path, err)
+		}
+		if got != test.want {
+			t.Errorf("Includes(%q) with %+v = %v, want %v", test.path, test.data, got, test.want)
+		}
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		// ** at the start
+		{"**/*.proto", "order.proto", true},
+		{"**/*.proto", "api/v1/order.proto", true},
+		{"**/*.proto", "api/v1/order.go", false},
+		// ** in the middle
+		{"internal/**/server.go", "internal/server.go", true},
+		{"internal/**/server.go", "internal/grpc/server.go", true},
+		{"internal/**/server.go", "internal/grpc/v1/server.go", true},
+		{"internal/**/server.go", "cmd/grpc/server.go", false},
+		{"internal/**/server.go", "internal/grpc/client.go", false},
+		// ** at the end
+		{"docs/**", "docs", true},
+		{"docs/**", "docs/api.md", true},
+		{"docs/**", "docs/v1/api.md", true},
+		{"docs/**", "doc/api.md", false},
+		// without **
+		{"*.md", "README.md", true},
+		{"*.md", "docs/api.md", false},
+		{"docs/*", "docs/v1/api.md", false},
+		{"Makefile", "Makefile", true},
+	}
+	for _, test := range tests {
+		if got := matchAny([]string{test.pattern}, test.name); got != test.want {
+			t.Errorf("matchAny(%q, %q) = %v, want %v", test.pattern, test.name, got, test.want)
+		}
+	}
+}
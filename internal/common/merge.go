@@ -0,0 +1,187 @@
+package common
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+)
+
+// Conflict markers written by merges
+const (
+	conflictStart  = "<<<<<<< existing\n"
+	conflictMiddle = "=======\n"
+	conflictEnd    = ">>>>>>> ccin\n"
+)
+
+// hunk replaces base lines [start, end) with lines
+type hunk struct {
+	start, end int
+	lines      []string
+}
+
+// hunks groups the edits of a line diff against base into hunks
+func hunks(edits []edit) []hunk {
+	var result []hunk
+	var current *hunk
+	position := 0
+	for _, e := range edits {
+		if e.kind == editEqual {
+			if current != nil {
+				result = append(result, *current)
+				current = nil
+			}
+			position++
+			continue
+		}
+
+		if current == nil {
+			current = &hunk{start: position, end: position}
+		}
+		if e.kind == editDelete {
+			current.end++
+			position++
+		} else {
+			current.lines = append(current.lines, e.line)
+		}
+	}
+	if current != nil {
+		result = append(result, *current)
+	}
+	return result
+}
+
+// applyHunks returns base lines [start, end) with the hunks applied
+func applyHunks(base []string, applied []hunk, start, end int) []string {
+	var lines []string
+	position := start
+	for _, h := range applied {
+		lines = append(lines, base[position:h.start]...)
+		lines = append(lines, h.lines...)
+		position = h.end
+	}
+	return append(lines, base[position:end]...)
+}
+
+// Merge3 merges the changes from base to existing (the user's file) and
+// from base to rendered (the new template output), like git merge-file.
+// Changes on one side are applied; overlapping or adjacent changes that
+// differ are wrapped in conflict markers. It returns the merged content and
+// the number of conflicts.
+func Merge3(base, existing, rendered []byte) ([]byte, int) {
+	baseLines := splitLines(base)
+	ours := hunks(diffLines(baseLines, splitLines(existing)))
+	theirs := hunks(diffLines(baseLines, splitLines(rendered)))
+
+	var merged bytes.Buffer
+	conflicts := 0
+	position := 0
+	i, j := 0, 0
+	for i < len(ours) || j < len(theirs) {
+		start := len(baseLines)
+		if i < len(ours) {
+			start = ours[i].start
+		}
+		if j < len(theirs) {
+			start = min(start, theirs[j].start)
+		}
+
+		// Collect the hunks of both sides that overlap or touch the group
+		end := start
+		var groupOurs, groupTheirs []hunk
+		for grown := true; grown; {
+			grown = false
+			if i < len(ours) && ours[i].start <= end {
+				groupOurs = append(groupOurs, ours[i])
+				end = max(end, ours[i].end)
+				i++
+				grown = true
+			}
+			if j < len(theirs) && theirs[j].start <= end {
+				groupTheirs = append(groupTheirs, theirs[j])
+				end = max(end, theirs[j].end)
+				j++
+				grown = true
+			}
+		}
+
+		writeLinesRaw(&merged, baseLines[position:start])
+		oursLines := applyHunks(baseLines, groupOurs, start, end)
+		theirsLines := applyHunks(baseLines, groupTheirs, start, end)
+		switch {
+		case len(groupTheirs) == 0:
+			writeLinesRaw(&merged, oursLines)
+		case len(groupOurs) == 0, slices.Equal(oursLines, theirsLines):
+			writeLinesRaw(&merged, theirsLines)
+		default:
+			conflicts++
+			merged.WriteString(conflictStart)
+			writeLines(&merged, oursLines)
+			merged.WriteString(conflictMiddle)
+			writeLines(&merged, theirsLines)
+			merged.WriteString(conflictEnd)
+		}
+		position = end
+	}
+	writeLinesRaw(&merged, baseLines[position:])
+
+	return merged.Bytes(), conflicts
+}
+
+// MergeFiles merges generated content into an existing file without a common
+// base. Lines only the template adds are inserted, lines only the file has are
+// kept, and lines both sides changed are wrapped in git-style conflict markers.
+// It returns the merged content and the number of conflicts.
+func MergeFiles(existing, rendered []byte) ([]byte, int) {
+	var merged bytes.Buffer
+	conflicts := 0
+
+	var deleted, inserted []string
+	flush := func() {
+		switch {
+		case len(deleted) > 0 && len(inserted) > 0:
+			conflicts++
+			merged.WriteString(conflictStart)
+			writeLines(&merged, deleted)
+			merged.WriteString(conflictMiddle)
+			writeLines(&merged, inserted)
+			merged.WriteString(conflictEnd)
+		default:
+			writeLinesRaw(&merged, deleted)
+			writeLinesRaw(&merged, inserted)
+		}
+		deleted, inserted = nil, nil
+	}
+
+	for _, e := range diffLines(splitLines(existing), splitLines(rendered)) {
+		switch e.kind {
+		case editDelete:
+			deleted = append(deleted, e.line)
+		case editInsert:
+			inserted = append(inserted, e.line)
+		default:
+			flush()
+			merged.WriteString(e.line)
+		}
+	}
+	flush()
+
+	return merged.Bytes(), conflicts
+}
+
+// writeLines writes the lines of a conflict side, terminating a last line
+// that has no newline so the next marker starts on its own line
+func writeLines(buf *bytes.Buffer, lines []string) {
+	for _, line := range lines {
+		buf.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			buf.WriteString("\n")
+		}
+	}
+}
+
+// writeLinesRaw writes lines as they are
+func writeLinesRaw(buf *bytes.Buffer, lines []string) {
+	for _, line := range lines {
+		buf.WriteString(line)
+	}
+}
@@ -0,0 +1,132 @@
+package common
+
+import "testing"
+
+func TestMerge3(t *testing.T) {
+	tests := []struct {
+		name                     string
+		base, existing, rendered string
+		want                     string
+		conflicts                int
+	}{
+		{
+			name:     "changed by the user only",
+			base:     "a\nb\nc\n",
+			existing: "a\nB\nc\n",
+			rendered: "a\nb\nc\n",
+			want:     "a\nB\nc\n",
+		},
+		{
+			name:     "changed by the templates only",
+			base:     "a\nb\nc\n",
+			existing: "a\nb\nc\n",
+			rendered: "a\nb\nC\n",
+			want:     "a\nb\nC\n",
+		},
+		{
+			name:     "changes apart",
+			base:     "a\nb\nc\nd\ne\n",
+			existing: "A\nb\nc\nd\ne\n",
+			rendered: "a\nb\nc\nd\nE\n",
+			want:     "A\nb\nc\nd\nE\n",
+		},
+		{
+			name:     "same change on both sides",
+			base:     "a\nb\nc\n",
+			existing: "a\nB\nc\n",
+			rendered: "a\nB\nc\n",
+			want:     "a\nB\nc\n",
+		},
+		{
+			name:     "line deleted while another is added",
+			base:     "a\nb\nc\n",
+			existing: "a\nc\n",
+			rendered: "a\nb\nc\nd\n",
+			want:     "a\nc\nd\n",
+		},
+		{
+			name:      "same line changed differently",
+			base:      "a\nb\nc\n",
+			existing:  "a\nX\nc\n",
+			rendered:  "a\nY\nc\n",
+			want:      "a\n<<<<<<< existing\nX\n=======\nY\n>>>>>>> ccin\nc\n",
+			conflicts: 1,
+		},
+		{
+			name:      "adjacent changes",
+			base:      "a\nb\nc\n",
+			existing:  "A\nb\nc\n",
+			rendered:  "a\nB\nc\n",
+			want:      "<<<<<<< existing\nA\nb\n=======\na\nB\n>>>>>>> ccin\nc\n",
+			conflicts: 1,
+		},
+		{
+			name:      "conflict on a last line without newline",
+			base:      "a\nb",
+			existing:  "a\nX",
+			rendered:  "a\nY",
+			want:      "a\n<<<<<<< existing\nX\n=======\nY\n>>>>>>> ccin\n",
+			conflicts: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged, conflicts := Merge3([]byte(test.base), []byte(test.existing), []byte(test.rendered))
+			if string(merged) != test.want || conflicts != test.conflicts {
+				t.Errorf("Merge3 = %q with %d conflicts, want %q with %d", merged, conflicts, test.want, test.conflicts)
+			}
+		})
+	}
+}
+
+func TestMergeFiles(t *testing.T) {
+	tests := []struct {
+		name               string
+		existing, rendered string
+		want               string
+		conflicts          int
+	}{
+		{
+			name:     "identical",
+			existing: "a\nb\n",
+			rendered: "a\nb\n",
+			want:     "a\nb\n",
+		},
+		{
+			name:     "line added by the templates",
+			existing: "a\nc\n",
+			rendered: "a\nb\nc\n",
+			want:     "a\nb\nc\n",
+		},
+		{
+			name:     "line only the file has",
+			existing: "a\nmine\nc\n",
+			rendered: "a\nc\n",
+			want:     "a\nmine\nc\n",
+		},
+		{
+			name:      "line changed",
+			existing:  "a\nX\nc\n",
+			rendered:  "a\nY\nc\n",
+			want:      "a\n<<<<<<< existing\nX\n=======\nY\n>>>>>>> ccin\nc\n",
+			conflicts: 1,
+		},
+		{
+			name:      "lines changed apart",
+			existing:  "A\nb\nc\nd\ne\n",
+			rendered:  "a\nb\nc\nd\nE\n",
+			want:      "<<<<<<< existing\nA\n=======\na\n>>>>>>> ccin\nb\nc\nd\n<<<<<<< existing\ne\n=======\nE\n>>>>>>> ccin\n",
+			conflicts: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged, conflicts := MergeFiles([]byte(test.existing), []byte(test.rendered))
+			if string(merged) != test.want || conflicts != test.conflicts {
+				t.Errorf("MergeFiles = %q with %d conflicts, want %q with %d", merged, conflicts, test.want, test.conflicts)
+			}
+		})
+	}
+}
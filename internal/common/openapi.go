@@ -0,0 +1,525 @@
+package common
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Contract is what an API contract (an OpenAPI document or a .proto file)
+// declares for ccin: domains with their fields and operations, and what
+// could not be imported
+type Contract struct {
+	Domains  []Domain
+	Proto    *ProtoContract    // services of a .proto file, nil for OpenAPI documents
+	IDTypes  map[string]string // field type of each domain's ids, for the domains whose ids the contract types
+	Warnings []string          // schemas, properties and operations left out, for the user to review
+}
+
+// setIDType records the field type the contract gives a domain's ids
+func (c *Contract) setIDType(domain, idType string) {
+	if idType == "" {
+		return
+	}
+	if c.IDTypes == nil {
+		c.IDTypes = make(map[string]string)
+	}
+	c.IDTypes[domain] = idType
+}
+
+// openAPIDocument is the part of an OpenAPI 3.0/3.1 document ccin reads
+type openAPIDocument struct {
+	OpenAPI string `yaml:"openapi"`
+	Servers []struct {
+		URL string `yaml:"url"`
+	} `yaml:"servers"`
+	Paths      orderedMap[openAPIPathItem] `yaml:"paths"`
+	Components struct {
+		Schemas orderedMap[*openAPISchema] `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+// openAPIPathItem holds the operations of a path by lower-case method
+type openAPIPathItem map[string]yaml.Node
+
+// openAPIOperation is an operation of a path
+type openAPIOperation struct {
+	OperationID string             `yaml:"operationId"`
+	Summary     string             `yaml:"summary"`
+	Parameters  []openAPIParameter `yaml:"parameters"`
+	RequestBody *struct {
+		Content map[string]openAPIMediaType `yaml:"content"`
+	} `yaml:"requestBody"`
+	Responses orderedMap[openAPIResponse] `yaml:"responses"`
+}
+
+// openAPIParameter is a parameter of an operation or of all operations of a path
+type openAPIParameter struct {
+	Name   string         `yaml:"name"`
+	In     string         `yaml:"in"`
+	Schema *openAPISchema `yaml:"schema"`
+}
+
+// openAPIResponse is a response of an operation
+type openAPIResponse struct {
+	Content map[string]openAPIMediaType `yaml:"content"`
+}
+
+// openAPIMediaType is the body of a request or response
+type openAPIMediaType struct {
+	Schema *openAPISchema `yaml:"schema"`
+}
+
+// openAPISchema is the part of a schema object ccin maps to fields
+type openAPISchema struct {
+	Ref        string                     `yaml:"$ref"`
+	Type       openAPITypes               `yaml:"type"`
+	Format     string                     `yaml:"format"`
+	Nullable   bool                       `yaml:"nullable"` // OpenAPI 3.0; 3.1 lists "null" in type
+	Required   []string                   `yaml:"required"`
+	Properties orderedMap[*openAPISchema] `yaml:"properties"`
+	Items      *openAPISchema             `yaml:"items"`
+	AllOf      []*openAPISchema           `yaml:"allOf"`
+	Unique     bool                       `yaml:"x-unique"` // extension: the field gets a unique constraint
+}
+
+// openAPITypes is the type of a schema: one name, or a list in OpenAPI 3.1
+type openAPITypes []string
+
+// UnmarshalYAML accepts a single type name as well as a list
+func (t *openAPITypes) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*t = openAPITypes{node.Value}
+		return nil
+	}
+	return node.Decode((*[]string)(t))
+}
+
+// orderedMap is a YAML mapping decoded in document order, so domains and
+// operations are generated in the order the contract declares them
+type orderedMap[T any] []struct {
+	Key   string
+	Value T
+}
+
+// UnmarshalYAML decodes every value of a mapping, keeping the key order
+func (m *orderedMap[T]) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected a mapping", node.Line)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var value T
+		if err := node.Content[i+1].Decode(&value); err != nil {
+			return err
+		}
+		*m = append(*m, struct {
+			Key   string
+			Value T
+		}{node.Content[i].Value, value})
+	}
+	return nil
+}
+
+// get returns the value of a key
+func (m orderedMap[T]) get(key string) (T, bool) {
+	for _, entry := range m {
+		if entry.Key == key {
+			return entry.Value, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// openAPIMethods are the operations of a path item ccin generates, in the
+// order they are listed within a path
+var openAPIMethods = []string{"get", "post", "put", "patch", "delete"}
+
+// LoadOpenAPI imports an OpenAPI 3.0 or 3.1 document (YAML or JSON). The
+// schemas that operations take or return become domains, their scalar
+// properties become fields, and each operation is attached to the domain
+// it serves. Routes include the path of the first server URL.
+func LoadOpenAPI(file string) (*Contract, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc openAPIDocument
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document %s: %w", file, err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.0") && !strings.HasPrefix(doc.OpenAPI, "3.1") {
+		return nil, fmt.Errorf("invalid OpenAPI document %s: version '%s' is not supported, use OpenAPI 3.0 or 3.1", file, doc.OpenAPI)
+	}
+
+	importer := &openAPIImporter{doc: &doc, basePath: serverPath(doc.Servers)}
+	if err := importer.importOperations(); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document %s: %w", file, err)
+	}
+	if len(importer.contract.Domains) == 0 {
+		return nil, fmt.Errorf("no operation of %s takes or returns a schema of components.schemas", file)
+	}
+	return &importer.contract, nil
+}
+
+// openAPIImporter builds a contract from an OpenAPI document
+type openAPIImporter struct {
+	doc      *openAPIDocument
+	basePath string
+	contract Contract
+}
+
+// warn records something the contract declares that was not imported
+func (im *openAPIImporter) warn(format string, args ...any) {
+	im.contract.Warnings = append(im.contract.Warnings, fmt.Sprintf(format, args...))
+}
+
+// importOperations attaches every operation to the domain of the schema it
+// takes or returns, creating domains in the order of components.schemas
+func (im *openAPIImporter) importOperations() error {
+	type pending struct {
+		schema    string
+		operation Operation
+		idType    string // field type of the operation's path parameter, if typed
+	}
+	var operations []pending
+
+	for _, entry := range im.doc.Paths {
+		var parameters []openAPIParameter
+		if node, ok := entry.Value["parameters"]; ok {
+			if err := node.Decode(&parameters); err != nil {
+				return fmt.Errorf("%s: %w", entry.Key, err)
+			}
+		}
+		for _, method := range openAPIMethods {
+			node, ok := entry.Value[method]
+			if !ok {
+				continue
+			}
+			var op openAPIOperation
+			if err := node.Decode(&op); err != nil {
+				return fmt.Errorf("%s %s: %w", strings.ToUpper(method), entry.Key, err)
+			}
+			schema, operation := im.operation(entry.Key, method, op)
+			if _, ok := im.doc.Components.Schemas.get(schema); schema != "" && !ok {
+				return fmt.Errorf("%s %s: schema '%s' is not declared in components.schemas", operation.Method, entry.Key, schema)
+			}
+			idType := paramType(operation.Param, slices.Concat(op.Parameters, parameters))
+			operations = append(operations, pending{schema, operation, idType})
+		}
+	}
+
+	// Operations without a body (e.g. DELETE /orders/{id}) belong to the
+	// domain served on the same path, else to the one their path names
+	for i := range operations {
+		p := &operations[i]
+		if p.schema != "" {
+			continue
+		}
+		if j := slices.IndexFunc(operations, func(other pending) bool {
+			return other.schema != "" && other.operation.Path == p.operation.Path
+		}); j >= 0 {
+			p.schema = operations[j].schema
+			continue
+		}
+		if segments := staticSegments(strings.TrimPrefix(p.operation.Path, im.basePath)); len(segments) > 0 {
+			for _, entry := range im.doc.Components.Schemas {
+				if segments[0] == Pluralize(Kebab(entry.Key)) {
+					p.schema = entry.Key
+				}
+			}
+		}
+		if p.schema == "" {
+			im.warn("%s %s skipped: no schema of components.schemas is taken, returned or named by its path", p.operation.Method, p.operation.Path)
+		}
+	}
+
+	// Domains in schema order, operations in path order
+	for _, entry := range im.doc.Components.Schemas {
+		if !slices.ContainsFunc(operations, func(p pending) bool { return p.schema == entry.Key }) {
+			continue
+		}
+		fields, idType := im.fields(entry.Key, entry.Value)
+		domain := Domain{Name: Camel(entry.Key), Fields: fields}
+		if !domainNamePattern.MatchString(domain.Name) {
+			return fmt.Errorf("schema '%s' cannot be a domain: its name must start with a letter", entry.Key)
+		}
+		for _, p := range operations {
+			if p.schema == entry.Key {
+				domain.Operations = append(domain.Operations, p.operation)
+				idType = cmp.Or(idType, p.idType)
+			}
+		}
+		classifyOperations(domain.Operations)
+		if err := checkOperationNames(domain); err != nil {
+			return err
+		}
+		im.contract.Domains = append(im.contract.Domains, domain)
+		im.contract.setIDType(domain.Name, idType)
+	}
+	return nil
+}
+
+// operation reads an operation and returns it with the schema whose domain
+// serves it: the schema of the successful response, else that of the
+// request body
+func (im *openAPIImporter) operation(route, method string, op openAPIOperation) (string, Operation) {
+	operation := Operation{
+		Name:    Pascal(op.OperationID),
+		Summary: op.Summary,
+		Method:  strings.ToUpper(method),
+		Path:    im.basePath + route,
+		Status:  http.StatusOK,
+	}
+	if segments := strings.Split(route, "/"); len(segments) > 0 {
+		if name, ok := pathParam(segments[len(segments)-1]); ok {
+			operation.Param = name
+		}
+	}
+
+	var schema string
+	if op.RequestBody != nil {
+		if ref, list := im.bodySchema(op.RequestBody.Content); ref != "" && !list {
+			schema, operation.Request = ref, true
+		}
+	}
+	for _, response := range op.Responses {
+		status, err := strconv.Atoi(response.Key)
+		if err != nil || status < 200 || status > 299 {
+			continue
+		}
+		operation.Status = status
+		ref, list := im.bodySchema(response.Value.Content)
+		if ref == "" {
+			break
+		}
+		schema, operation.Response = ref, ResponseEntity
+		if list {
+			operation.Response = ResponseList
+		}
+		break
+	}
+	return schema, operation
+}
+
+// bodySchema returns the components.schemas name of a JSON body, and
+// whether the body is an array of it
+func (im *openAPIImporter) bodySchema(content map[string]openAPIMediaType) (string, bool) {
+	for mediaType, body := range content {
+		if body.Schema == nil || !strings.Contains(mediaType, "json") {
+			continue
+		}
+		if name, ok := schemaRef(body.Schema.Ref); ok {
+			return name, false
+		}
+		if body.Schema.Items != nil {
+			if name, ok := schemaRef(body.Schema.Items.Ref); ok {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// paramType returns the field type of the named path parameter, if the
+// parameters declare it with a scalar schema
+func paramType(name string, parameters []openAPIParameter) string {
+	for _, parameter := range parameters {
+		if parameter.In != "path" || parameter.Name != name || parameter.Schema == nil {
+			continue
+		}
+		if fieldType, ok := openAPIFieldType(parameter.Schema); ok {
+			return fieldType
+		}
+	}
+	return ""
+}
+
+// fields maps the scalar properties of a schema to fields. Properties of
+// allOf parts are included; id, created_at and updated_at are left out
+// since every entity has them, and the field type of id is returned.
+func (im *openAPIImporter) fields(name string, schema *openAPISchema) ([]Field, string) {
+	var fields []Field
+	var idType string
+	var keys []string // property names as the contract writes them
+	required := map[string]bool{}
+
+	var collect func(schema *openAPISchema, depth int)
+	collect = func(schema *openAPISchema, depth int) {
+		if schema == nil || depth > 8 {
+			return
+		}
+		if ref, ok := schemaRef(schema.Ref); ok {
+			resolved, _ := im.doc.Components.Schemas.get(ref)
+			collect(resolved, depth+1)
+			return
+		}
+		for _, part := range schema.AllOf {
+			collect(part, depth+1)
+		}
+		for _, property := range schema.Required {
+			required[property] = true
+		}
+		for _, property := range schema.Properties {
+			if property.Value == nil {
+				im.warn("%s.%s skipped: the property has no schema", name, property.Key)
+				continue
+			}
+			fieldType, ok := openAPIFieldType(property.Value)
+			if !ok {
+				im.warn("%s.%s skipped: only string, integer, number and boolean properties become fields", name, property.Key)
+				continue
+			}
+			field, err := Field{Name: property.Key, Type: fieldType, Unique: property.Value.Unique}.normalize()
+			switch {
+			case reservedFields[field.Name]:
+				if field.Name == "id" {
+					idType = cmp.Or(idType, fieldType)
+				}
+				continue
+			case err != nil:
+				im.warn("%s.%s skipped: %v", name, property.Key, err)
+				continue
+			case slices.ContainsFunc(fields, func(f Field) bool { return f.Name == field.Name }):
+				continue
+			}
+			field.Optional = property.Value.Nullable || slices.Contains(property.Value.Type, "null")
+			fields = append(fields, field)
+			keys = append(keys, property.Key)
+		}
+	}
+	collect(schema, 0)
+
+	for i, key := range keys {
+		fields[i].Optional = fields[i].Optional || !required[key]
+	}
+	if len(fields) == 0 {
+		im.warn("%s has no scalar properties; it gets the default fields", name)
+	}
+	return fields, idType
+}
+
+// openAPIFieldType maps the type and format of a scalar schema to a field type
+func openAPIFieldType(schema *openAPISchema) (string, bool) {
+	types := slices.DeleteFunc(slices.Clone(schema.Type), func(t string) bool { return t == "null" })
+	if schema.Ref != "" || len(types) != 1 {
+		return "", false
+	}
+	switch types[0] {
+	case "string":
+		switch schema.Format {
+		case "date-time", "date":
+			return FieldDateTime, true
+		case "uuid":
+			return FieldUUID, true
+		}
+		return FieldString, true
+	case "integer":
+		return FieldInt, true
+	case "number":
+		if schema.Format == "decimal" {
+			return FieldDecimal, true
+		}
+		return FieldFloat, true
+	case "boolean":
+		return FieldBool, true
+	}
+	return "", false
+}
+
+// classifyOperations finds the CRUD action of each operation of a domain.
+// An action is taken by its first operation only; later ones, and
+// operations of other shapes, are custom.
+func classifyOperations(ops Operations) {
+	for i := range ops {
+		op := &ops[i]
+		item := op.Param != ""
+		switch {
+		case op.Method == http.MethodGet && !item && op.Response == ResponseList:
+			op.Action = ActionList
+		case op.Method == http.MethodGet && item && op.Response == ResponseEntity:
+			op.Action = ActionGet
+		case op.Method == http.MethodPost && !item && op.Request:
+			op.Action = ActionCreate
+		case (op.Method == http.MethodPut || op.Method == http.MethodPatch) && item && op.Request:
+			op.Action = ActionUpdate
+		case op.Method == http.MethodDelete && item && op.Response == "":
+			op.Action = ActionDelete
+		default:
+			op.Action = ActionCustom
+		}
+		if op.Action != ActionCustom && ops[:i].Has(op.Action) {
+			op.Action = ActionCustom
+		}
+	}
+}
+
+// checkOperationNames names the operations without an operationId after
+// their action and refuses names used twice within a domain
+func checkOperationNames(domain Domain) error {
+	title := Pascal(domain.Name)
+	seen := make(map[string]bool, len(domain.Operations))
+	for i := range domain.Operations {
+		op := &domain.Operations[i]
+		if op.Name == "" {
+			switch op.Action {
+			case ActionList:
+				op.Name = "List" + Pluralize(title)
+			case ActionCustom:
+				op.Name = Pascal(strings.ToLower(op.Method) + " " + strings.Join(staticSegments(op.Path), " "))
+			default:
+				op.Name = Pascal(op.Action) + title
+			}
+		}
+		if op.Name == "" || !unicode.IsLetter([]rune(op.Name)[0]) {
+			return fmt.Errorf("%s %s: operationId '%s' does not start with a letter", op.Method, op.Path, op.Name)
+		}
+		if seen[op.Name] {
+			return fmt.Errorf("%s %s: schema %s has two operations named %s; give them distinct operationIds", op.Method, op.Path, title, op.Name)
+		}
+		seen[op.Name] = true
+	}
+	return nil
+}
+
+// staticSegments returns the path segments that are not parameters
+func staticSegments(route string) []string {
+	var segments []string
+	for _, segment := range strings.Split(route, "/") {
+		if _, ok := pathParam(segment); !ok && segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// schemaRef returns the name of a #/components/schemas/<name> reference
+func schemaRef(ref string) (string, bool) {
+	name, ok := strings.CutPrefix(ref, "#/components/schemas/")
+	return name, ok && name != ""
+}
+
+// serverPath returns the path of the first server URL without a trailing
+// slash, e.g. /v1 for https://api.example.com/v1/
+func serverPath(servers []struct {
+	URL string `yaml:"url"`
+}) string {
+	if len(servers) == 0 || strings.Contains(servers[0].URL, "{") {
+		return ""
+	}
+	parsed, err := url.Parse(servers[0].URL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(path.Clean("/"+parsed.Path), "/")
+}
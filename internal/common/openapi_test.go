@@ -0,0 +1,353 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLoadOpenAPIOperations(t *testing.T) {
+	contract, err := loadOpenAPI(t, `
+openapi: 3.0.3
+servers:
+  - url: https://api.example.com/v1/
+paths:
+  /orders:
+    get:
+      responses:
+        "200":
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: "#/components/schemas/Order"}}
+    post:
+      operationId: placeOrder
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/Order"}
+      responses:
+        "201":
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Order"}
+  /orders/{id}:
+    get:
+      responses:
+        "200":
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Order"}
+    delete:
+      responses:
+        "204": {}
+  /orders/{id}/cancel:
+    post:
+      responses:
+        "204": {}
+  /health:
+    get:
+      responses:
+        "200": {}
+  /customers/{id}:
+    get:
+      responses:
+        "200":
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Customer"}
+components:
+  schemas:
+    Customer:
+      properties:
+        name: {type: string}
+    Order:
+      properties:
+        total: {type: number}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]string{
+		"customer": {"GetCustomer get GET /v1/customers/{id} 200"},
+		"order": {
+			"ListOrders list GET /v1/orders 200",
+			"PlaceOrder create POST /v1/orders 201",
+			"GetOrder get GET /v1/orders/{id} 200",
+			"DeleteOrder delete DELETE /v1/orders/{id} 204",             // no body: the domain of the same path
+			"PostV1OrdersCancel custom POST /v1/orders/{id}/cancel 204", // no body: the domain its path names
+		},
+	}
+	var names []string
+	for _, domain := range contract.Domains {
+		names = append(names, domain.Name)
+		var got []string
+		for _, op := range domain.Operations {
+			got = append(got, strings.Join([]string{op.Name, op.Action, op.Method, op.Path, strconv.Itoa(op.Status)}, " "))
+		}
+		if !reflect.DeepEqual(got, want[domain.Name]) {
+			t.Errorf("operations of %s = %q, want %q", domain.Name, got, want[domain.Name])
+		}
+	}
+	if !reflect.DeepEqual(names, []string{"customer", "order"}) {
+		t.Errorf("domains = %q, want them in the order of components.schemas", names)
+	}
+	if !slices.ContainsFunc(contract.Warnings, func(w string) bool { return strings.HasPrefix(w, "GET /v1/health skipped") }) {
+		t.Errorf("warnings = %q, want one for GET /v1/health", contract.Warnings)
+	}
+}
+
+func TestLoadOpenAPIFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		schemas  string // components.schemas of a document whose GET /items/{id} returns Item
+		want     []Field
+		warnings []string
+	}{
+		{
+			name: "required and nullable",
+			schemas: `
+    Item:
+      required: [name, note, price]
+      properties:
+        name: {type: string}
+        note: {type: string, nullable: true}
+        price: {type: [number, "null"], format: decimal}
+        count: {type: integer}
+        sku: {type: string, x-unique: true}`,
+			want: []Field{
+				{Name: "name", Type: FieldString},
+				{Name: "note", Type: FieldString, Optional: true},
+				{Name: "price", Type: FieldDecimal, Optional: true},
+				{Name: "count", Type: FieldInt, Optional: true},
+				{Name: "sku", Type: FieldString, Optional: true, Unique: true},
+			},
+		},
+		{
+			name: "allOf and $ref",
+			schemas: `
+    Audited:
+      required: [createdBy]
+      properties:
+        createdBy: {type: string}
+        created_at: {type: string, format: date-time}
+    Item:
+      allOf:
+        - $ref: "#/components/schemas/Audited"
+        - required: [label]
+          properties:
+            label: {type: string}
+            createdBy: {type: integer}
+            shippedOn: {type: string, format: date}`,
+			want: []Field{
+				{Name: "created_by", Type: FieldString},
+				{Name: "label", Type: FieldString},
+				{Name: "shipped_on", Type: FieldDateTime, Optional: true},
+			},
+		},
+		{
+			name: "properties that are not fields",
+			schemas: `
+    Item:
+      properties:
+        name: {type: string}
+        tags: {type: array, items: {type: string}}
+        owner: {$ref: "#/components/schemas/Owner"}
+        blank:
+        2fa: {type: boolean}
+    Owner:
+      properties:
+        name: {type: string}`,
+			want: []Field{{Name: "name", Type: FieldString, Optional: true}},
+			warnings: []string{
+				"Item.tags skipped: only string, integer, number and boolean properties become fields",
+				"Item.owner skipped: only string, integer, number and boolean properties become fields",
+				"Item.blank skipped: the property has no schema",
+				"Item.2fa skipped: invalid field name",
+			},
+		},
+		{
+			name: "no scalar properties",
+			schemas: `
+    Item:
+      properties:
+        blank:`,
+			warnings: []string{
+				"Item.blank skipped: the property has no schema",
+				"Item has no scalar properties; it gets the default fields",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			contract, err := loadOpenAPI(t, `
+openapi: 3.1.0
+paths:
+  /items/{id}:
+    get:
+      responses:
+        "200":
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Item"}
+components:
+  schemas:`+test.schemas)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := contract.Domains[0].Fields; !reflect.DeepEqual(got, test.want) {
+				t.Errorf("fields = %+v, want %+v", got, test.want)
+			}
+			if len(contract.Warnings) != len(test.warnings) {
+				t.Fatalf("warnings = %q, want %d", contract.Warnings, len(test.warnings))
+			}
+			for i, warning := range test.warnings {
+				if !strings.HasPrefix(contract.Warnings[i], warning) {
+					t.Errorf("warning %d = %q, want it to start with %q", i+1, contract.Warnings[i], warning)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadOpenAPIIDTypes(t *testing.T) {
+	tests := []struct {
+		name       string
+		parameters string // parameters of /items/{id}
+		id         string // schema of Item's id property
+		want       string
+	}{
+		{name: "untyped"},
+		{name: "id property", id: "{type: string, format: uuid}", want: FieldUUID},
+		{name: "path parameter", parameters: "[{name: id, in: path, schema: {type: integer}}]", want: FieldInt},
+		{name: "property before parameter", parameters: "[{name: id, in: path, schema: {type: string}}]", id: "{type: integer}", want: FieldInt},
+		{name: "parameter without a schema", parameters: "[{name: id, in: path}]"},
+		{name: "query parameter", parameters: "[{name: id, in: query, schema: {type: integer}}]"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc := `
+openapi: 3.0.3
+paths:
+  /items/{id}:
+    get:
+      responses:
+        "200":
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Item"}
+components:
+  schemas:
+    Item:
+      properties:
+        name: {type: string}`
+			if test.id != "" {
+				doc += "\n        id: " + test.id
+			}
+			if test.parameters != "" {
+				doc = strings.Replace(doc, "  /items/{id}:\n", "  /items/{id}:\n    parameters: "+test.parameters+"\n", 1)
+			}
+
+			contract, err := loadOpenAPI(t, doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := contract.IDTypes["item"]; got != test.want {
+				t.Errorf("id type = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLoadOpenAPIErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr string
+	}{
+		{
+			name:    "swagger 2",
+			doc:     "swagger: '2.0'\n",
+			wantErr: "version '' is not supported",
+		},
+		{
+			name: "undeclared schema",
+			doc: `
+openapi: 3.0.3
+paths:
+  /items:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/Item"}
+      responses:
+        "204": {}`,
+			wantErr: "POST /items: schema 'Item' is not declared in components.schemas",
+		},
+		{
+			name: "no schema",
+			doc: `
+openapi: 3.0.3
+paths:
+  /health:
+    get:
+      responses:
+        "200": {}`,
+			wantErr: "takes or returns a schema of components.schemas",
+		},
+		{
+			name: "same operationId twice",
+			doc: `
+openapi: 3.0.3
+paths:
+  /items/{id}:
+    get:
+      operationId: fetch
+      responses:
+        "200":
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Item"}
+    put:
+      operationId: fetch
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/Item"}
+      responses:
+        "204": {}
+components:
+  schemas:
+    Item:
+      properties:
+        name: {type: string}`,
+			wantErr: "schema Item has two operations named Fetch",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := loadOpenAPI(t, test.doc)
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("LoadOpenAPI error = %v, want one containing %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+// loadOpenAPI imports an OpenAPI document written to a temporary file
+func loadOpenAPI(t *testing.T, doc string) (*Contract, error) {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "openapi.yaml")
+	if err := os.WriteFile(file, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return LoadOpenAPI(file)
+}
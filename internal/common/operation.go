@@ -0,0 +1,143 @@
+package common
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Operation actions. CRUD actions are served by the domain's service;
+// custom operations get a handler stub that answers 501 Not Implemented.
+const (
+	ActionList   = "list"
+	ActionGet    = "get"
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+	ActionCustom = "custom"
+)
+
+// Response shapes of an operation
+const (
+	ResponseEntity = "entity" // one entity of the domain
+	ResponseList   = "list"   // an array of entities of the domain
+)
+
+// defaultAPIPrefix is where the routes of domains without an API contract
+// are served in projects that have one
+const defaultAPIPrefix = "/api/v1"
+
+// Operation is an endpoint of a domain declared by an API contract (see
+// --from-openapi). Templates render routes and handlers from it instead of
+// the fixed CRUD routes.
+type Operation struct {
+	Name     string `yaml:"name" json:"name"`                             // handler name in PascalCase, e.g. ListOrders
+	Summary  string `yaml:"summary,omitempty" json:"summary,omitempty"`   // description of the operation in the contract
+	Action   string `yaml:"action" json:"action"`                         // list, get, create, update, delete or custom
+	Method   string `yaml:"method" json:"method"`                         // HTTP method in upper case
+	Path     string `yaml:"path" json:"path"`                             // route with {param} placeholders, e.g. /orders/{id}
+	Param    string `yaml:"param,omitempty" json:"param,omitempty"`       // path parameter holding the entity ID
+	Status   int    `yaml:"status" json:"status"`                         // status code of a successful response
+	Request  bool   `yaml:"request,omitempty" json:"request,omitempty"`   // whether the request body is an entity of the domain
+	Response string `yaml:"response,omitempty" json:"response,omitempty"` // entity, list, or empty for no body
+}
+
+// Operations is the ordered list of operations of a domain
+type Operations []Operation
+
+// DefaultOperations returns the CRUD operations of a domain without an API
+// contract in a project that has one, served under /api/v1
+func DefaultOperations(domain string) Operations {
+	title := Pascal(domain)
+	route := defaultAPIPrefix + "/" + Pluralize(Kebab(domain))
+	return Operations{
+		{Name: "List" + Pluralize(title), Action: ActionList, Method: http.MethodGet, Path: route, Status: http.StatusOK, Response: ResponseList},
+		{Name: "Get" + title, Action: ActionGet, Method: http.MethodGet, Path: route + "/{id}", Param: "id", Status: http.StatusOK, Response: ResponseEntity},
+		{Name: "Create" + title, Action: ActionCreate, Method: http.MethodPost, Path: route, Status: http.StatusCreated, Request: true, Response: ResponseEntity},
+		{Name: "Update" + title, Action: ActionUpdate, Method: http.MethodPut, Path: route + "/{id}", Param: "id", Status: http.StatusOK, Request: true, Response: ResponseEntity},
+		{Name: "Delete" + title, Action: ActionDelete, Method: http.MethodDelete, Path: route + "/{id}", Param: "id", Status: http.StatusNoContent},
+	}
+}
+
+// ColonPath returns the route with :param placeholders, as Gin, Fiber,
+// Express and Axum 0.7 write them
+func (o Operation) ColonPath() string {
+	segments := strings.Split(o.Path, "/")
+	for i, segment := range segments {
+		if name, ok := pathParam(segment); ok {
+			segments[i] = ":" + name
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// RelativePath returns ColonPath without the leading slash, as NestJS
+// decorators write routes
+func (o Operation) RelativePath() string {
+	return strings.TrimPrefix(o.ColonPath(), "/")
+}
+
+// Segments returns the path components of the route with :param
+// placeholders, as Vapor registers routes
+func (o Operation) Segments() []string {
+	return strings.FieldsFunc(o.ColonPath(), func(r rune) bool { return r == '/' })
+}
+
+// StatusText returns the reason phrase of the success status, e.g. "No
+// Content", for templates that name status codes (StatusCode::NO_CONTENT)
+func (o Operation) StatusText() string {
+	return http.StatusText(o.Status)
+}
+
+// Has reports whether any operation has one of the actions
+func (ops Operations) Has(actions ...string) bool {
+	return slices.ContainsFunc(ops, func(o Operation) bool {
+		return slices.Contains(actions, o.Action)
+	})
+}
+
+// Methods returns the HTTP methods of the operations, each once, for
+// templates that import one decorator per method (NestJS)
+func (ops Operations) Methods() []string {
+	var methods []string
+	for _, op := range ops {
+		if !slices.Contains(methods, op.Method) {
+			methods = append(methods, op.Method)
+		}
+	}
+	return methods
+}
+
+// OperationGroup is the operations served on the same route
+type OperationGroup struct {
+	Path       string
+	Operations Operations
+}
+
+// ColonPath returns the route with :param placeholders
+func (g OperationGroup) ColonPath() string {
+	return Operation{Path: g.Path}.ColonPath()
+}
+
+// ByPath groups the operations by route, in the order routes first appear,
+// for routers that register every method of a route at once (Axum)
+func (ops Operations) ByPath() []OperationGroup {
+	var groups []OperationGroup
+	for _, op := range ops {
+		i := slices.IndexFunc(groups, func(g OperationGroup) bool { return g.Path == op.Path })
+		if i < 0 {
+			groups = append(groups, OperationGroup{Path: op.Path})
+			i = len(groups) - 1
+		}
+		groups[i].Operations = append(groups[i].Operations, op)
+	}
+	return groups
+}
+
+// pathParam returns the name of a {param} path segment
+func pathParam(segment string) (string, bool) {
+	if len(segment) > 2 && strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+		return segment[1 : len(segment)-1], true
+	}
+	return "", false
+}
@@ -0,0 +1,85 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FileStatus describes what generation would do to a file
+type FileStatus string
+
+// File statuses reported by PlanFiles
+const (
+	StatusCreate    FileStatus = "create"
+	StatusModify    FileStatus = "modify"
+	StatusUnchanged FileStatus = "unchanged"
+)
+
+// PlannedFile is a rendered file compared with the file on disk
+type PlannedFile struct {
+	RenderedFile
+	Status   FileStatus
+	Existing []byte // current content, nil when the file does not exist
+}
+
+// Diff returns a unified diff from the file on disk to the rendered content,
+// or an empty string when nothing changes
+func (p PlannedFile) Diff() string {
+	if p.Status == StatusUnchanged {
+		return ""
+	}
+	from := filepath.ToSlash(filepath.Join("a", p.Path))
+	if p.Status == StatusCreate {
+		from = "/dev/null"
+	}
+	return UnifiedDiff(from, filepath.ToSlash(filepath.Join("b", p.Path)), p.Existing, p.Content)
+}
+
+// PlanFiles compares rendered files with the contents of outputDir
+func PlanFiles(outputDir string, files []RenderedFile) ([]PlannedFile, error) {
+	plan := make([]PlannedFile, 0, len(files))
+	for _, file := range files {
+		planned := PlannedFile{RenderedFile: file, Status: StatusCreate}
+
+		existing, err := os.ReadFile(filepath.Join(outputDir, file.Path))
+		switch {
+		case err == nil:
+			planned.Existing = existing
+			planned.Status = StatusModify
+			if bytes.Equal(existing, file.Content) {
+				planned.Status = StatusUnchanged
+			}
+		case !os.IsNotExist(err):
+			return nil, err
+		}
+
+		plan = append(plan, planned)
+	}
+	return plan, nil
+}
+
+// RenderProject renders the generator's templates for config in memory,
+// followed by the project's lock file, without writing anything to
+// config.OutputDir
+func RenderProject(generator Generator, config *GeneratorConfig) ([]RenderedFile, error) {
+	templates, processor, err := newProjectProcessor(generator, config)
+	if err != nil {
+		return nil, err
+	}
+	files, err := processor.RenderDirectory(PrepareTemplateData(config))
+	if err != nil {
+		return nil, fmt.Errorf("failed to process %s templates: %w", generator.GetMetadata().DisplayName, err)
+	}
+
+	lock, err := NewLock(generator.GetName(), config, templates, files)
+	if err != nil {
+		return nil, err
+	}
+	lockFile, err := lock.File()
+	if err != nil {
+		return nil, err
+	}
+	return append(files, lockFile), nil
+}
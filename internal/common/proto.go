@@ -0,0 +1,1194 @@
+package common
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Well-known types that rpcs and fields may use
+const (
+	protoEmpty       = "google.protobuf.Empty"
+	protoTimestamp   = "google.protobuf.Timestamp"
+	protoAnnotations = "google/api/annotations.proto"
+)
+
+// ProtoContract is the gRPC side of a contract read from a .proto file: the
+// file itself, copied into the project, and the services it declares, for
+// which templates render server stubs
+type ProtoContract struct {
+	File     string         `json:"file"`    // file name, e.g. orders.proto
+	Package  string         `json:"package"` // proto package, e.g. acme.orders.v1
+	Imports  []string       `json:"imports,omitempty"`
+	Source   string         `json:"source"` // the file as written
+	Services []ProtoService `json:"services"`
+}
+
+// ProtoService is a service of a .proto file
+type ProtoService struct {
+	Name    string     `json:"name"`
+	Domains []string   `json:"domains,omitempty"` // domains its rpcs are mapped to, in order
+	RPCs    []ProtoRPC `json:"rpcs"`
+}
+
+// ProtoRPC is an rpc of a service
+type ProtoRPC struct {
+	Name            string `json:"name"`
+	Input           string `json:"input"`  // message of the file, or google.protobuf.Empty
+	Output          string `json:"output"` // message of the file, or google.protobuf.Empty
+	ClientStreaming bool   `json:"client_streaming,omitempty"`
+	ServerStreaming bool   `json:"server_streaming,omitempty"`
+	Domain          string `json:"domain,omitempty"` // domain whose REST route it is, if any
+}
+
+// HTTPAnnotations reports whether the file imports the google.api.http
+// annotations, whose definitions the project then needs next to it
+func (p *ProtoContract) HTTPAnnotations() bool {
+	return slices.Contains(p.Imports, protoAnnotations)
+}
+
+// UsesEmpty reports whether an rpc takes or returns google.protobuf.Empty
+func (p *ProtoContract) UsesEmpty() bool {
+	return p.hasRPC(func(rpc ProtoRPC) bool { return rpc.Input == protoEmpty || rpc.Output == protoEmpty })
+}
+
+// HasUnary reports whether an rpc streams in neither direction
+func (p *ProtoContract) HasUnary() bool {
+	return p.hasRPC(func(rpc ProtoRPC) bool { return !rpc.ClientStreaming && !rpc.ServerStreaming })
+}
+
+// ServesDomains reports whether an rpc is mapped to a domain
+func (p *ProtoContract) ServesDomains() bool {
+	return slices.ContainsFunc(p.Services, func(s ProtoService) bool { return len(s.Domains) > 0 })
+}
+
+// hasRPC reports whether any rpc of any service matches
+func (p *ProtoContract) hasRPC(match func(ProtoRPC) bool) bool {
+	return slices.ContainsFunc(p.Services, func(s ProtoService) bool { return slices.ContainsFunc(s.RPCs, match) })
+}
+
+// SwiftPrefix returns the prefix SwiftProtobuf gives the types of the
+// package, e.g. Acme_Orders_V1_ for acme.orders.v1
+func (p *ProtoContract) SwiftPrefix() string {
+	var prefix strings.Builder
+	for component := range strings.SplitSeq(p.Package, ".") {
+		if component != "" {
+			prefix.WriteString(Pascal(component) + "_")
+		}
+	}
+	return prefix.String()
+}
+
+// Streaming reports whether the rpc streams in either direction
+func (r ProtoRPC) Streaming() bool {
+	return r.ClientStreaming || r.ServerStreaming
+}
+
+// protoFieldTypes maps scalar types and Timestamp to field types
+var protoFieldTypes = map[string]string{
+	"string": FieldString,
+	"bool":   FieldBool,
+	"int32":  FieldInt, "int64": FieldInt, "uint32": FieldInt, "uint64": FieldInt,
+	"sint32": FieldInt, "sint64": FieldInt, "fixed32": FieldInt, "fixed64": FieldInt,
+	"sfixed32": FieldInt, "sfixed64": FieldInt,
+	"float": FieldFloat, "double": FieldFloat,
+	protoTimestamp: FieldDateTime,
+}
+
+// protoWrappers maps the wrapper types to the field types of their
+// optional values
+var protoWrappers = map[string]string{
+	"google.protobuf.StringValue": FieldString,
+	"google.protobuf.BoolValue":   FieldBool,
+	"google.protobuf.Int32Value":  FieldInt,
+	"google.protobuf.Int64Value":  FieldInt,
+	"google.protobuf.UInt32Value": FieldInt,
+	"google.protobuf.UInt64Value": FieldInt,
+	"google.protobuf.FloatValue":  FieldFloat,
+	"google.protobuf.DoubleValue": FieldFloat,
+}
+
+// protoVersion matches a version as the last component of a package
+var protoVersion = regexp.MustCompile(`^v[0-9]+[a-z0-9]*$`)
+
+// protoPathVariable matches a variable of a google.api.http path template,
+// {name} or {name=pattern}
+var protoPathVariable = regexp.MustCompile(`\{([^}=]+)(?:=([^}]*))?\}`)
+
+// LoadProto imports the services and messages of a proto3 file without
+// protoc. The messages that rpcs take or return become domains, their
+// scalar fields become fields, and each rpc gets the REST route of its
+// google.api.http option, or the conventional route of its name (ListOrders
+// is GET /v1/orders) when it has none.
+func LoadProto(file string) (*Contract, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := parseProto(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid proto file %s: %w", file, err)
+	}
+	if len(parsed.services) == 0 {
+		return nil, fmt.Errorf("%s declares no service", file)
+	}
+
+	importer := &protoImporter{file: parsed}
+	importer.contract.Proto = &ProtoContract{
+		File:    filepath.Base(file),
+		Package: parsed.pkg,
+		Imports: parsed.imports,
+		Source:  string(content),
+	}
+	if err := importer.importServices(); err != nil {
+		return nil, fmt.Errorf("invalid proto file %s: %w", file, err)
+	}
+	if len(importer.contract.Domains) == 0 {
+		return nil, fmt.Errorf("no rpc of %s takes or returns a message of the file", file)
+	}
+	return &importer.contract, nil
+}
+
+// protoImporter builds a contract from a parsed .proto file
+type protoImporter struct {
+	file     *protoFile
+	contract Contract
+}
+
+// warn records something the file declares that was not imported
+func (im *protoImporter) warn(format string, args ...any) {
+	im.contract.Warnings = append(im.contract.Warnings, fmt.Sprintf(format, args...))
+}
+
+// importServices maps every rpc to the domain of the message it serves and
+// records the services for the gRPC stubs. Domains are created in the order
+// their messages are declared.
+func (im *protoImporter) importServices() error {
+	type pending struct {
+		message   string
+		operation Operation
+		rpc       *ProtoRPC
+	}
+	var operations []pending
+
+	for _, service := range im.file.services {
+		stub := ProtoService{Name: service.name}
+		for _, decl := range service.rpcs {
+			input, err := im.rpcType(decl.input)
+			if err != nil {
+				return fmt.Errorf("line %d: rpc %s: %w", decl.line, decl.name, err)
+			}
+			output, err := im.rpcType(decl.output)
+			if err != nil {
+				return fmt.Errorf("line %d: rpc %s: %w", decl.line, decl.name, err)
+			}
+			stub.RPCs = append(stub.RPCs, ProtoRPC{
+				Name:            decl.name,
+				Input:           input,
+				Output:          output,
+				ClientStreaming: decl.clientStreaming,
+				ServerStreaming: decl.serverStreaming,
+			})
+		}
+		im.contract.Proto.Services = append(im.contract.Proto.Services, stub)
+	}
+
+	for i, service := range im.file.services {
+		for j, decl := range service.rpcs {
+			rpc := &im.contract.Proto.Services[i].RPCs[j]
+			if rpc.Streaming() {
+				im.warn("%s.%s is served over gRPC only: streaming rpcs have no REST route", service.name, rpc.Name)
+				continue
+			}
+			message, operation, err := im.operation(decl, *rpc)
+			if err != nil {
+				return fmt.Errorf("line %d: rpc %s: %w", decl.line, decl.name, err)
+			}
+			if message == "" {
+				im.warn("%s.%s is served over gRPC only: it takes and returns no message the REST routes could serve", service.name, rpc.Name)
+				continue
+			}
+			operations = append(operations, pending{message, operation, rpc})
+		}
+	}
+
+	for _, message := range im.file.messages {
+		if !slices.ContainsFunc(operations, func(p pending) bool { return p.message == message.name }) {
+			continue
+		}
+		fields, idType := im.fields(message)
+		domain := Domain{Name: Camel(message.name), Fields: fields}
+		if !domainNamePattern.MatchString(domain.Name) {
+			return fmt.Errorf("message '%s' cannot be a domain: its name must start with a letter", message.name)
+		}
+		for _, p := range operations {
+			if p.message == message.name {
+				domain.Operations = append(domain.Operations, p.operation)
+				p.rpc.Domain = domain.Name
+			}
+		}
+		classifyOperations(domain.Operations)
+		if err := checkOperationNames(domain); err != nil {
+			return err
+		}
+		im.contract.Domains = append(im.contract.Domains, domain)
+		im.contract.setIDType(domain.Name, idType)
+	}
+
+	for i := range im.contract.Proto.Services {
+		service := &im.contract.Proto.Services[i]
+		for _, rpc := range service.RPCs {
+			if rpc.Domain != "" && !slices.Contains(service.Domains, rpc.Domain) {
+				service.Domains = append(service.Domains, rpc.Domain)
+			}
+		}
+	}
+	return nil
+}
+
+// rpcType resolves the input or output type of an rpc to a top-level
+// message of the file or google.protobuf.Empty
+func (im *protoImporter) rpcType(name string) (string, error) {
+	if resolved := im.file.resolve(name, ""); resolved != nil {
+		if strings.Contains(resolved.name, ".") {
+			return "", fmt.Errorf("'%s' is a nested message; rpcs must take and return top-level messages", name)
+		}
+		return resolved.name, nil
+	}
+	if strings.TrimPrefix(name, ".") == protoEmpty {
+		return protoEmpty, nil
+	}
+	return "", fmt.Errorf("'%s' is not a message of the file (only google.protobuf.Empty may come from another file)", name)
+}
+
+// operation returns the REST route of a unary rpc with the message whose
+// domain serves it: the message it returns, else the one it takes or wraps
+// in its request, else the one its name ends in (DeleteOrder)
+func (im *protoImporter) operation(decl protoRPCDecl, rpc ProtoRPC) (string, Operation, error) {
+	operation := Operation{Name: rpc.Name, Status: http.StatusOK}
+
+	var message string
+	output := im.file.message(rpc.Output)
+	switch {
+	case output != nil && isResource(output.name):
+		message, operation.Response = output.name, ResponseEntity
+	case output != nil:
+		if listed := im.listed(output); listed != "" {
+			message, operation.Response = listed, ResponseList
+		}
+	}
+	requested := ""
+	if input := im.file.message(rpc.Input); input != nil {
+		requested = im.requested(input)
+	}
+	if message == "" {
+		message = requested
+	}
+	if message == "" {
+		message = im.named(rpc.Name)
+	}
+	if message == "" {
+		return "", operation, nil
+	}
+
+	if decl.http != nil {
+		if decl.http.path == "" {
+			return "", operation, fmt.Errorf("the google.api.http option has no path")
+		}
+		operation.Method = strings.ToUpper(decl.http.method)
+		operation.Path = restPath(decl.http.path)
+		operation.Request = decl.http.body != "" && requested == message
+	} else {
+		var err error
+		if operation.Method, operation.Path, err = im.conventionalRoute(rpc, message); err != nil {
+			return "", operation, err
+		}
+		operation.Request = operation.Method != http.MethodGet && operation.Method != http.MethodDelete && requested == message
+	}
+	if segments := strings.Split(operation.Path, "/"); len(segments) > 0 {
+		if name, ok := pathParam(segments[len(segments)-1]); ok {
+			operation.Param = name
+		}
+	}
+	return message, operation, nil
+}
+
+// listed returns the resource a response message lists in its only
+// repeated message field (ListOrdersResponse.orders)
+func (im *protoImporter) listed(message *protoMessage) string {
+	var listed string
+	for _, field := range message.fields {
+		if field.label != "repeated" || field.isMap {
+			continue
+		}
+		resolved := im.file.resolve(field.typeName, message.name)
+		if resolved == nil || !isResource(resolved.name) || listed != "" {
+			return ""
+		}
+		listed = resolved.name
+	}
+	return listed
+}
+
+// requested returns the resource a request is, or wraps in a field of its
+// own (CreateOrderRequest.order)
+func (im *protoImporter) requested(message *protoMessage) string {
+	if isResource(message.name) {
+		return message.name
+	}
+	for _, field := range message.fields {
+		if field.label == "repeated" || field.isMap {
+			continue
+		}
+		if resolved := im.file.resolve(field.typeName, message.name); resolved != nil && isResource(resolved.name) {
+			return resolved.name
+		}
+	}
+	return ""
+}
+
+// named returns the resource an rpc name ends in, without its verb
+// (DeleteOrder and ListOrders name Order)
+func (im *protoImporter) named(rpc string) string {
+	words := splitWords(rpc)
+	if len(words) < 2 {
+		return ""
+	}
+	noun := strings.Join(words[1:], "")
+	for _, message := range im.file.messages {
+		if isResource(message.name) && (message.name == noun || Pluralize(message.name) == noun) {
+			return message.name
+		}
+	}
+	return ""
+}
+
+// conventionalRoute returns the route of an rpc without a google.api.http
+// option after its verb, under the version of the package (acme.orders.v1
+// serves /v1/orders). Other verbs are POST routes named after the verb, on
+// the item when the request has an id field. A name without letters or
+// digits (rpc _) has no verb and needs an option.
+func (im *protoImporter) conventionalRoute(rpc ProtoRPC, message string) (string, string, error) {
+	collection := "/" + Pluralize(Kebab(message))
+	if components := strings.Split(im.file.pkg, "."); protoVersion.MatchString(components[len(components)-1]) {
+		collection = "/" + components[len(components)-1] + collection
+	}
+	item := collection + "/{id}"
+
+	words := splitWords(rpc.Name)
+	if len(words) == 0 {
+		return "", "", fmt.Errorf("no REST route can be derived from the name '%s'; give the rpc a google.api.http option", rpc.Name)
+	}
+	switch verb := words[0]; verb {
+	case "List":
+		return http.MethodGet, collection, nil
+	case "Get":
+		return http.MethodGet, item, nil
+	case "Create":
+		return http.MethodPost, collection, nil
+	case "Update":
+		return http.MethodPatch, item, nil
+	case "Delete":
+		return http.MethodDelete, item, nil
+	default:
+		if input := im.file.message(rpc.Input); input != nil && slices.ContainsFunc(input.fields, func(f protoField) bool { return f.name == "id" }) {
+			return http.MethodPost, item + "/" + Kebab(verb), nil
+		}
+		return http.MethodPost, collection + "/" + Kebab(verb), nil
+	}
+}
+
+// fields maps the scalar fields of a message to fields. Enums become
+// strings; wrappers and optional fields are optional; repeated, map and
+// message fields are left out, and so are the fields every entity has, of
+// which the field type of id is returned.
+func (im *protoImporter) fields(message *protoMessage) ([]Field, string) {
+	var fields []Field
+	var idType string
+	for _, decl := range message.fields {
+		fieldType, optional, ok := im.fieldType(decl, message.name)
+		if !ok {
+			im.warn("%s.%s skipped: only scalar, enum, Timestamp and wrapper fields become fields", message.name, decl.name)
+			continue
+		}
+		field, err := Field{Name: decl.name, Type: fieldType}.normalize()
+		switch {
+		case reservedFields[field.Name]:
+			if field.Name == "id" {
+				idType = cmp.Or(idType, fieldType)
+			}
+			continue
+		case err != nil:
+			im.warn("%s.%s skipped: %v", message.name, decl.name, err)
+			continue
+		case slices.ContainsFunc(fields, func(f Field) bool { return f.Name == field.Name }):
+			continue
+		}
+		field.Optional = optional || decl.label == "optional" || decl.oneof
+		fields = append(fields, field)
+	}
+	if len(fields) == 0 {
+		im.warn("%s has no scalar fields; it gets the default fields", message.name)
+	}
+	return fields, idType
+}
+
+// fieldType maps the type of a message field to a field type
+func (im *protoImporter) fieldType(decl protoField, scope string) (string, bool, bool) {
+	if decl.label == "repeated" || decl.isMap {
+		return "", false, false
+	}
+	typeName := strings.TrimPrefix(decl.typeName, ".")
+	if fieldType, ok := protoFieldTypes[typeName]; ok {
+		return fieldType, false, true
+	}
+	if fieldType, ok := protoWrappers[typeName]; ok {
+		return fieldType, true, true
+	}
+	if im.file.isEnum(decl.typeName, scope) {
+		return FieldString, false, true
+	}
+	return "", false, false
+}
+
+// isResource reports whether a message is an entity rather than the request
+// or response of an rpc
+func isResource(message string) bool {
+	return !strings.Contains(message, ".") && !strings.HasSuffix(message, "Request") && !strings.HasSuffix(message, "Response")
+}
+
+// restPath converts a google.api.http path template to a route with {param}
+// placeholders: {name=orders/*} becomes orders/{name}, earlier wildcards of
+// a pattern are named after the segment before them, and a custom verb
+// (:cancel) becomes the last segment
+func restPath(template string) string {
+	route := protoPathVariable.ReplaceAllStringFunc(template, func(variable string) string {
+		match := protoPathVariable.FindStringSubmatch(variable)
+		name := strings.ReplaceAll(match[1], ".", "_")
+		if match[2] == "" {
+			return "{" + name + "}"
+		}
+
+		parts := strings.Split(match[2], "/")
+		last := -1
+		for i, part := range parts {
+			if part == "*" || part == "**" {
+				last = i
+			}
+		}
+		for i, part := range parts {
+			switch {
+			case part != "*" && part != "**":
+			case i == last:
+				parts[i] = "{" + name + "}"
+			case i > 0:
+				parts[i] = "{" + Snake(Singularize(parts[i-1])) + "_id}"
+			default:
+				parts[i] = "{parent}"
+			}
+		}
+		return strings.Join(parts, "/")
+	})
+
+	segments := strings.Split(route, "/")
+	if before, verb, ok := strings.Cut(segments[len(segments)-1], ":"); ok {
+		segments = append(segments[:len(segments)-1], before, verb)
+	}
+	return strings.Join(segments, "/")
+}
+
+// protoFile is the part of a .proto file ccin reads
+type protoFile struct {
+	pkg      string
+	imports  []string
+	messages []*protoMessage // top-level and nested, by name relative to the package (Order.Item)
+	enums    []string        // by name relative to the package
+	services []protoServiceDecl
+}
+
+// protoMessage is a message declaration
+type protoMessage struct {
+	name   string
+	fields []protoField
+}
+
+// protoField is a field of a message
+type protoField struct {
+	name     string
+	typeName string
+	label    string // repeated, optional or empty
+	isMap    bool
+	oneof    bool
+}
+
+// protoServiceDecl is a service declaration
+type protoServiceDecl struct {
+	name string
+	rpcs []protoRPCDecl
+}
+
+// protoRPCDecl is an rpc declaration
+type protoRPCDecl struct {
+	name            string
+	input, output   string
+	clientStreaming bool
+	serverStreaming bool
+	http            *protoHTTPRule
+	line            int
+}
+
+// protoHTTPRule is the google.api.http option of an rpc
+type protoHTTPRule struct {
+	method string
+	path   string
+	body   string
+}
+
+// message returns a message by its name relative to the package
+func (f *protoFile) message(name string) *protoMessage {
+	for _, message := range f.messages {
+		if message.name == name {
+			return message
+		}
+	}
+	return nil
+}
+
+// resolve finds the message a type name refers to from within a message
+// scope, searching the scope and its parents as protoc does
+func (f *protoFile) resolve(typeName, scope string) *protoMessage {
+	for _, candidate := range f.candidates(typeName, scope) {
+		if message := f.message(candidate); message != nil {
+			return message
+		}
+	}
+	return nil
+}
+
+// isEnum reports whether a type name refers to an enum of the file
+func (f *protoFile) isEnum(typeName, scope string) bool {
+	return slices.ContainsFunc(f.candidates(typeName, scope), func(candidate string) bool {
+		return slices.Contains(f.enums, candidate)
+	})
+}
+
+// candidates lists the names relative to the package a type name may
+// refer to from within a scope, innermost first
+func (f *protoFile) candidates(typeName, scope string) []string {
+	if absolute, ok := strings.CutPrefix(typeName, "."); ok {
+		if f.pkg == "" {
+			return []string{absolute}
+		}
+		if relative, ok := strings.CutPrefix(absolute, f.pkg+"."); ok {
+			return []string{relative}
+		}
+		return nil
+	}
+
+	var candidates []string
+	for scope != "" {
+		candidates = append(candidates, scope+"."+typeName)
+		i := strings.LastIndex(scope, ".")
+		if i < 0 {
+			break
+		}
+		scope = scope[:i]
+	}
+	candidates = append(candidates, typeName)
+	if relative, ok := strings.CutPrefix(typeName, f.pkg+"."); ok && f.pkg != "" {
+		candidates = append(candidates, relative)
+	}
+	return candidates
+}
+
+// protoToken is a token of a .proto file
+type protoToken struct {
+	text   string
+	quoted bool // a string literal, with text unescaped
+	line   int
+}
+
+// tokenizeProto splits a .proto file into identifiers, numbers, string
+// literals and symbols, dropping comments
+func tokenizeProto(source string) ([]protoToken, error) {
+	var tokens []protoToken
+	runes := []rune(source)
+	line := 1
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			start := line
+			i += 2
+			for i+1 < len(runes) && !(runes[i] == '*' && runes[i+1] == '/') {
+				if runes[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			if i+1 >= len(runes) {
+				return nil, fmt.Errorf("line %d: unterminated comment", start)
+			}
+			i += 2
+		case r == '"' || r == '\'':
+			var text strings.Builder
+			i++
+			for i < len(runes) && runes[i] != r {
+				if runes[i] == '\n' {
+					return nil, fmt.Errorf("line %d: unterminated string", line)
+				}
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+					switch runes[i] {
+					case 'n':
+						text.WriteRune('\n')
+					case 't':
+						text.WriteRune('\t')
+					default:
+						text.WriteRune(runes[i])
+					}
+				} else {
+					text.WriteRune(runes[i])
+				}
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			i++
+			tokens = append(tokens, protoToken{text: text.String(), quoted: true, line: line})
+		case isProtoWord(r) || (r == '.' && i+1 < len(runes) && isProtoWord(runes[i+1])):
+			start := i
+			for i < len(runes) && (isProtoWord(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, protoToken{text: string(runes[start:i]), line: line})
+		default:
+			tokens = append(tokens, protoToken{text: string(r), line: line})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// isProtoWord reports whether a rune belongs to an identifier or number
+func isProtoWord(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// protoParser reads the declarations ccin needs from the tokens of a
+// .proto file and skips the rest (options, reserved ranges, extensions)
+type protoParser struct {
+	tokens []protoToken
+	pos    int
+	file   protoFile
+}
+
+// parseProto parses a proto3 file
+func parseProto(source string) (*protoFile, error) {
+	tokens, err := tokenizeProto(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &protoParser{tokens: tokens}
+	if err := p.parseFile(); err != nil {
+		return nil, err
+	}
+	return &p.file, nil
+}
+
+// peek returns the current token, or an empty one at the end of the file
+func (p *protoParser) peek() protoToken {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	line := 1
+	if len(p.tokens) > 0 {
+		line = p.tokens[len(p.tokens)-1].line
+	}
+	return protoToken{line: line}
+}
+
+// next consumes the current token
+func (p *protoParser) next() protoToken {
+	token := p.peek()
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+	return token
+}
+
+// errorf reports a problem at the current token
+func (p *protoParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", p.peek().line, fmt.Sprintf(format, args...))
+}
+
+// expect consumes a symbol or keyword
+func (p *protoParser) expect(text string) error {
+	if token := p.peek(); token.quoted || token.text != text {
+		if token.text == "" && !token.quoted {
+			return p.errorf("expected '%s' but the file ends", text)
+		}
+		return p.errorf("expected '%s', found '%s'", text, token.text)
+	}
+	p.next()
+	return nil
+}
+
+// accept consumes a symbol or keyword when it is next
+func (p *protoParser) accept(text string) bool {
+	if token := p.peek(); !token.quoted && token.text == text {
+		p.next()
+		return true
+	}
+	return false
+}
+
+// ident consumes an identifier, possibly dotted
+func (p *protoParser) ident(what string) (string, error) {
+	token := p.peek()
+	if token.quoted || token.text == "" || !(isProtoWord([]rune(token.text)[0]) || token.text[0] == '.') {
+		return "", p.errorf("expected %s, found '%s'", what, token.text)
+	}
+	p.next()
+	return token.text, nil
+}
+
+// str consumes a string literal; adjacent literals are concatenated
+func (p *protoParser) str(what string) (string, error) {
+	if !p.peek().quoted {
+		return "", p.errorf("expected %s", what)
+	}
+	var text string
+	for p.peek().quoted {
+		text += p.next().text
+	}
+	return text, nil
+}
+
+// parseFile parses the top-level statements
+func (p *protoParser) parseFile() error {
+	for p.pos < len(p.tokens) {
+		switch keyword := p.next(); keyword.text {
+		case "syntax":
+			if err := p.expect("="); err != nil {
+				return err
+			}
+			syntax, err := p.str("the syntax")
+			if err != nil {
+				return err
+			}
+			if syntax != "proto3" {
+				return fmt.Errorf("line %d: syntax '%s' is not supported, use proto3", keyword.line, syntax)
+			}
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+		case "edition":
+			return fmt.Errorf("line %d: editions are not supported, use syntax = \"proto3\"", keyword.line)
+		case "package":
+			pkg, err := p.ident("a package name")
+			if err != nil {
+				return err
+			}
+			p.file.pkg = pkg
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+		case "import":
+			if !p.accept("public") {
+				p.accept("weak")
+			}
+			imported, err := p.str("the imported file")
+			if err != nil {
+				return err
+			}
+			p.file.imports = append(p.file.imports, imported)
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+		case "option":
+			if err := p.skipStatement(); err != nil {
+				return err
+			}
+		case "message":
+			if err := p.parseMessage(""); err != nil {
+				return err
+			}
+		case "enum":
+			if err := p.parseEnum(""); err != nil {
+				return err
+			}
+		case "service":
+			if err := p.parseService(); err != nil {
+				return err
+			}
+		case "extend":
+			if err := p.skipStatement(); err != nil {
+				return err
+			}
+		case ";":
+		default:
+			return fmt.Errorf("line %d: unexpected '%s'", keyword.line, keyword.text)
+		}
+	}
+	return nil
+}
+
+// parseMessage parses a message and its nested messages and enums
+func (p *protoParser) parseMessage(scope string) error {
+	name, err := p.ident("a message name")
+	if err != nil {
+		return err
+	}
+	if scope != "" {
+		name = scope + "." + name
+	}
+	message := &protoMessage{name: name}
+	p.file.messages = append(p.file.messages, message)
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	return p.parseMessageBody(message, false)
+}
+
+// parseMessageBody parses the statements of a message or oneof up to its
+// closing brace
+func (p *protoParser) parseMessageBody(message *protoMessage, oneof bool) error {
+	for !p.accept("}") {
+		token := p.peek()
+		if token.text == "" && !token.quoted {
+			return p.errorf("message %s is not closed", message.name)
+		}
+		switch token.text {
+		case "message":
+			p.next()
+			if err := p.parseMessage(message.name); err != nil {
+				return err
+			}
+		case "enum":
+			p.next()
+			if err := p.parseEnum(message.name); err != nil {
+				return err
+			}
+		case "oneof":
+			p.next()
+			if _, err := p.ident("a oneof name"); err != nil {
+				return err
+			}
+			if err := p.expect("{"); err != nil {
+				return err
+			}
+			if err := p.parseMessageBody(message, true); err != nil {
+				return err
+			}
+		case "option", "reserved", "extensions", "extend":
+			if err := p.skipStatement(); err != nil {
+				return err
+			}
+		case ";":
+			p.next()
+		default:
+			field, err := p.parseField()
+			if err != nil {
+				return err
+			}
+			field.oneof = oneof
+			message.fields = append(message.fields, field)
+		}
+	}
+	return nil
+}
+
+// parseField parses a field or map field up to its semicolon
+func (p *protoParser) parseField() (protoField, error) {
+	var field protoField
+	if p.accept("map") {
+		field.isMap = true
+		if err := p.expect("<"); err != nil {
+			return field, err
+		}
+		if _, err := p.ident("a map key type"); err != nil {
+			return field, err
+		}
+		if err := p.expect(","); err != nil {
+			return field, err
+		}
+		value, err := p.ident("a map value type")
+		if err != nil {
+			return field, err
+		}
+		field.typeName = value
+		if err := p.expect(">"); err != nil {
+			return field, err
+		}
+	} else {
+		if token := p.peek(); token.text == "repeated" || token.text == "optional" || token.text == "required" {
+			field.label = p.next().text
+		}
+		typeName, err := p.ident("a field type")
+		if err != nil {
+			return field, err
+		}
+		field.typeName = typeName
+	}
+
+	name, err := p.ident("a field name")
+	if err != nil {
+		return field, err
+	}
+	field.name = name
+	return field, p.skipStatement()
+}
+
+// parseEnum records an enum and skips its values
+func (p *protoParser) parseEnum(scope string) error {
+	name, err := p.ident("an enum name")
+	if err != nil {
+		return err
+	}
+	if scope != "" {
+		name = scope + "." + name
+	}
+	p.file.enums = append(p.file.enums, name)
+	if p.peek().text != "{" {
+		return p.expect("{")
+	}
+	return p.skipBlock()
+}
+
+// parseService parses a service and its rpcs
+func (p *protoParser) parseService() error {
+	name, err := p.ident("a service name")
+	if err != nil {
+		return err
+	}
+	service := protoServiceDecl{name: name}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for !p.accept("}") {
+		switch token := p.peek(); token.text {
+		case "rpc":
+			p.next()
+			rpc, err := p.parseRPC()
+			if err != nil {
+				return err
+			}
+			service.rpcs = append(service.rpcs, rpc)
+		case "option":
+			if err := p.skipStatement(); err != nil {
+				return err
+			}
+		case ";":
+			p.next()
+		case "":
+			return p.errorf("service %s is not closed", name)
+		default:
+			return p.errorf("unexpected '%s' in service %s", token.text, name)
+		}
+	}
+	p.file.services = append(p.file.services, service)
+	return nil
+}
+
+// parseRPC parses an rpc and its google.api.http option
+func (p *protoParser) parseRPC() (protoRPCDecl, error) {
+	rpc := protoRPCDecl{line: p.peek().line}
+	name, err := p.ident("an rpc name")
+	if err != nil {
+		return rpc, err
+	}
+	rpc.name = name
+
+	for _, part := range []struct {
+		keyword   string
+		streaming *bool
+		typeName  *string
+	}{{"", &rpc.clientStreaming, &rpc.input}, {"returns", &rpc.serverStreaming, &rpc.output}} {
+		if part.keyword != "" {
+			if err := p.expect(part.keyword); err != nil {
+				return rpc, err
+			}
+		}
+		if err := p.expect("("); err != nil {
+			return rpc, err
+		}
+		// "stream" is a keyword only when a type follows it
+		if token := p.peek(); token.text == "stream" && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].text != ")" {
+			p.next()
+			*part.streaming = true
+		}
+		typeName, err := p.ident("a message type")
+		if err != nil {
+			return rpc, err
+		}
+		*part.typeName = typeName
+		if err := p.expect(")"); err != nil {
+			return rpc, err
+		}
+	}
+
+	if p.accept(";") {
+		return rpc, nil
+	}
+	if err := p.expect("{"); err != nil {
+		return rpc, err
+	}
+	for !p.accept("}") {
+		switch token := p.peek(); token.text {
+		case "option":
+			p.next()
+			rule, err := p.parseRPCOption()
+			if err != nil {
+				return rpc, err
+			}
+			if rule != nil {
+				rpc.http = rule
+			}
+		case ";":
+			p.next()
+		case "":
+			return rpc, p.errorf("rpc %s is not closed", rpc.name)
+		default:
+			return rpc, p.errorf("unexpected '%s' in rpc %s", token.text, rpc.name)
+		}
+	}
+	p.accept(";")
+	return rpc, nil
+}
+
+// parseRPCOption parses an option of an rpc, returning the HTTP rule when
+// it is google.api.http
+func (p *protoParser) parseRPCOption() (*protoHTTPRule, error) {
+	var name string
+	for token := p.peek(); token.text != "=" && token.text != ";" && token.text != ""; token = p.peek() {
+		name += p.next().text
+	}
+	if err := p.expect("="); err != nil {
+		return nil, err
+	}
+	if name != "(google.api.http)" || p.peek().text != "{" {
+		return nil, p.skipStatement()
+	}
+
+	p.next()
+	rule := &protoHTTPRule{}
+	for !p.accept("}") {
+		key, err := p.ident("a field of google.api.http")
+		if err != nil {
+			return nil, err
+		}
+		p.accept(":")
+		switch key {
+		case "get", "put", "post", "delete", "patch":
+			path, err := p.str("the path of " + key)
+			if err != nil {
+				return nil, err
+			}
+			rule.method, rule.path = key, path
+		case "body":
+			body, err := p.str("the body field")
+			if err != nil {
+				return nil, err
+			}
+			rule.body = body
+		default:
+			// custom, response_body, additional_bindings and the like
+			if err := p.skipValue(); err != nil {
+				return nil, err
+			}
+		}
+		if !p.accept(",") {
+			p.accept(";")
+		}
+	}
+	return rule, p.expect(";")
+}
+
+// skipValue skips a constant or an aggregate value of an option
+func (p *protoParser) skipValue() error {
+	switch p.peek().text {
+	case "{", "[":
+		return p.skipBlock()
+	case "-":
+		p.next()
+	}
+	if token := p.next(); token.text == "" && !token.quoted {
+		return p.errorf("expected a value but the file ends")
+	}
+	for p.peek().quoted {
+		p.next()
+	}
+	return nil
+}
+
+// skipStatement skips to the semicolon ending a statement, or past the
+// block it declares, including nested brackets
+func (p *protoParser) skipStatement() error {
+	for {
+		token := p.peek()
+		switch {
+		case token.text == "" && !token.quoted:
+			return p.errorf("expected ';' but the file ends")
+		case token.quoted:
+			p.next()
+		case token.text == ";":
+			p.next()
+			return nil
+		case token.text == "}":
+			return p.expect(";")
+		case token.text == "{":
+			if err := p.skipBlock(); err != nil {
+				return err
+			}
+			// Blocks such as extend end without a semicolon
+			if p.peek().text != ";" {
+				return nil
+			}
+		case token.text == "[" || token.text == "(" || token.text == "<":
+			if err := p.skipBlock(); err != nil {
+				return err
+			}
+		default:
+			p.next()
+		}
+	}
+}
+
+// skipBlock skips a bracketed block and everything nested in it
+func (p *protoParser) skipBlock() error {
+	closing := map[string]string{"{": "}", "[": "]", "(": ")", "<": ">"}
+	var open []string
+	for {
+		token := p.next()
+		switch {
+		case token.text == "" && !token.quoted:
+			return p.errorf("unbalanced brackets")
+		case token.quoted:
+		case closing[token.text] != "":
+			open = append(open, closing[token.text])
+		case len(open) > 0 && token.text == open[len(open)-1]:
+			open = open[:len(open)-1]
+			if len(open) == 0 {
+				return nil
+			}
+		}
+	}
+}
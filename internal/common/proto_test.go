@@ -0,0 +1,351 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseProto(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		want    protoFile
+		wantErr string
+	}{
+		{
+			name: "package and imports",
+			source: `syntax = "proto3";
+package acme.orders.v1;
+import "google/api/annotations.proto";
+import public "common.proto";
+import weak "legacy.proto";
+option go_package = "example.com/orders/v1;ordersv1";`,
+			want: protoFile{
+				pkg:     "acme.orders.v1",
+				imports: []string{"google/api/annotations.proto", "common.proto", "legacy.proto"},
+			},
+		},
+		{
+			name: "nested messages and enums",
+			source: `syntax = "proto3";
+message Order {
+  message Item { string sku = 1; }
+  enum Status { STATUS_UNSPECIFIED = 0; PLACED = 1; }
+  repeated Item items = 1;
+  Status status = 2;
+  reserved 3, 4;
+  reserved "legacy";
+}
+enum Currency { CURRENCY_UNSPECIFIED = 0; }`,
+			want: protoFile{
+				messages: []*protoMessage{
+					{name: "Order", fields: []protoField{
+						{name: "items", typeName: "Item", label: "repeated"},
+						{name: "status", typeName: "Status"},
+					}},
+					{name: "Order.Item", fields: []protoField{{name: "sku", typeName: "string"}}},
+				},
+				enums: []string{"Order.Status", "Currency"},
+			},
+		},
+		{
+			name: "maps, oneofs and field options",
+			source: `syntax = "proto3";
+message Order {
+  map<string, int32> quantities = 1;
+  optional string note = 2 [deprecated = true, json_name = "n"];
+  oneof payment {
+    string card = 3;
+    google.protobuf.StringValue voucher = 4;
+  }
+  .acme.Money total = 5;
+}`,
+			want: protoFile{
+				messages: []*protoMessage{{name: "Order", fields: []protoField{
+					{name: "quantities", typeName: "int32", isMap: true},
+					{name: "note", typeName: "string", label: "optional"},
+					{name: "card", typeName: "string", oneof: true},
+					{name: "voucher", typeName: "google.protobuf.StringValue", oneof: true},
+					{name: "total", typeName: ".acme.Money"},
+				}}},
+			},
+		},
+		{
+			name: "services with google.api.http options",
+			source: `syntax = "proto3";
+service OrderService {
+  option deprecated = true;
+  rpc ListOrders(ListOrdersRequest) returns (ListOrdersResponse) {
+    option (google.api.http) = { get: "/v1/orders" };
+  }
+  rpc CreateOrder(CreateOrderRequest) returns (Order) {
+    option (google.api.http) = {
+      post: "/v1/orders"
+      body: "order"
+      additional_bindings { post: "/v1/shops/{shop}/orders" body: "*" }
+    };
+    option idempotency_level = IDEMPOTENT;
+  }
+  rpc WatchOrders(stream WatchRequest) returns (stream Order);
+  rpc Echo(stream) returns (stream);
+}`,
+			want: protoFile{
+				services: []protoServiceDecl{{name: "OrderService", rpcs: []protoRPCDecl{
+					{name: "ListOrders", input: "ListOrdersRequest", output: "ListOrdersResponse", http: &protoHTTPRule{method: "get", path: "/v1/orders"}, line: 4},
+					{name: "CreateOrder", input: "CreateOrderRequest", output: "Order", http: &protoHTTPRule{method: "post", path: "/v1/orders", body: "order"}, line: 7},
+					{name: "WatchOrders", input: "WatchRequest", output: "Order", clientStreaming: true, serverStreaming: true, line: 15},
+					{name: "Echo", input: "stream", output: "stream", line: 16},
+				}}},
+			},
+		},
+		{
+			name: "comments and concatenated strings",
+			source: `// orders
+syntax = "proto3"; /* the
+only supported syntax */
+package acme;
+import "google/" "protobuf/empty.proto"; // split literal`,
+			want: protoFile{pkg: "acme", imports: []string{"google/protobuf/empty.proto"}},
+		},
+		{
+			name:    "proto2",
+			source:  `syntax = "proto2";`,
+			wantErr: "line 1: syntax 'proto2' is not supported",
+		},
+		{
+			name:    "editions",
+			source:  `edition = "2023";`,
+			wantErr: "editions are not supported",
+		},
+		{
+			name:    "unclosed message",
+			source:  "syntax = \"proto3\";\nmessage Order {\n  string id = 1;",
+			wantErr: "message Order is not closed",
+		},
+		{
+			name:    "unterminated comment",
+			source:  "syntax = \"proto3\";\n/* open",
+			wantErr: "line 2: unterminated comment",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseProto(test.source)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("parseProto error = %v, want one containing %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseProto: %v", err)
+			}
+			if !reflect.DeepEqual(*got, test.want) {
+				t.Errorf("parseProto = %s, want %s", describeProto(got), describeProto(&test.want))
+			}
+		})
+	}
+}
+
+func TestRestPath(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"/v1/orders", "/v1/orders"},
+		{"/v1/orders/{id}", "/v1/orders/{id}"},
+		{"/v1/orders/{order.id}", "/v1/orders/{order_id}"},
+		{"/v1/{name=orders/*}", "/v1/orders/{name}"},
+		{"/v1/{name=shops/*/orders/*}", "/v1/shops/{shop_id}/orders/{name}"},
+		{"/v1/{parent=*}/orders", "/v1/{parent}/orders"},
+		{"/v1/orders/{id}:cancel", "/v1/orders/{id}/cancel"},
+		{"/v1/orders:batchGet", "/v1/orders/batchGet"},
+	}
+	for _, test := range tests {
+		if got := restPath(test.template); got != test.want {
+			t.Errorf("restPath(%q) = %q, want %q", test.template, got, test.want)
+		}
+	}
+}
+
+func TestLoadProtoRoutes(t *testing.T) {
+	const messages = `
+message Order { string id = 1; string email = 2; }
+message ListOrdersRequest { int32 page_size = 1; }
+message ListOrdersResponse { repeated Order orders = 1; }
+message GetOrderRequest { string id = 1; }
+message CreateOrderRequest { Order order = 1; }
+message CancelOrderRequest { string id = 1; }
+message ExportOrdersRequest { string format = 1; }
+`
+	tests := []struct {
+		name     string
+		services string
+		want     []string // METHOD path of each operation, in order
+		wantErr  string
+	}{
+		{
+			name: "conventional routes under the package version",
+			services: `package acme.orders.v1;
+service OrderService {
+  rpc ListOrders(ListOrdersRequest) returns (ListOrdersResponse);
+  rpc GetOrder(GetOrderRequest) returns (Order);
+  rpc CreateOrder(CreateOrderRequest) returns (Order);
+  rpc UpdateOrder(Order) returns (Order);
+  rpc DeleteOrder(GetOrderRequest) returns (google.protobuf.Empty);
+  rpc CancelOrder(CancelOrderRequest) returns (Order);
+  rpc ExportOrders(ExportOrdersRequest) returns (Order);
+}`,
+			want: []string{
+				"GET /v1/orders",
+				"GET /v1/orders/{id}",
+				"POST /v1/orders",
+				"PATCH /v1/orders/{id}",
+				"DELETE /v1/orders/{id}",
+				"POST /v1/orders/{id}/cancel",
+				"POST /v1/orders/export",
+			},
+		},
+		{
+			name: "unversioned package",
+			services: `package acme.orders;
+service OrderService { rpc GetOrder(GetOrderRequest) returns (Order); }`,
+			want: []string{"GET /orders/{id}"},
+		},
+		{
+			name: `google.api.http options`,
+			services: `package acme.orders.v1;
+service OrderService {
+  rpc GetOrder(GetOrderRequest) returns (Order) { option (google.api.http) = { get: "/v1/{id=orders/*}" }; }
+  rpc CancelOrder(CancelOrderRequest) returns (Order) { option (google.api.http) = { post: "/v1/orders/{id}:cancel" body: "*" }; }
+}`,
+			want: []string{"GET /v1/orders/{id}", "POST /v1/orders/{id}/cancel"},
+		},
+		{
+			name: "streaming rpcs have no route",
+			services: `service OrderService {
+  rpc WatchOrders(google.protobuf.Empty) returns (stream Order);
+  rpc GetOrder(GetOrderRequest) returns (Order);
+}`,
+			want: []string{"GET /orders/{id}"},
+		},
+		{
+			name:     "rpc name without letters or digits",
+			services: `service OrderService { rpc _(Order) returns (Order); }`,
+			wantErr:  "rpc _: no REST route can be derived from the name '_'",
+		},
+		{
+			name:     "option without a path",
+			services: `service OrderService { rpc GetOrder(GetOrderRequest) returns (Order) { option (google.api.http) = { body: "*" }; } }`,
+			wantErr:  "the google.api.http option has no path",
+		},
+		{
+			name:     "nested message as input",
+			services: `message Shop { message Order { string id = 1; } } service S { rpc GetOrder(Shop.Order) returns (Order); }`,
+			wantErr:  "is a nested message",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "orders.proto")
+			source := "syntax = \"proto3\";\n" + test.services + messages
+			if err := os.WriteFile(file, []byte(source), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			contract, err := LoadProto(file)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("LoadProto error = %v, want one containing %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadProto: %v", err)
+			}
+
+			var got []string
+			for _, domain := range contract.Domains {
+				for _, operation := range domain.Operations {
+					got = append(got, operation.Method+" "+operation.Path)
+				}
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("routes = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLoadProtoFields(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "orders.proto")
+	source := `syntax = "proto3";
+import "google/protobuf/timestamp.proto";
+import "google/protobuf/wrappers.proto";
+message Order {
+  enum Status { STATUS_UNSPECIFIED = 0; }
+  string id = 1;
+  string email = 2;
+  Status status = 3;
+  google.protobuf.Timestamp placed_at = 4;
+  google.protobuf.Int64Value points = 5;
+  optional string note = 6;
+  oneof payment { string card = 7; }
+  repeated string tags = 8;
+  map<string, string> labels = 9;
+}
+service OrderService { rpc GetOrder(Order) returns (Order); }
+`
+	if err := os.WriteFile(file, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	contract, err := LoadProto(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Field{
+		{Name: "email", Type: FieldString},
+		{Name: "status", Type: FieldString},
+		{Name: "placed_at", Type: FieldDateTime},
+		{Name: "points", Type: FieldInt, Optional: true},
+		{Name: "note", Type: FieldString, Optional: true},
+		{Name: "card", Type: FieldString, Optional: true},
+	}
+	if got := contract.Domains[0].Fields; !reflect.DeepEqual(got, want) {
+		t.Errorf("fields = %+v, want %+v", got, want)
+	}
+	if got := contract.IDTypes["order"]; got != FieldString {
+		t.Errorf("id type = %q, want %q", got, FieldString)
+	}
+	if len(contract.Warnings) != 2 {
+		t.Errorf("warnings = %q, want one for tags and one for labels", contract.Warnings)
+	}
+}
+
+// describeProto formats a parsed file for failure messages
+func describeProto(file *protoFile) string {
+	var b strings.Builder
+	b.WriteString("{pkg: " + file.pkg + ", imports: " + strings.Join(file.imports, " ") + ", enums: " + strings.Join(file.enums, " "))
+	for _, message := range file.messages {
+		b.WriteString(", message " + message.name)
+		for _, field := range message.fields {
+			b.WriteString(" " + strings.TrimSpace(field.label+" "+field.typeName) + " " + field.name)
+		}
+	}
+	for _, service := range file.services {
+		b.WriteString(", service " + service.name)
+		for _, rpc := range service.rpcs {
+			b.WriteString(" " + rpc.name + "(" + rpc.input + ")" + rpc.output)
+			if rpc.http != nil {
+				b.WriteString(" " + rpc.http.method + " " + rpc.http.path)
+			}
+		}
+	}
+	return b.String() + "}"
+}
@@ -2,6 +2,7 @@ package common
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 )
 
@@ -38,7 +39,15 @@ func (gr *GeneratorRegistry) Get(name string) (Generator, error) {
 	return generator, nil
 }
 
-// List returns all registered generator names
+// Has reports whether a generator is registered under name
+func (gr *GeneratorRegistry) Has(name string) bool {
+	gr.mutex.RLock()
+	defer gr.mutex.RUnlock()
+	_, exists := gr.generators[name]
+	return exists
+}
+
+// List returns all registered generator names in alphabetical order
 func (gr *GeneratorRegistry) List() []string {
 	gr.mutex.RLock()
 	defer gr.mutex.RUnlock()
@@ -47,6 +56,7 @@ func (gr *GeneratorRegistry) List() []string {
 	for name := range gr.generators {
 		names = append(names, name)
 	}
+	sort.Strings(names)
 	
 	return names
 }
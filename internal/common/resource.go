@@ -0,0 +1,244 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// markerPrefix starts the comment that marks a registration region in a
+// generated file (e.g. "// ccin:routes"). Shared templates define the region
+// body with {{define "routes"}}, render it once per domain on the line before
+// the marker, and `ccin add resource` inserts new domains right before it.
+const markerPrefix = "ccin:"
+
+// Registration is a region snippet for a new domain that belongs in a shared file
+type Registration struct {
+	File    string // path relative to the project directory
+	Region  string // region name, matching the ccin:<region> marker
+	Snippet string // rendered region for the new domain
+}
+
+// ResourceResult lists what AddResource changed in a project
+type ResourceResult struct {
+	Created []string       // per-domain files written
+	Patched []string       // shared files where the domain was registered
+	Manual  []Registration // registrations whose marker was not found
+}
+
+// AddResource renders the per-domain templates for the domain in
+// data.DomainData into the output directory and registers the domain in the
+// shared files by inserting each region before its ccin:<region> marker.
+// Existing files are never overwritten; registrations that cannot be placed
+// are returned in ResourceResult.Manual. Templates the manifest excludes
+// are ignored.
+func (tp *TemplateProcessor) AddResource(data *TemplateData) (*ResourceResult, error) {
+	data, err := tp.prepare(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var perDomain, shared []string
+	err = fs.WalkDir(tp.templates, ".", func(templatePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		if included, err := tp.includes(templatePath, data); err != nil || !included {
+			return err
+		}
+		if isPerDomain(strings.TrimSuffix(templatePath, ".tpl")) {
+			perDomain = append(perDomain, templatePath)
+		} else {
+			shared = append(shared, templatePath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Refuse to touch a domain that already has files
+	for _, templatePath := range perDomain {
+		relPath := tp.relativePath(templatePath, data)
+		if _, err := os.Stat(filepath.Join(tp.outputDir, relPath)); err == nil {
+			return nil, fmt.Errorf("resource '%s' already exists (found %s)", data.DomainLower, relPath)
+		}
+	}
+
+	result := &ResourceResult{}
+	for _, templatePath := range perDomain {
+		content, err := tp.renderTemplate(templatePath, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", templatePath, err)
+		}
+		if content == nil {
+			continue
+		}
+
+		relPath := tp.relativePath(templatePath, data)
+		if err := writeFile(filepath.Join(tp.outputDir, relPath), content); err != nil {
+			return nil, err
+		}
+		result.Created = append(result.Created, relPath)
+	}
+
+	for _, templatePath := range shared {
+		if err := tp.registerDomain(templatePath, data, result); err != nil {
+			return nil, fmt.Errorf("failed to register %s in %s: %w", data.DomainLower, templatePath, err)
+		}
+	}
+
+	return result, nil
+}
+
+// registerDomain inserts the regions of one shared template for the new domain
+func (tp *TemplateProcessor) registerDomain(templatePath string, data *TemplateData, result *ResourceResult) error {
+	tmpl, err := tp.parseTemplate(templatePath)
+	if err != nil {
+		return err
+	}
+
+	partials, err := tp.Partials()
+	if err != nil {
+		return err
+	}
+
+	var regions []string
+	for _, region := range tmpl.Templates() {
+		if region.Name() != tmpl.Name() && partials.Lookup(region.Name()) == nil {
+			regions = append(regions, region.Name())
+		}
+	}
+	if len(regions) == 0 {
+		return nil
+	}
+	sort.Strings(regions)
+
+	// Only regions whose marker the project would contain apply (e.g. gRPC
+	// registrations are skipped for projects generated without --grpc)
+	var full bytes.Buffer
+	if err := tmpl.Execute(&full, data); err != nil {
+		return err
+	}
+
+	relPath := tp.relativePath(templatePath, data)
+	outputPath := filepath.Join(tp.outputDir, relPath)
+	existing, readErr := os.ReadFile(outputPath)
+	content := string(existing)
+	changed := false
+
+	for _, region := range regions {
+		marker := markerPrefix + region
+		if !strings.Contains(full.String(), marker) {
+			continue
+		}
+
+		var snippet bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&snippet, region, data); err != nil {
+			return err
+		}
+		if strings.Contains(content, snippet.String()) {
+			continue
+		}
+
+		index := markerIndex(content, marker)
+		if readErr != nil || index < 0 {
+			result.Manual = append(result.Manual, Registration{File: relPath, Region: region, Snippet: strings.TrimPrefix(snippet.String(), "\n")})
+			continue
+		}
+
+		content = content[:index] + snippet.String() + content[index:]
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	result.Patched = append(result.Patched, relPath)
+	return os.WriteFile(outputPath, []byte(content), 0644)
+}
+
+// markerIndex returns where a region snippet is inserted: the newline that
+// ends the line before the marker line, since region snippets start with a
+// newline. It returns -1 when the marker is missing.
+func markerIndex(content, marker string) int {
+	offset := 0
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if fields := strings.Fields(line); containsToken(fields, marker) {
+			return max(offset-1, 0)
+		}
+		offset += len(line)
+	}
+	return -1
+}
+
+// containsToken reports whether the marker appears as a whole word
+func containsToken(fields []string, marker string) bool {
+	for _, field := range fields {
+		if field == marker {
+			return true
+		}
+	}
+	return false
+}
+
+// AddResource adds the domain in config.Domains to the existing project in
+// config.OutputDir using the generator's templates
+func AddResource(generator Generator, config *GeneratorConfig) (*ResourceResult, error) {
+	templates, err := config.Templates(generator.GetName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s templates: %w", generator.GetMetadata().DisplayName, err)
+	}
+
+	processor := NewTemplateProcessor(templates, config.OutputDir)
+	result, err := processor.AddResource(PrepareTemplateData(config))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := addToLock(config, result); err != nil {
+		return nil, fmt.Errorf("failed to update %s: %w", LockFile, err)
+	}
+	return result, nil
+}
+
+// addToLock records the new domains in the project's lock file, if it has
+// one, with the hashes of the files the domain created or patched as they
+// are on disk. Every other file keeps its recorded hash: shared files that
+// are not patched, such as a README listing the domains, still hold what
+// was generated for them.
+func addToLock(config *GeneratorConfig, result *ResourceResult) error {
+	lock, err := ReadLock(config.OutputDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if lock.Config.TemplateSource, err = AbsTemplateSource(config.TemplateSource); err != nil {
+		return err
+	}
+	lock.Config.Domains = append(lock.Config.Domains, config.Domains...)
+	if lock.Files == nil {
+		lock.Files = make(map[string]string, len(result.Created)+len(result.Patched))
+	}
+	for _, relPath := range slices.Concat(result.Created, result.Patched) {
+		content, err := os.ReadFile(filepath.Join(config.OutputDir, relPath))
+		if err != nil {
+			return err
+		}
+		lock.Files[filepath.ToSlash(relPath)] = HashContent(content)
+	}
+
+	file, err := lock.File()
+	if err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(config.OutputDir, file.Path), file.Content)
+}
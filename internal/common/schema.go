@@ -0,0 +1,349 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// jsonSchema is the subset of JSON Schema (draft 2020-12) that ccin's
+// published schemas use. YAML documents are validated against it node by
+// node, so every problem is reported with its line and column.
+type jsonSchema struct {
+	Ref                  string                 `json:"$ref"`
+	Defs                 map[string]*jsonSchema `json:"$defs"`
+	Type                 schemaTypes            `json:"type"`
+	Enum                 []any                  `json:"enum"`
+	Required             []string               `json:"required"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	AdditionalProperties *additionalProperties  `json:"additionalProperties"`
+	PropertyNames        *jsonSchema            `json:"propertyNames"`
+	Items                *jsonSchema            `json:"items"`
+	MinItems             *int                   `json:"minItems"`
+	UniqueItems          bool                   `json:"uniqueItems"`
+	MinLength            *int                   `json:"minLength"`
+	Pattern              string                 `json:"pattern"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+
+	pattern *regexp.Regexp
+}
+
+// schemaTypes is the "type" keyword: one type name or a list of them
+type schemaTypes []string
+
+// UnmarshalJSON accepts a single type name as well as a list
+func (t *schemaTypes) UnmarshalJSON(content []byte) error {
+	var single string
+	if err := json.Unmarshal(content, &single); err == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+	return json.Unmarshal(content, (*[]string)(t))
+}
+
+// additionalProperties is false (no other properties) or a schema the other
+// properties must match
+type additionalProperties struct {
+	forbidden bool
+	schema    *jsonSchema
+}
+
+// UnmarshalJSON accepts a boolean or a schema
+func (a *additionalProperties) UnmarshalJSON(content []byte) error {
+	var allowed bool
+	if err := json.Unmarshal(content, &allowed); err == nil {
+		a.forbidden = !allowed
+		return nil
+	}
+	return json.Unmarshal(content, &a.schema)
+}
+
+// SchemaProblem is a place where a YAML document does not match its schema
+type SchemaProblem struct {
+	Line    int
+	Column  int
+	Path    string // e.g. domains[0].fields[1].type, empty for the document
+	Message string
+}
+
+// String returns the problem as line:column: path: message
+func (p SchemaProblem) String() string {
+	if p.Path == "" {
+		return fmt.Sprintf("%d:%d: %s", p.Line, p.Column, p.Message)
+	}
+	return fmt.Sprintf("%d:%d: %s: %s", p.Line, p.Column, p.Path, p.Message)
+}
+
+// parseSchema reads a JSON Schema and compiles its patterns
+func parseSchema(content []byte) (*jsonSchema, error) {
+	var schema jsonSchema
+	if err := json.Unmarshal(content, &schema); err != nil {
+		return nil, fmt.Errorf("invalid JSON Schema: %w", err)
+	}
+	if err := schema.compile(); err != nil {
+		return nil, fmt.Errorf("invalid JSON Schema: %w", err)
+	}
+	return &schema, nil
+}
+
+// compile compiles the patterns of the schema and its subschemas
+func (s *jsonSchema) compile() error {
+	if s == nil {
+		return nil
+	}
+	if s.Pattern != "" {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return err
+		}
+		s.pattern = pattern
+	}
+
+	subschemas := []*jsonSchema{s.PropertyNames, s.Items}
+	if s.AdditionalProperties != nil {
+		subschemas = append(subschemas, s.AdditionalProperties.schema)
+	}
+	for _, property := range s.Properties {
+		subschemas = append(subschemas, property)
+	}
+	for _, def := range s.Defs {
+		subschemas = append(subschemas, def)
+	}
+	for _, subschema := range subschemas {
+		if err := subschema.compile(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks a YAML document against the schema and returns every
+// problem in document order
+func (s *jsonSchema) Validate(doc *yaml.Node) []SchemaProblem {
+	validator := &schemaValidator{root: s}
+	if doc.Kind == 0 || doc.Kind == yaml.DocumentNode {
+		if len(doc.Content) == 0 {
+			return []SchemaProblem{{Line: 1, Column: 1, Message: "the document is empty"}}
+		}
+		doc = doc.Content[0]
+	}
+	validator.validate(s, doc, "")
+	return validator.problems
+}
+
+// schemaValidator collects the problems of one document
+type schemaValidator struct {
+	root     *jsonSchema // resolves $ref
+	problems []SchemaProblem
+}
+
+// report records a problem at a node
+func (v *schemaValidator) report(node *yaml.Node, path, format string, args ...any) {
+	v.problems = append(v.problems, SchemaProblem{Line: node.Line, Column: node.Column, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// validate checks a node against a schema
+func (v *schemaValidator) validate(schema *jsonSchema, node *yaml.Node, path string) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if schema.Ref != "" {
+		name, ok := strings.CutPrefix(schema.Ref, "#/$defs/")
+		if ref := v.root.Defs[name]; ok && ref != nil {
+			schema = ref
+		} else {
+			v.report(node, path, "the schema refers to the unknown definition %s", schema.Ref)
+			return
+		}
+	}
+
+	kind := nodeType(node)
+	if len(schema.Type) > 0 && !slices.Contains(schema.Type, kind) && !(kind == "integer" && slices.Contains(schema.Type, "number")) {
+		v.report(node, path, "must be %s, not %s", describeTypes(schema.Type), article(kind))
+		return
+	}
+	if len(schema.Enum) > 0 && !enumContains(schema.Enum, node) {
+		v.report(node, path, "must be one of %s", describeEnum(schema.Enum))
+		return
+	}
+
+	switch kind {
+	case "object":
+		v.validateObject(schema, node, path)
+	case "array":
+		v.validateArray(schema, node, path)
+	case "string":
+		if schema.MinLength != nil && len([]rune(node.Value)) < *schema.MinLength {
+			v.report(node, path, "must be at least %d characters long", *schema.MinLength)
+		}
+		if schema.pattern != nil && !schema.pattern.MatchString(node.Value) {
+			v.report(node, path, "'%s' does not match %s", node.Value, schema.Pattern)
+		}
+	case "integer", "number":
+		number, _ := strconv.ParseFloat(strings.ReplaceAll(node.Value, "_", ""), 64)
+		if schema.Minimum != nil && number < *schema.Minimum {
+			v.report(node, path, "must be at least %v", *schema.Minimum)
+		}
+		if schema.Maximum != nil && number > *schema.Maximum {
+			v.report(node, path, "must be at most %v", *schema.Maximum)
+		}
+	}
+}
+
+// validateObject checks the properties of a mapping
+func (v *schemaValidator) validateObject(schema *jsonSchema, node *yaml.Node, path string) {
+	present := make(map[string]bool, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		propertyPath := joinPath(path, key.Value)
+		if present[key.Value] {
+			v.report(key, propertyPath, "is declared twice")
+			continue
+		}
+		present[key.Value] = true
+
+		if schema.PropertyNames != nil {
+			v.validate(schema.PropertyNames, key, propertyPath)
+		}
+		if property, ok := schema.Properties[key.Value]; ok {
+			v.validate(property, value, propertyPath)
+			continue
+		}
+		switch {
+		case schema.AdditionalProperties == nil:
+		case schema.AdditionalProperties.forbidden:
+			v.report(key, propertyPath, "unknown property (expected %s)", describeProperties(schema.Properties))
+		case schema.AdditionalProperties.schema != nil:
+			v.validate(schema.AdditionalProperties.schema, value, propertyPath)
+		}
+	}
+
+	for _, name := range schema.Required {
+		if !present[name] {
+			v.report(node, path, "missing required property '%s'", name)
+		}
+	}
+}
+
+// validateArray checks the items of a sequence
+func (v *schemaValidator) validateArray(schema *jsonSchema, node *yaml.Node, path string) {
+	if schema.MinItems != nil && len(node.Content) < *schema.MinItems {
+		v.report(node, path, "must have at least %d item(s)", *schema.MinItems)
+	}
+
+	seen := make(map[string]bool, len(node.Content))
+	for i, item := range node.Content {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		if schema.Items != nil {
+			v.validate(schema.Items, item, itemPath)
+		}
+		if schema.UniqueItems && item.Kind == yaml.ScalarNode {
+			if seen[item.Value] {
+				v.report(item, itemPath, "'%s' is listed twice", item.Value)
+			}
+			seen[item.Value] = true
+		}
+	}
+}
+
+// nodeType returns the JSON Schema type of a YAML node
+func nodeType(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch node.ShortTag() {
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	case "!!bool":
+		return "boolean"
+	case "!!null":
+		return "null"
+	}
+	return "string"
+}
+
+// enumContains reports whether a scalar node holds one of the values
+func enumContains(values []any, node *yaml.Node) bool {
+	if node.Kind != yaml.ScalarNode {
+		return false
+	}
+	for _, value := range values {
+		switch value := value.(type) {
+		case string:
+			if nodeType(node) == "string" && node.Value == value {
+				return true
+			}
+		case bool:
+			if parsed, err := strconv.ParseBool(node.Value); err == nil && nodeType(node) == "boolean" && parsed == value {
+				return true
+			}
+		case float64:
+			if parsed, err := strconv.ParseFloat(node.Value, 64); err == nil && parsed == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// joinPath appends a property to a document path
+func joinPath(path, property string) string {
+	if path == "" {
+		return property
+	}
+	return path + "." + property
+}
+
+// describeTypes lists type names for messages ("a string or an integer")
+func describeTypes(types []string) string {
+	described := make([]string, len(types))
+	for i, name := range types {
+		described[i] = article(name)
+	}
+	return strings.Join(described, " or ")
+}
+
+// article prefixes a type name with its indefinite article
+func article(name string) string {
+	switch name {
+	case "object":
+		return "a mapping"
+	case "array":
+		return "a list"
+	case "integer":
+		return "an integer"
+	case "null":
+		return "empty"
+	}
+	return "a " + name
+}
+
+// describeEnum lists the allowed values for messages
+func describeEnum(values []any) string {
+	described := make([]string, len(values))
+	for i, value := range values {
+		described[i] = fmt.Sprint(value)
+	}
+	return strings.Join(described, ", ")
+}
+
+// describeProperties lists the known properties of an object for messages
+func describeProperties(properties map[string]*jsonSchema) string {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,342 @@
+package common
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Template source kinds
+const (
+	sourceDir     = "dir"
+	sourceGit     = "git"
+	sourceArchive = "archive"
+)
+
+// templateSource is a parsed template source. Every source resolves to a
+// template root holding one directory per generator, like templates/.
+type templateSource struct {
+	kind   string
+	url    string // directory path, git URL or archive location
+	subdir string // template root inside the repository or archive
+	ref    string // git branch, tag or commit
+}
+
+// parseTemplateSource parses a template source:
+//
+//	./templates                              local directory
+//	git+https://host/org/repo.git//path@v1.2 git repository, optional subdirectory and ref
+//	git+file:///srv/templates.git            local git repository
+//	file:///tmp/templates.tar.gz             tar or tar.gz archive (also https:// or a path)
+func parseTemplateSource(source string) (templateSource, error) {
+	src := templateSource{kind: sourceDir, url: source}
+
+	switch {
+	case strings.HasPrefix(source, "git+"):
+		src.kind = sourceGit
+		src.url = strings.TrimPrefix(source, "git+")
+		if at := strings.LastIndex(src.url, "@"); at >= 0 && !strings.Contains(src.url[at+1:], "/") {
+			src.url, src.ref = src.url[:at], src.url[at+1:]
+		}
+		// Sources are also read from lock files of cloned projects, so
+		// nothing may reach git as an option
+		if strings.HasPrefix(src.url, "-") || strings.HasPrefix(src.ref, "-") {
+			return src, fmt.Errorf("invalid template source '%s': the repository and ref cannot start with '-'", source)
+		}
+	case isArchive(source):
+		src.kind = sourceArchive
+	default:
+		return src, nil
+	}
+
+	// A "//" after the scheme separates the template root inside the source
+	schemeEnd := strings.Index(src.url, "://")
+	if schemeEnd < 0 && src.kind == sourceGit {
+		return src, fmt.Errorf("invalid template source '%s': expected git+<scheme>://...", source)
+	}
+	start := 0
+	if schemeEnd >= 0 {
+		start = schemeEnd + 3
+	}
+	if i := strings.Index(src.url[start:], "//"); i >= 0 {
+		src.subdir = strings.Trim(src.url[start+i+2:], "/")
+		src.url = src.url[:start+i]
+	}
+	if src.subdir != "" && !filepath.IsLocal(filepath.FromSlash(src.subdir)) {
+		return src, fmt.Errorf("invalid template source '%s': subdirectory '%s' leaves the source", source, src.subdir)
+	}
+	return src, nil
+}
+
+// isArchive reports whether a source names a tar archive
+func isArchive(source string) bool {
+	name := source
+	if i := strings.Index(name, "://"); i >= 0 {
+		name = name[i+3:]
+	}
+	if i := strings.Index(name, "//"); i >= 0 {
+		name = name[:i]
+	}
+	return strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz") || strings.HasSuffix(name, ".tar")
+}
+
+// AbsTemplateSource returns a local directory or archive source with an
+// absolute path, so it resolves the same from any directory once recorded
+// in a lock file. Git repositories and URLs are returned unchanged.
+func AbsTemplateSource(source string) (string, error) {
+	if !isLocalSource(source) {
+		return source, nil
+	}
+	location, subdir, found := source, "", false
+	if isArchive(source) {
+		location, subdir, found = strings.Cut(source, "//")
+	}
+	abs, err := filepath.Abs(location)
+	if err != nil {
+		return "", err
+	}
+	if found {
+		abs += "//" + subdir
+	}
+	return abs, nil
+}
+
+// FetchTemplateSource resolves a template source to a local template root.
+// Git repositories and remote archives are fetched into the user cache
+// directory; a git source pinned to a ref is reused from the cache.
+func FetchTemplateSource(source string) (string, error) {
+	src, err := parseTemplateSource(source)
+	if err != nil {
+		return "", err
+	}
+
+	root := src.url
+	switch src.kind {
+	case sourceGit:
+		root, err = fetchGit(src)
+	case sourceArchive:
+		root, err = fetchArchive(src)
+		if err == nil && src.subdir == "" {
+			root, err = archiveRoot(root)
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch templates from %s: %w", source, err)
+	}
+
+	root = filepath.Join(root, filepath.FromSlash(src.subdir))
+	info, err := os.Stat(root)
+	if err != nil {
+		return "", fmt.Errorf("template directory %s: %w", root, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("template directory %s is not a directory", root)
+	}
+	return root, nil
+}
+
+// templateCache returns the cache directory for a fetched source, and
+// whether it already exists
+func templateCache(kind, key string) (string, bool, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", false, err
+	}
+	sum := sha256.Sum256([]byte(key))
+	dir := filepath.Join(cache, "ccin", "templates", kind+"-"+hex.EncodeToString(sum[:8]))
+	_, err = os.Stat(dir)
+	return dir, err == nil, nil
+}
+
+// populateCache fills a cache directory through a temporary sibling so an
+// interrupted fetch never leaves a half-written cache behind
+func populateCache(dir string, fill func(tmp string) error) error {
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), ".fetch-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := fill(tmp); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dir)
+}
+
+// fetchGit clones a git source at its ref into the cache. Unpinned sources
+// are cloned again on every use so they follow the default branch.
+func fetchGit(src templateSource) (string, error) {
+	dir, cached, err := templateCache(sourceGit, src.url+"@"+src.ref)
+	if err != nil || (cached && src.ref != "") {
+		return dir, err
+	}
+
+	err = populateCache(dir, func(tmp string) error {
+		if err := runGit("", "clone", "--quiet", "--", src.url, tmp); err != nil {
+			return err
+		}
+		if src.ref != "" {
+			if err := runGit(tmp, "checkout", "--quiet", src.ref); err != nil {
+				return err
+			}
+		}
+		return os.RemoveAll(filepath.Join(tmp, ".git"))
+	})
+	return dir, err
+}
+
+// runGit runs a git command, returning its output as the error on failure
+func runGit(dir string, args ...string) error {
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	output, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// fetchArchive extracts a tar archive into the cache. Local archives are
+// extracted on every use; downloaded archives are reused from the cache.
+func fetchArchive(src templateSource) (string, error) {
+	location := src.url
+	remote := strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+	if strings.HasPrefix(location, "file://") {
+		parsed, err := url.Parse(location)
+		if err != nil {
+			return "", err
+		}
+		location = parsed.Path
+	}
+	if !remote {
+		absolute, err := filepath.Abs(location)
+		if err != nil {
+			return "", err
+		}
+		location = absolute
+	}
+
+	dir, cached, err := templateCache(sourceArchive, location)
+	if err != nil {
+		return "", err
+	}
+	if !cached || !remote {
+		err = populateCache(dir, func(tmp string) error {
+			archive, err := openArchive(location, remote)
+			if err != nil {
+				return err
+			}
+			defer archive.Close()
+			return extractArchive(archive, location, tmp)
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return dir, nil
+}
+
+// openArchive opens a local or downloaded archive
+func openArchive(location string, remote bool) (io.ReadCloser, error) {
+	if !remote {
+		return os.Open(location)
+	}
+
+	response, err := http.Get(location)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		response.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", location, response.Status)
+	}
+	return response.Body, nil
+}
+
+// extractArchive extracts regular files and directories of a tar or tar.gz
+// archive into dest, refusing entries that would land outside it
+func extractArchive(archive io.Reader, name, dest string) error {
+	if !strings.HasSuffix(name, ".tar") {
+		gz, err := gzip.NewReader(archive)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		archive = gz
+	}
+
+	reader := tar.NewReader(archive)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		if name == "." {
+			continue
+		}
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			return fmt.Errorf("archive entry %s leaves the archive", header.Name)
+		}
+		target := filepath.Join(dest, filepath.FromSlash(name))
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(file, reader)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// archiveRoot descends into the single top-level directory archives often
+// wrap their content in (e.g. templates-1.2.0/), unless that directory is
+// the template set of a generator. It is only used when the source names no
+// subdirectory.
+func archiveRoot(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() && !Registry.Has(entries[0].Name()) {
+		return filepath.Join(dir, entries[0].Name()), nil
+	}
+	return dir, nil
+}
@@ -0,0 +1,276 @@
+package common
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseTemplateSource(t *testing.T) {
+	tests := []struct {
+		source  string
+		want    templateSource
+		wantErr string
+	}{
+		{
+			source: "./templates",
+			want:   templateSource{kind: sourceDir, url: "./templates"},
+		},
+		{
+			source: "/srv/ccin/templates",
+			want:   templateSource{kind: sourceDir, url: "/srv/ccin/templates"},
+		},
+		{
+			source: "git+https://github.com/acme/templates.git",
+			want:   templateSource{kind: sourceGit, url: "https://github.com/acme/templates.git"},
+		},
+		{
+			source: "git+https://github.com/acme/templates.git@v1.2.0",
+			want:   templateSource{kind: sourceGit, url: "https://github.com/acme/templates.git", ref: "v1.2.0"},
+		},
+		{
+			source: "git+https://github.com/acme/platform.git//ccin/templates@main",
+			want:   templateSource{kind: sourceGit, url: "https://github.com/acme/platform.git", subdir: "ccin/templates", ref: "main"},
+		},
+		{
+			source: "git+ssh://git@github.com/acme/templates.git",
+			want:   templateSource{kind: sourceGit, url: "ssh://git@github.com/acme/templates.git"},
+		},
+		{
+			source: "git+file:///srv/templates.git@3f2c1a9",
+			want:   templateSource{kind: sourceGit, url: "file:///srv/templates.git", ref: "3f2c1a9"},
+		},
+		{
+			source: "file:///tmp/templates.tar.gz",
+			want:   templateSource{kind: sourceArchive, url: "file:///tmp/templates.tar.gz"},
+		},
+		{
+			source: "https://example.com/templates-1.2.0.tgz//templates",
+			want:   templateSource{kind: sourceArchive, url: "https://example.com/templates-1.2.0.tgz", subdir: "templates"},
+		},
+		{
+			source: "dist/templates.tar//templates/",
+			want:   templateSource{kind: sourceArchive, url: "dist/templates.tar", subdir: "templates"},
+		},
+		{
+			source:  "git+github.com/acme/templates.git",
+			wantErr: "expected git+<scheme>://",
+		},
+		{
+			source:  "git+--upload-pack=touch /tmp/pwned://x",
+			wantErr: "cannot start with '-'",
+		},
+		{
+			source:  "git+https://github.com/acme/templates.git@--force",
+			wantErr: "cannot start with '-'",
+		},
+		{
+			source:  "git+https://github.com/acme/templates.git//../secrets",
+			wantErr: "leaves the source",
+		},
+		{
+			source:  "https://example.com/templates.tar.gz//../etc",
+			wantErr: "leaves the source",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.source, func(t *testing.T) {
+			got, err := parseTemplateSource(test.source)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("parseTemplateSource(%q) error = %v, want one containing %q", test.source, err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTemplateSource(%q): %v", test.source, err)
+			}
+			if got != test.want {
+				t.Errorf("parseTemplateSource(%q) = %+v, want %+v", test.source, got, test.want)
+			}
+		})
+	}
+}
+
+func TestFetchTemplateSourceGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	useTempCache(t)
+	for _, name := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(name, "ccin")
+	}
+	for _, name := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(name, "ccin@example.com")
+	}
+
+	// A repository with the templates under templates/, tagged v1 before
+	// the README changes
+	work := t.TempDir()
+	git(t, work, "init", "--quiet", "--initial-branch", "main")
+	writeFiles(t, work, map[string]string{"templates/go-gin/README.md.tpl": "# v1\n"})
+	git(t, work, "add", "--all")
+	git(t, work, "commit", "--quiet", "-m", "v1")
+	git(t, work, "tag", "v1")
+	writeFiles(t, work, map[string]string{"templates/go-gin/README.md.tpl": "# v2\n"})
+	git(t, work, "commit", "--quiet", "--all", "-m", "v2")
+
+	bare := filepath.Join(t.TempDir(), "templates.git")
+	git(t, "", "clone", "--quiet", "--bare", work, bare)
+	repository := "git+file://" + filepath.ToSlash(bare)
+
+	tests := []struct {
+		source string
+		want   string // content of go-gin/README.md.tpl in the template root
+	}{
+		{repository + "//templates", "# v2\n"},
+		{repository + "//templates@v1", "# v1\n"},
+		{repository + "//templates@v1", "# v1\n"}, // reused from the cache
+		{repository + "//templates@main", "# v2\n"},
+	}
+
+	for _, test := range tests {
+		root, err := FetchTemplateSource(test.source)
+		if err != nil {
+			t.Fatalf("FetchTemplateSource(%q): %v", test.source, err)
+		}
+		assertFile(t, filepath.Join(root, "go-gin", "README.md.tpl"), test.want)
+		if _, err := os.Stat(filepath.Join(root, "..", ".git")); !os.IsNotExist(err) {
+			t.Errorf("FetchTemplateSource(%q) kept the .git directory", test.source)
+		}
+	}
+
+	if _, err := FetchTemplateSource(repository + "//templates@v9"); err == nil {
+		t.Error("FetchTemplateSource with an unknown ref succeeded")
+	}
+	if _, err := FetchTemplateSource(repository + "//missing"); err == nil {
+		t.Error("FetchTemplateSource with a missing subdirectory succeeded")
+	}
+}
+
+func TestFetchTemplateSourceArchive(t *testing.T) {
+	useTempCache(t)
+	dir := t.TempDir()
+
+	// Release archives usually wrap the template root in one directory
+	wrapped := filepath.Join(dir, "templates-1.2.0.tar.gz")
+	writeArchive(t, wrapped, map[string]string{
+		"templates-1.2.0/go-gin/README.md.tpl": "# wrapped\n",
+		"templates-1.2.0/nestjs/README.md.tpl": "# nestjs\n",
+	})
+	flat := filepath.Join(dir, "templates.tar")
+	writeArchive(t, flat, map[string]string{
+		"./ccin/go-gin/README.md.tpl": "# flat\n",
+	})
+
+	tests := []struct {
+		source string
+		want   string // content of go-gin/README.md.tpl in the template root
+	}{
+		{wrapped, "# wrapped\n"},
+		{"file://" + filepath.ToSlash(wrapped), "# wrapped\n"},
+		{wrapped + "//templates-1.2.0", "# wrapped\n"},
+		{flat + "//ccin", "# flat\n"},
+	}
+	for _, test := range tests {
+		root, err := FetchTemplateSource(test.source)
+		if err != nil {
+			t.Fatalf("FetchTemplateSource(%q): %v", test.source, err)
+		}
+		assertFile(t, filepath.Join(root, "go-gin", "README.md.tpl"), test.want)
+	}
+
+	// A local archive is extracted again on every use
+	writeArchive(t, wrapped, map[string]string{"templates-1.2.0/go-gin/README.md.tpl": "# rebuilt\n"})
+	root, err := FetchTemplateSource(wrapped)
+	if err != nil {
+		t.Fatalf("FetchTemplateSource(%q): %v", wrapped, err)
+	}
+	assertFile(t, filepath.Join(root, "go-gin", "README.md.tpl"), "# rebuilt\n")
+
+	escaping := filepath.Join(dir, "escaping.tar.gz")
+	writeArchive(t, escaping, map[string]string{"../go-gin/README.md.tpl": "# outside\n"})
+	if _, err := FetchTemplateSource(escaping); err == nil || !strings.Contains(err.Error(), "leaves the archive") {
+		t.Errorf("FetchTemplateSource of an archive with an entry outside it: error = %v", err)
+	}
+}
+
+// useTempCache points the user cache directory, where fetched sources are
+// kept, to a directory removed after the test
+func useTempCache(t *testing.T) {
+	t.Helper()
+	cache := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cache)
+	t.Setenv("HOME", cache)
+	t.Setenv("LocalAppData", cache)
+}
+
+// git runs a git command in dir, failing the test on error
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	if err := runGit(dir, args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeFiles writes files by slash-separated path under dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := writeFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// writeArchive writes files into a tar archive, gzipped unless the name
+// ends in .tar
+func writeArchive(t *testing.T, name string, files map[string]string) {
+	t.Helper()
+	file, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var gz *gzip.Writer
+	archive := tar.NewWriter(file)
+	if !strings.HasSuffix(name, ".tar") {
+		gz = gzip.NewWriter(file)
+		archive = tar.NewWriter(gz)
+	}
+	for path, content := range files {
+		header := &tar.Header{Name: path, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := archive.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := archive.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// assertFile checks the content of a file
+func assertFile(t *testing.T, name, want string) {
+	t.Helper()
+	content, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != want {
+		t.Errorf("%s = %q, want %q", name, content, want)
+	}
+}
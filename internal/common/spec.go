@@ -0,0 +1,228 @@
+package common
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/chrisloarryn/ccin/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// Transports a spec may declare
+const (
+	TransportHTTP = "http"
+	TransportGRPC = "grpc"
+)
+
+// serviceSchema is the published JSON Schema of spec files, parsed once
+var serviceSchema = sync.OnceValues(func() (*jsonSchema, error) {
+	return parseSchema(schema.Service)
+})
+
+// Spec is a whole service declared in one YAML file (`ccin generate -f`),
+// so it can be checked into a repository and regenerated reproducibly. Its
+// format is published as schema/service.schema.json.
+type Spec struct {
+	Generator     string            `yaml:"generator"`
+	Name          string            `yaml:"name"`
+	Module        string            `yaml:"module"` // import path of Go services, ending in Name
+	Database      string            `yaml:"database"`
+	Port          string            `yaml:"port"`
+	Transports    []string          `yaml:"transports"`
+	Auth          SpecAuth          `yaml:"auth"`
+	Observability SpecObservability `yaml:"observability"`
+	Templates     string            `yaml:"templates"` // template source, relative to the spec file
+	Author        string            `yaml:"author"`
+	License       string            `yaml:"license"`
+	Domains       []Domain          `yaml:"domains"`
+	Vars          map[string]any    `yaml:"vars"`
+}
+
+// SpecAuth is the authentication section of a spec
+type SpecAuth struct {
+	Type string `yaml:"type"` // only none: no generator renders authentication middleware yet
+}
+
+// SpecObservability is the observability section of a spec
+type SpecObservability struct {
+	GCPProject string `yaml:"gcp_project"`
+}
+
+// SpecError lists every problem found in a spec file
+type SpecError struct {
+	File     string
+	Problems []SchemaProblem
+}
+
+// Error returns the problems one per line as file:line:column: path: message
+func (e *SpecError) Error() string {
+	lines := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		lines[i] = e.File + ":" + problem.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// LoadSpec reads a spec file and checks it against the published schema and
+// the chosen generator. All problems are returned together as a *SpecError.
+func LoadSpec(file string) (*Spec, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if len(bytes.TrimSpace(content)) > 0 {
+		if err := yaml.Unmarshal(content, &doc); err != nil {
+			return nil, fmt.Errorf("invalid spec file %s: %w", file, err)
+		}
+	}
+	validator, err := serviceSchema()
+	if err != nil {
+		return nil, err
+	}
+	if problems := validator.Validate(&doc); len(problems) > 0 {
+		return nil, &SpecError{File: file, Problems: problems}
+	}
+
+	var spec Spec
+	if err := doc.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("invalid spec file %s: %w", file, err)
+	}
+	if problems := spec.check(doc.Content[0]); len(problems) > 0 {
+		return nil, &SpecError{File: file, Problems: problems}
+	}
+
+	if isLocalSource(spec.Templates) && !filepath.IsAbs(spec.Templates) {
+		spec.Templates = filepath.Join(filepath.Dir(file), spec.Templates)
+	}
+	return &spec, nil
+}
+
+// check finds what the schema cannot express: the generator's capabilities,
+// the module path, and duplicate or invalid domains and fields. Fields are
+// normalized in place, as in entity files.
+func (s *Spec) check(root *yaml.Node) []SchemaProblem {
+	var problems []SchemaProblem
+	report := func(node *yaml.Node, path, format string, args ...any) {
+		problems = append(problems, SchemaProblem{Line: node.Line, Column: node.Column, Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	generator, err := Registry.Get(s.Generator)
+	if err != nil {
+		report(nodeAt(root, "generator"), "generator", "unknown framework (available: %s)", strings.Join(Registry.List(), ", "))
+		return problems
+	}
+	metadata := generator.GetMetadata()
+
+	if s.Database != "" && !metadata.SupportsDatabase(s.Database) {
+		report(nodeAt(root, "database"), "database", "%s does not support '%s' (supported: %s)", metadata.DisplayName, s.Database, strings.Join(metadata.SupportedDatabases, ", "))
+	}
+	if i := slices.Index(s.Transports, TransportGRPC); i >= 0 && !metadata.SupportsGRPC {
+		report(nodeAt(root, "transports", i), fmt.Sprintf("transports[%d]", i), "%s does not support gRPC", metadata.DisplayName)
+	}
+	if s.Module != "" && path.Base(s.Module) != s.Name {
+		report(nodeAt(root, "module"), "module", "'%s' must end in the project name '%s'", s.Module, s.Name)
+	}
+
+	seen := make(map[string]bool, len(s.Domains))
+	for i := range s.Domains {
+		domain := &s.Domains[i]
+		domainPath := fmt.Sprintf("domains[%d]", i)
+		if key := strings.ToLower(domain.Name); seen[key] {
+			report(nodeAt(root, "domains", i, "name"), domainPath+".name", "duplicate domain '%s'", domain.Name)
+		} else {
+			seen[key] = true
+		}
+
+		fields := make(map[string]bool, len(domain.Fields))
+		for j, field := range domain.Fields {
+			fieldPath := fmt.Sprintf("%s.fields[%d].name", domainPath, j)
+			normalized, err := field.normalize()
+			if err != nil {
+				report(nodeAt(root, "domains", i, "fields", j, "name"), fieldPath, "%v", err)
+				continue
+			}
+			if fields[normalized.Name] {
+				report(nodeAt(root, "domains", i, "fields", j, "name"), fieldPath, "duplicate field '%s'", normalized.Name)
+			}
+			fields[normalized.Name] = true
+			domain.Fields[j] = normalized
+		}
+	}
+	slices.SortStableFunc(problems, func(a, b SchemaProblem) int {
+		return cmp.Or(cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column))
+	})
+	return problems
+}
+
+// Config returns the generator configuration of the spec, writing the
+// project to a directory named after it
+func (s *Spec) Config() *GeneratorConfig {
+	config := &GeneratorConfig{
+		ProjectName:    s.Name,
+		Domains:        make([]Domain, len(s.Domains)),
+		GCPProject:     s.Observability.GCPProject,
+		OutputDir:      s.Name,
+		TemplateSource: s.Templates,
+		WithGRPC:       slices.Contains(s.Transports, TransportGRPC),
+		DatabaseType:   s.Database,
+		Port:           s.Port,
+		Author:         s.Author,
+		License:        s.License,
+	}
+	if strings.Contains(s.Module, "/") {
+		config.ModulePrefix = path.Dir(s.Module)
+	}
+	for i, domain := range s.Domains {
+		if len(domain.Fields) == 0 {
+			domain.Fields = DefaultFields()
+		}
+		config.Domains[i] = domain
+	}
+
+	if len(s.Vars) > 0 {
+		config.Vars = maps.Clone(s.Vars)
+	}
+	return config
+}
+
+// isLocalSource reports whether a template source is a local directory or
+// archive path rather than a git repository or URL
+func isLocalSource(source string) bool {
+	return source != "" && !strings.HasPrefix(source, "git+") && !strings.Contains(source, "://")
+}
+
+// nodeAt returns the node at a path of mapping keys and sequence indexes
+// below root, or the deepest node found on the way
+func nodeAt(root *yaml.Node, steps ...any) *yaml.Node {
+	node := root
+	for _, step := range steps {
+		var next *yaml.Node
+		switch step := step.(type) {
+		case string:
+			for i := 0; node.Kind == yaml.MappingNode && i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == step {
+					next = node.Content[i+1]
+				}
+			}
+		case int:
+			if node.Kind == yaml.SequenceNode && step < len(node.Content) {
+				next = node.Content[step]
+			}
+		}
+		if next == nil {
+			return node
+		}
+		node = next
+	}
+	return node
+}
@@ -0,0 +1,76 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// stagingPrefix starts the name of the hidden directory a project is
+// generated into before it is moved into place
+const stagingPrefix = ".ccin-staging-"
+
+// GenerateProject runs a generator atomically: the project is generated into
+// a staging directory next to config.OutputDir and moved into place only
+// when every file, including the lock, was written. When generation fails
+// the staging directory is removed and the output directory is left as it
+// was. Files that already exist in the output directory are resolved against
+// it by config.Conflicts as usual.
+func GenerateProject(generator Generator, config *GeneratorConfig) error {
+	parent := filepath.Dir(config.OutputDir)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return err
+	}
+	staging, err := os.MkdirTemp(parent, stagingPrefix+filepath.Base(config.OutputDir)+"-")
+	if err != nil {
+		return fmt.Errorf("failed to create a staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	staged := *config
+	staged.OutputDir = staging
+	staged.Conflicts.target = config.OutputDir
+	if err := generator.Generate(&staged); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(staging, 0755); err != nil {
+		return err
+	}
+	return moveIntoPlace(staging, config.OutputDir)
+}
+
+// moveIntoPlace moves a staged project to the output directory: in one
+// rename when the output directory does not exist or is empty, otherwise
+// file by file, each file replacing the one at its path
+func moveIntoPlace(staging, outputDir string) error {
+	entries, err := os.ReadDir(outputDir)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return os.Rename(staging, outputDir)
+	case err != nil:
+		return err
+	case len(entries) == 0:
+		if err := os.Remove(outputDir); err != nil {
+			return err
+		}
+		return os.Rename(staging, outputDir)
+	}
+
+	return filepath.WalkDir(staging, func(stagedPath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		relPath, err := filepath.Rel(staging, stagedPath)
+		if err != nil {
+			return err
+		}
+		outputPath := filepath.Join(outputDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+			return err
+		}
+		return os.Rename(stagedPath, outputPath)
+	})
+}
@@ -0,0 +1,149 @@
+package common
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DriftStatus describes how a project file compares to its templates
+type DriftStatus string
+
+// Drift statuses reported by Status
+const (
+	DriftPristine DriftStatus = "pristine" // matches what the templates render
+	DriftOutdated DriftStatus = "outdated" // untouched since generation, but the templates changed
+	DriftModified DriftStatus = "modified" // changed by the user
+	DriftMissing  DriftStatus = "missing"  // generated but deleted
+	DriftExtra    DriftStatus = "extra"    // in the project but not generated
+)
+
+// DriftStatuses lists the statuses in report order
+var DriftStatuses = []DriftStatus{DriftPristine, DriftOutdated, DriftModified, DriftMissing, DriftExtra}
+
+// DriftFile is the status of one project file
+type DriftFile struct {
+	Path     string      `json:"path"`
+	Status   DriftStatus `json:"status"`
+	Rendered []byte      `json:"-"` // template output, nil for extra files
+	Current  []byte      `json:"-"` // content on disk, nil for missing files
+}
+
+// Diff returns a unified diff from the rendered file to the file on disk
+func (f DriftFile) Diff() string {
+	from, to := "a/"+f.Path, "b/"+f.Path
+	if f.Rendered == nil {
+		from = "/dev/null"
+	}
+	if f.Current == nil {
+		to = "/dev/null"
+	}
+	return UnifiedDiff(from, to, f.Rendered, f.Current)
+}
+
+// DriftReport is the result of Status, with files in path order
+type DriftReport struct {
+	Generator string      `json:"generator"`
+	Version   string      `json:"ccin_version"` // version that generated the project
+	Files     []DriftFile `json:"files"`
+}
+
+// Count returns the number of files with the given status
+func (r *DriftReport) Count(status DriftStatus) int {
+	count := 0
+	for _, file := range r.Files {
+		if file.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Status re-renders the project in dir in memory with the configuration
+// recorded in its lock file and reports, per file, whether it is pristine,
+// outdated, user-modified, missing or extra. Extra files are the files git
+// tracks or would track, or every file outside .git when dir is not a git
+// repository.
+func Status(dir, templateSource string) (*DriftReport, error) {
+	project, err := OpenProject(dir, templateSource)
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := project.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DriftReport{Generator: project.Generator.GetName(), Version: project.Lock.Version}
+	generated := map[string]bool{LockFile: true}
+	for _, file := range rendered {
+		path := filepath.ToSlash(file.Path)
+		generated[path] = true
+
+		drift := DriftFile{Path: path, Rendered: file.Content}
+		current, err := os.ReadFile(filepath.Join(dir, file.Path))
+		switch {
+		case os.IsNotExist(err):
+			drift.Status = DriftMissing
+		case err != nil:
+			return nil, err
+		case bytes.Equal(current, file.Content):
+			drift.Status = DriftPristine
+		case HashContent(current) == project.Lock.Files[path]:
+			drift.Status = DriftOutdated
+		default:
+			drift.Status = DriftModified
+		}
+		drift.Current = current
+		report.Files = append(report.Files, drift)
+	}
+
+	files, err := projectFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range files {
+		if !generated[path] {
+			current, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+			if err != nil {
+				continue
+			}
+			report.Files = append(report.Files, DriftFile{Path: path, Status: DriftExtra, Current: current})
+		}
+	}
+
+	sort.Slice(report.Files, func(i, j int) bool { return report.Files[i].Path < report.Files[j].Path })
+	return report, nil
+}
+
+// projectFiles lists the slash-separated paths of the files in dir, honoring
+// .gitignore when dir is inside a git repository
+func projectFiles(dir string) ([]string, error) {
+	if output, err := exec.Command("git", "-C", dir, "ls-files", "-z", "--cached", "--others", "--exclude-standard").Output(); err == nil {
+		return strings.FieldsFunc(string(output), func(r rune) bool { return r == 0 }), nil
+	}
+
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(relPath))
+		return nil
+	})
+	return files, err
+}
@@ -1,101 +1,476 @@
 package common
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 	"text/template"
 )
 
-// TemplateData represents the data passed to templates
+// DomainData holds the naming variants and fields of one domain
+type DomainData struct {
+	DomainName  string
+	DomainTitle string
+	DomainUpper string
+	DomainLower string
+	Fields      Fields
+	Operations  Operations // routes from the API contract; empty without one
+}
+
+// TemplateData represents the data passed to templates. Per-domain templates
+// see the domain being rendered through the embedded DomainData; shared
+// templates see the first domain there and range over Domains to register
+// every one of them.
 type TemplateData struct {
-	ProjectName   string
-	DomainName    string
-	DomainTitle   string
-	DomainUpper   string
-	DomainLower   string
-	GCPProject    string
-	WithGRPC      bool
-	Port          string
-	DatabaseType  string
+	ProjectName  string
+	GCPProject   string
+	WithGRPC     bool
+	Port         string
+	DatabaseType string
+	ModulePath   string // import path of the project: the module prefix (if any) and the project name
+	Author       string
+	License      string         // license identifier such as MIT; templates fall back to their own default
+	Contract     string         // file name of the API contract, empty when routes are the fixed CRUD ones
+	Proto        *ProtoContract // services of the contract when it is a .proto file, else nil
+	Vars         map[string]any // custom variables, completed from the template set's manifest
+	DomainData
+	Domains []DomainData
+}
+
+// ForDomain returns a copy of the data scoped to one domain
+func (d *TemplateData) ForDomain(domain DomainData) *TemplateData {
+	scoped := *d
+	scoped.DomainData = domain
+	return &scoped
 }
 
 // TemplateProcessor handles template processing
 type TemplateProcessor struct {
-	templateDir string
-	outputDir   string
+	templates fs.FS
+	outputDir string
+	conflicts ConflictHandler
+	required  []string
+	manifest  *Manifest
+
+	mu       sync.Mutex // guards partials and parsed for concurrent rendering
+	partials *template.Template
+	parsed   map[string]*template.Template // templates by path, each parsed once
 }
 
-// NewTemplateProcessor creates a new template processor
-func NewTemplateProcessor(templateDir, outputDir string) *TemplateProcessor {
+// ErrMissingArtifacts is returned when the templates do not produce a file
+// the generator advertises
+var ErrMissingArtifacts = errors.New("the templates do not produce every advertised file")
+
+// NewTemplateProcessor creates a new template processor reading templates
+// from the given file system (embedded or on disk)
+func NewTemplateProcessor(templates fs.FS, outputDir string) *TemplateProcessor {
 	return &TemplateProcessor{
-		templateDir: templateDir,
-		outputDir:   outputDir,
+		templates: templates,
+		outputDir: outputDir,
+	}
+}
+
+// SetConflictHandler sets how files that already exist in the output
+// directory are handled (by default a non-empty directory is refused)
+func (tp *TemplateProcessor) SetConflictHandler(conflicts ConflictHandler) {
+	tp.conflicts = conflicts
+}
+
+// SetRequiredFiles sets output paths (slash-separated) that rendering must
+// produce, such as the artifacts a generator advertises; rendering fails
+// with ErrMissingArtifacts when one of them is missing
+func (tp *TemplateProcessor) SetRequiredFiles(paths []string) {
+	tp.required = paths
+}
+
+// Manifest returns the manifest of the template set, read on first use
+func (tp *TemplateProcessor) Manifest() (*Manifest, error) {
+	if tp.manifest == nil {
+		manifest, err := LoadManifest(tp.templates)
+		if err != nil {
+			return nil, err
+		}
+		tp.manifest = manifest
 	}
+	return tp.manifest, nil
 }
 
-// ProcessTemplate processes a single template file
+// prepare loads the manifest and partials of the template set and completes
+// data with the manifest's variables
+func (tp *TemplateProcessor) prepare(data *TemplateData) (*TemplateData, error) {
+	manifest, err := tp.Manifest()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tp.Partials(); err != nil {
+		return nil, err
+	}
+	return manifest.Resolve(data)
+}
+
+// includes reports whether the manifest renders a template for data
+func (tp *TemplateProcessor) includes(templatePath string, data *TemplateData) (bool, error) {
+	manifest, err := tp.Manifest()
+	if err != nil {
+		return false, err
+	}
+	return manifest.Includes(templatePath, data)
+}
+
+// ProcessTemplate processes a single template file. Templates the manifest
+// excludes for data, or that render to nothing but whitespace, are skipped.
+// An existing output file is resolved by the processor's conflict handler.
 func (tp *TemplateProcessor) ProcessTemplate(templatePath, outputPath string, data *TemplateData) error {
-	// Create output directory if it doesn't exist
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+	data, err := tp.prepare(data)
+	if err != nil {
+		return err
+	}
+	included, err := tp.includes(templatePath, data)
+	if err != nil || !included {
+		return err
+	}
+	content, err := tp.renderTemplate(templatePath, data)
+	if err != nil || content == nil {
+		return err
+	}
+
+	relPath, err := filepath.Rel(tp.outputDir, outputPath)
+	if err != nil {
+		relPath = outputPath
+	}
+	return tp.writeResolved(outputPath, relPath, content)
+}
+
+// writeResolved writes a generated file unless it exists and the conflict
+// handler decides to keep it
+func (tp *TemplateProcessor) writeResolved(outputPath, relPath string, content []byte) error {
+	content, err := tp.conflicts.resolve(outputPath, relPath, content)
+	if err != nil || content == nil {
 		return err
 	}
+	return writeFile(outputPath, content)
+}
+
+// Partials returns the partials of the template set, parsed on first use
+func (tp *TemplateProcessor) Partials() (*template.Template, error) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	if tp.partials != nil {
+		return tp.partials, nil
+	}
+
+	files, err := partialFiles(tp.templates)
+	if err != nil {
+		return nil, err
+	}
+	partials := template.New(PartialsDir).Funcs(TemplateFuncs)
+	for _, partialPath := range files {
+		content, err := fs.ReadFile(tp.templates, partialPath)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := partials.New(partialPath).Parse(string(content)); err != nil {
+			return nil, err
+		}
+	}
+	tp.partials = partials
+	return partials, nil
+}
+
+// parseTemplate reads and parses a template, including its {{define}}
+// regions, on top of the template set's partials. A template is parsed once
+// and shared by every rendering; executing it concurrently is safe.
+func (tp *TemplateProcessor) parseTemplate(templatePath string) (*template.Template, error) {
+	tp.mu.Lock()
+	tmpl, ok := tp.parsed[templatePath]
+	tp.mu.Unlock()
+	if ok {
+		return tmpl, nil
+	}
+
+	partials, err := tp.Partials()
+	if err != nil {
+		return nil, err
+	}
 
 	// Read template file
-	tmpl, err := template.ParseFiles(templatePath)
+	content, err := fs.ReadFile(tp.templates, templatePath)
 	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err = partials.Clone()
+	if err != nil {
+		return nil, err
+	}
+	if tmpl, err = tmpl.New(path.Base(templatePath)).Parse(string(content)); err != nil {
+		return nil, err
+	}
+
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	if tp.parsed == nil {
+		tp.parsed = make(map[string]*template.Template)
+	}
+	tp.parsed[templatePath] = tmpl
+	return tmpl, nil
+}
+
+// renderTemplate executes a template and returns its output, or nil when the
+// output is only whitespace
+func (tp *TemplateProcessor) renderTemplate(templatePath string, data *TemplateData) ([]byte, error) {
+	tmpl, err := tp.parseTemplate(templatePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Execute template
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(buf.Bytes())) == 0 {
+		return nil, nil
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeFile writes a generated file, creating its directory if needed
+func writeFile(outputPath string, content []byte) error {
+	// Create output directory if it doesn't exist
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
 		return err
 	}
 
-	// Create output file
-	file, err := os.Create(outputPath)
+	// Write output file
+	return os.WriteFile(outputPath, content, 0644)
+}
+
+// ProcessDirectory processes all templates in the template set recursively.
+// Per-domain templates are rendered once for every domain in data.Domains.
+// Every template is rendered before the first file is written, and files
+// that already exist are resolved by the processor's conflict handler. It
+// returns the files as rendered, whatever the conflict handler kept on disk.
+func (tp *TemplateProcessor) ProcessDirectory(data *TemplateData) ([]RenderedFile, error) {
+	if err := tp.conflicts.checkOutputDir(tp.outputDir); err != nil {
+		return nil, err
+	}
+
+	files, err := tp.RenderDirectory(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		if err := tp.writeResolved(filepath.Join(tp.outputDir, file.Path), file.Path, file.Content); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// RenderedFile is a generated file held in memory
+type RenderedFile struct {
+	Path    string // path relative to the output directory
+	Content []byte
+}
+
+// RenderDirectory renders all templates in the template set in memory, in
+// template order, without touching the output directory. Templates excluded
+// by the manifest's file rules and templates that render to whitespace only
+// are left out. Every template is parsed once and the files are rendered
+// concurrently; the result does not depend on scheduling. When templates
+// fail, the error joins the failure of every file, in template order.
+func (tp *TemplateProcessor) RenderDirectory(data *TemplateData) ([]RenderedFile, error) {
+	data, err := tp.prepare(data)
 	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := tp.renderJobs(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse each template once, then render every file
+	var templatePaths []string
+	for i, job := range jobs {
+		if i == 0 || jobs[i-1].templatePath != job.templatePath {
+			templatePaths = append(templatePaths, job.templatePath)
+		}
+	}
+	parseErrs := inParallel(len(templatePaths), func(i int) error {
+		_, err := tp.parseTemplate(templatePaths[i])
 		return err
+	})
+	failed := make(map[string]error)
+	for i, err := range parseErrs {
+		if err != nil {
+			failed[templatePaths[i]] = fmt.Errorf("%s: %w", templatePaths[i], err)
+		}
 	}
-	defer file.Close()
 
-	// Execute template
-	return tmpl.Execute(file, data)
+	contents := make([][]byte, len(jobs))
+	renderErrs := inParallel(len(jobs), func(i int) error {
+		job := jobs[i]
+		if failed[job.templatePath] != nil {
+			return nil
+		}
+		content, err := tp.renderTemplate(job.templatePath, job.data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", job.name(), err)
+		}
+		contents[i] = content
+		return nil
+	})
+
+	var errs []error
+	for i, job := range jobs {
+		if i == 0 || jobs[i-1].templatePath != job.templatePath {
+			errs = append(errs, failed[job.templatePath])
+		}
+		errs = append(errs, renderErrs[i])
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	var files []RenderedFile
+	for i, job := range jobs {
+		if contents[i] != nil {
+			files = append(files, RenderedFile{Path: tp.relativePath(job.templatePath, job.data), Content: contents[i]})
+		}
+	}
+
+	if missing := missingFiles(files, tp.required); len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrMissingArtifacts, strings.Join(missing, ", "))
+	}
+	return files, nil
+}
+
+// renderJob is one file to render: a template and the data it is rendered
+// with, scoped to a domain for per-domain templates
+type renderJob struct {
+	templatePath string
+	data         *TemplateData
+	perDomain    bool
 }
 
-// ProcessDirectory processes all templates in a directory recursively
-func (tp *TemplateProcessor) ProcessDirectory(data *TemplateData) error {
-	return filepath.Walk(tp.templateDir, func(path string, info os.FileInfo, err error) error {
+// name identifies the file in errors
+func (j renderJob) name() string {
+	if j.perDomain {
+		return fmt.Sprintf("%s (domain %s)", j.templatePath, j.data.DomainName)
+	}
+	return j.templatePath
+}
+
+// renderJobs lists the files the template set renders for data, in template
+// order, leaving out the templates the manifest excludes
+func (tp *TemplateProcessor) renderJobs(data *TemplateData) ([]renderJob, error) {
+	var jobs []renderJob
+	add := func(job renderJob) error {
+		included, err := tp.includes(job.templatePath, job.data)
+		if err == nil && included {
+			jobs = append(jobs, job)
+		}
+		return err
+	}
+
+	err := fs.WalkDir(tp.templates, ".", func(templatePath string, entry fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
 		// Skip directories
-		if info.IsDir() {
+		if entry.IsDir() {
 			return nil
 		}
 
-		// Calculate relative path
-		relPath, err := filepath.Rel(tp.templateDir, path)
-		if err != nil {
-			return err
+		if !isPerDomain(strings.TrimSuffix(templatePath, ".tpl")) {
+			return add(renderJob{templatePath: templatePath, data: data})
+		}
+		for _, domain := range data.Domains {
+			if err := add(renderJob{templatePath: templatePath, data: data.ForDomain(domain), perDomain: true}); err != nil {
+				return err
+			}
 		}
+		return nil
+	})
+	return jobs, err
+}
+
+// inParallel calls fn for the indexes 0 to n-1 on a bounded pool of
+// goroutines and returns the errors by index
+func inParallel(n int, fn func(i int) error) []error {
+	errs := make([]error, n)
+	indexes := make(chan int)
+
+	var workers sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), n) {
+		workers.Go(func() {
+			for i := range indexes {
+				errs[i] = fn(i)
+			}
+		})
+	}
+	for i := range n {
+		indexes <- i
+	}
+	close(indexes)
+	workers.Wait()
+	return errs
+}
 
-		// Remove .tpl extension from output path
-		outputPath := filepath.Join(tp.outputDir, relPath)
-		if filepath.Ext(outputPath) == ".tpl" {
-			outputPath = outputPath[:len(outputPath)-4]
+// missingFiles returns the required paths that are not among the files
+func missingFiles(files []RenderedFile, required []string) []string {
+	rendered := make(map[string]bool, len(files))
+	for _, file := range files {
+		rendered[filepath.ToSlash(file.Path)] = true
+	}
+
+	var missing []string
+	for _, path := range required {
+		if !rendered[path] {
+			missing = append(missing, path)
 		}
+	}
+	return missing
+}
 
-		// Replace template variables in path
-		outputPath = tp.replacePlaceholders(outputPath, data)
+// isPerDomain reports whether a template path belongs to a single domain,
+// either through a domain placeholder or a "domain" directory
+func isPerDomain(path string) bool {
+	return strings.Contains(path, "{{.Domain") ||
+		strings.HasPrefix(path, "domain/") ||
+		strings.Contains(path, "/domain/")
+}
 
-		// Process template
-		return tp.ProcessTemplate(path, outputPath, data)
-	})
+// relativePath maps a template path to its slash-separated output path
+func (tp *TemplateProcessor) relativePath(templatePath string, data *TemplateData) string {
+	return filepath.FromSlash(tp.replacePlaceholders(strings.TrimSuffix(templatePath, ".tpl"), data))
 }
 
-// replacePlaceholders replaces template placeholders in file paths
+// replacePlaceholders replaces template placeholders in slash-separated file paths
 func (tp *TemplateProcessor) replacePlaceholders(path string, data *TemplateData) string {
 	path = strings.ReplaceAll(path, "{{.DomainLower}}", data.DomainLower)
 	path = strings.ReplaceAll(path, "{{.DomainTitle}}", data.DomainTitle)
 	path = strings.ReplaceAll(path, "{{.DomainUpper}}", data.DomainUpper)
 	path = strings.ReplaceAll(path, "{{.ProjectName}}", data.ProjectName)
+	path = strings.ReplaceAll(path, "{{.Contract}}", data.Contract)
+	for name, value := range data.Vars {
+		path = strings.ReplaceAll(path, "{{.Vars."+name+"}}", fmt.Sprint(value))
+	}
+	if strings.HasPrefix(path, "domain/") {
+		path = data.DomainLower + strings.TrimPrefix(path, "domain")
+	}
 	path = strings.ReplaceAll(path, "/domain/", "/"+data.DomainLower+"/")
 	return path
 }
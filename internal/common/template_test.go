@@ -0,0 +1,168 @@
+package common
+
+import (
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// TestRenderDirectoryGolden renders the template root in testdata/render on
+// pools of one to many workers and compares the files, in order, with the
+// golden ones
+func TestRenderDirectoryGolden(t *testing.T) {
+	templates, err := LoadTemplateFS(filepath.Join("testdata", "render", "templates"), "shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &GeneratorConfig{
+		ProjectName: "shop-api",
+		Port:        "8080",
+	}
+	for _, name := range []string{"order", "customer", "invoice", "product", "shipment", "refund", "coupon", "review", "cart"} {
+		config.Domains = append(config.Domains, Domain{Name: name, Fields: DefaultFields()})
+	}
+	config.Domains[0].Fields = []Field{{Name: "total", Type: FieldDecimal}, {Name: "placed_at", Type: FieldDateTime, Optional: true}}
+	golden := filepath.Join("testdata", "render", "golden")
+
+	var first []RenderedFile
+	for _, workers := range []int{1, 2, 4, 16} {
+		previous := runtime.GOMAXPROCS(workers)
+		files, err := NewTemplateProcessor(templates, "shop-api").RenderDirectory(PrepareTemplateData(config))
+		runtime.GOMAXPROCS(previous)
+		if err != nil {
+			t.Fatalf("RenderDirectory with %d workers: %v", workers, err)
+		}
+
+		if first == nil {
+			first = files
+			if *update {
+				writeGolden(t, golden, files)
+			}
+			compareGolden(t, golden, files)
+			continue
+		}
+		if !slices.EqualFunc(files, first, func(a, b RenderedFile) bool {
+			return a.Path == b.Path && string(a.Content) == string(b.Content)
+		}) {
+			t.Errorf("RenderDirectory with %d workers rendered other files or another order than with 1 worker", workers)
+		}
+	}
+}
+
+// TestRenderDirectoryErrors checks that every failing file is reported, in
+// template order, however many workers render them
+func TestRenderDirectoryErrors(t *testing.T) {
+	templates := fstest.MapFS{
+		"a.txt.tpl":                {Data: []byte("{{.Missing}}")},
+		"b.txt.tpl":                {Data: []byte("{{if}}")},
+		"c.txt.tpl":                {Data: []byte("fine")},
+		"{{.DomainLower}}.txt.tpl": {Data: []byte("{{.DomainName.Nope}}")},
+	}
+	config := &GeneratorConfig{ProjectName: "shop-api", Domains: []Domain{{Name: "order"}, {Name: "customer"}}}
+	want := []string{"a.txt.tpl:", "b.txt.tpl:", "{{.DomainLower}}.txt.tpl (domain order):", "{{.DomainLower}}.txt.tpl (domain customer):"}
+
+	for _, workers := range []int{1, 4} {
+		previous := runtime.GOMAXPROCS(workers)
+		files, err := NewTemplateProcessor(templates, "shop-api").RenderDirectory(PrepareTemplateData(config))
+		runtime.GOMAXPROCS(previous)
+		if err == nil {
+			t.Fatalf("RenderDirectory with %d workers rendered %d files without an error", workers, len(files))
+		}
+
+		lines := strings.Split(err.Error(), "\n")
+		if len(lines) != len(want) {
+			t.Fatalf("RenderDirectory with %d workers reported %d errors, want %d:\n%v", workers, len(lines), len(want), err)
+		}
+		for i, prefix := range want {
+			if !strings.HasPrefix(lines[i], prefix) {
+				t.Errorf("RenderDirectory with %d workers: error %d = %q, want it to start with %q", workers, i+1, lines[i], prefix)
+			}
+		}
+	}
+}
+
+// TestRustSerdeRenames checks that Rust fields renamed to escape a keyword
+// keep their JSON key
+func TestRustSerdeRenames(t *testing.T) {
+	templates, err := LoadTemplateFS("", "rust-axum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &GeneratorConfig{
+		ProjectName:  "shop-api",
+		Port:         "8080",
+		DatabaseType: "none",
+		Domains:      []Domain{{Name: "order", Fields: []Field{{Name: "self", Type: FieldString}, {Name: "type", Type: FieldString}, {Name: "total", Type: FieldDecimal}}}},
+	}
+	files, err := NewTemplateProcessor(templates, "shop-api").RenderDirectory(PrepareTemplateData(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]string{
+		"src/core/order.rs":                  {"#[serde(rename = \"self\")]\n    pub self_: String,", "#[serde(rename = \"type\")]\n    pub r#type: String,", "\n    pub total: "},
+		"src/http/handlers/order_handler.rs": {"#[serde(rename = \"self\")]\n    pub self_: String,", "#[serde(rename = \"type\")]\n    pub r#type: String,"},
+	}
+	for _, file := range files {
+		for _, fragment := range want[filepath.ToSlash(file.Path)] {
+			if !strings.Contains(string(file.Content), fragment) {
+				t.Errorf("%s does not contain %q:\n%s", file.Path, fragment, file.Content)
+			}
+		}
+		delete(want, filepath.ToSlash(file.Path))
+	}
+	for path := range want {
+		t.Errorf("%s was not rendered", path)
+	}
+}
+
+// compareGolden checks that files are the golden files
+func compareGolden(t *testing.T, golden string, files []RenderedFile) {
+	t.Helper()
+	want := make(map[string]bool)
+	err := filepath.WalkDir(golden, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(golden, path)
+		want[filepath.ToSlash(rel)] = true
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		path := filepath.ToSlash(file.Path)
+		if !want[path] {
+			t.Errorf("%s was rendered but has no golden file (run go test -update)", path)
+			continue
+		}
+		delete(want, path)
+		assertFile(t, filepath.Join(golden, file.Path), string(file.Content))
+	}
+	for path := range want {
+		t.Errorf("%s has a golden file but was not rendered", path)
+	}
+}
+
+// writeGolden replaces the golden files with files
+func writeGolden(t *testing.T, golden string, files []RenderedFile) {
+	t.Helper()
+	if err := os.RemoveAll(golden); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if err := writeFile(filepath.Join(golden, file.Path), file.Content); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1 @@
+{{define "header"}}// {{.ProjectName}}, generated for {{.Vars.team}}{{end}}
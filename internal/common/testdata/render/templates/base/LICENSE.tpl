@@ -0,0 +1 @@
+Base license, overridden by the set
@@ -0,0 +1,6 @@
+# {{.ProjectName}}
+
+Owned by {{.Vars.team}}.
+{{range .Domains}}
+- {{.DomainTitle}}: {{range $i, $field := .Fields}}{{if $i}}, {{end}}{{$field.Name}}{{end}}
+{{- end}}
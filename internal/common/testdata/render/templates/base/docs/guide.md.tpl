@@ -0,0 +1 @@
+# Guide, left out unless .Vars.docs
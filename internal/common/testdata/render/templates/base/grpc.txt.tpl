@@ -0,0 +1 @@
+{{if .WithGRPC}}gRPC on {{.Port}}{{end}}
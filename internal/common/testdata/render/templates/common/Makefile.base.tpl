@@ -0,0 +1,2 @@
+run: ## Run {{.ProjectName}}
+	go run . --port {{.Port}}
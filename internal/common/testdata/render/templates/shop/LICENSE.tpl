@@ -0,0 +1 @@
+{{.ProjectName}} license, overriding the base one
@@ -0,0 +1,10 @@
+{{template "header" .}}
+package models
+
+// {{.DomainTitle}} is an entity of {{.ProjectName}}
+type {{.DomainTitle}} struct {
+	ID int
+{{- range .Fields}}
+	{{pascal .Name}} {{.Type}} // position {{.Position}}
+{{- end}}
+}
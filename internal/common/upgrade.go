@@ -0,0 +1,254 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// UpgradeAction is what an upgrade does to one file
+type UpgradeAction string
+
+// Upgrade actions reported per file
+const (
+	UpgradeUnchanged UpgradeAction = "unchanged" // the file already matches the new templates
+	UpgradeCreated   UpgradeAction = "created"   // new template file
+	UpgradeUpdated   UpgradeAction = "updated"   // untouched by the user, replaced
+	UpgradeMerged    UpgradeAction = "merged"    // user changes and template changes combined
+	UpgradeConflict  UpgradeAction = "conflict"  // merged with conflict markers
+	UpgradeKept      UpgradeAction = "kept"      // no longer generated but modified by the user, left in place
+	UpgradeDeleted   UpgradeAction = "deleted"   // deleted by the user, not created again
+	UpgradeRemoved   UpgradeAction = "removed"   // no longer generated and untouched, deleted
+)
+
+// UpgradeOptions configures Upgrade
+type UpgradeOptions struct {
+	TemplateSource     string // new templates; the recorded source or the embedded templates when empty
+	BaseTemplateSource string // templates the project was generated with, to re-render the merge base
+	DryRun             bool   // compute the result without writing anything
+}
+
+// UpgradeFile is the outcome for one file
+type UpgradeFile struct {
+	Path      string
+	Action    UpgradeAction
+	Conflicts int  // conflict markers written by the merge
+	NoBase    bool // merged two-way, without the original rendering, so every line both sides changed is a conflict
+}
+
+// UpgradeResult lists what Upgrade did, in path order
+type UpgradeResult struct {
+	Generator   string
+	FromVersion string
+	Files       []UpgradeFile
+}
+
+// Upgrade re-renders the project in dir with the configuration recorded in
+// its lock file and the current templates. Files the user has not touched
+// are replaced; modified files get a three-way merge between the original
+// rendering (the base), the user's file and the new rendering. The base is
+// re-rendered from BaseTemplateSource when given or from the recorded template
+// source when upgrading to another one; otherwise it is looked up in the
+// project's git history by the hash recorded in the lock. Files without a
+// base are merged two-way and flagged NoBase.
+func Upgrade(dir string, options UpgradeOptions) (*UpgradeResult, error) {
+	project, err := OpenProject(dir, options.TemplateSource)
+	if err != nil {
+		return nil, err
+	}
+	lock, generator, config, templates := project.Lock, project.Generator, project.Config, project.Templates
+
+	rendered, err := project.Render()
+	if err != nil {
+		return nil, fmt.Errorf("failed to render the new templates: %w", err)
+	}
+
+	base, err := upgradeBase(generator, lock, config, templates, rendered, options.BaseTemplateSource)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UpgradeResult{Generator: generator.GetName(), FromVersion: lock.Version}
+	writes := make(map[string][]byte)
+	generated := make(map[string]bool, len(rendered))
+
+	for _, file := range rendered {
+		path := filepath.ToSlash(file.Path)
+		generated[path] = true
+		outcome, content, err := upgradeFile(dir, path, lock.Files[path], base[path], file.Content)
+		if err != nil {
+			return nil, err
+		}
+		if content != nil {
+			writes[path] = content
+		}
+		result.Files = append(result.Files, outcome)
+	}
+
+	// Files the templates no longer produce
+	var removed []string
+	for path, hash := range lock.Files {
+		if generated[path] {
+			continue
+		}
+		current, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+		if err != nil {
+			continue
+		}
+		if HashContent(current) == hash {
+			removed = append(removed, path)
+			result.Files = append(result.Files, UpgradeFile{Path: path, Action: UpgradeRemoved})
+		} else {
+			result.Files = append(result.Files, UpgradeFile{Path: path, Action: UpgradeKept})
+		}
+	}
+	sort.Slice(result.Files, func(i, j int) bool { return result.Files[i].Path < result.Files[j].Path })
+
+	if options.DryRun {
+		return result, nil
+	}
+
+	for path, content := range writes {
+		if err := writeFile(filepath.Join(dir, filepath.FromSlash(path)), content); err != nil {
+			return nil, err
+		}
+	}
+	for _, path := range removed {
+		if err := os.Remove(filepath.Join(dir, filepath.FromSlash(path))); err != nil {
+			return nil, err
+		}
+	}
+	if err := WriteLock(generator.GetName(), config, templates, rendered); err != nil {
+		return nil, fmt.Errorf("failed to update %s: %w", LockFile, err)
+	}
+
+	return result, nil
+}
+
+// upgradeFile decides what happens to one generated file and returns the
+// content to write, or nil to leave the file alone
+func upgradeFile(dir, path, lockedHash string, base, rendered []byte) (UpgradeFile, []byte, error) {
+	outcome := UpgradeFile{Path: path}
+
+	current, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+	switch {
+	case os.IsNotExist(err) && lockedHash != "":
+		// Deleted by the user
+		outcome.Action = UpgradeDeleted
+		return outcome, nil, nil
+	case os.IsNotExist(err):
+		outcome.Action = UpgradeCreated
+		return outcome, rendered, nil
+	case err != nil:
+		return outcome, nil, err
+	}
+
+	switch {
+	case bytes.Equal(current, rendered):
+		outcome.Action = UpgradeUnchanged
+		return outcome, nil, nil
+	case HashContent(current) == lockedHash:
+		outcome.Action = UpgradeUpdated
+		return outcome, rendered, nil
+	}
+
+	var merged []byte
+	if base != nil {
+		merged, outcome.Conflicts = Merge3(base, current, rendered)
+	} else {
+		merged, outcome.Conflicts = MergeFiles(current, rendered)
+	}
+	if bytes.Equal(merged, current) {
+		outcome.Action = UpgradeUnchanged
+		return outcome, nil, nil
+	}
+
+	outcome.NoBase = base == nil
+	outcome.Action = UpgradeMerged
+	if outcome.Conflicts > 0 {
+		outcome.Action = UpgradeConflict
+	}
+	return outcome, merged, nil
+}
+
+// upgradeBase returns the original rendering of the project's files, as far
+// as it can be recovered, keyed by slash-separated path
+func upgradeBase(generator Generator, lock *Lock, config *GeneratorConfig, templates fs.FS, rendered []RenderedFile, baseTemplateSource string) (map[string][]byte, error) {
+	base := make(map[string][]byte)
+	files := rendered
+
+	// A recorded source other than the new one (e.g. a git ref) can be
+	// fetched again to render the base
+	if baseTemplateSource == "" && lock.Config.TemplateSource != config.TemplateSource {
+		baseTemplateSource = lock.Config.TemplateSource
+	}
+
+	switch {
+	case baseTemplateSource != "":
+		baseConfig := lock.GeneratorConfig(config.OutputDir)
+		baseConfig.TemplateSource = baseTemplateSource
+		baseTemplates, err := baseConfig.Templates(generator.GetName())
+		if err != nil {
+			return nil, err
+		}
+		files, err = NewTemplateProcessor(baseTemplates, config.OutputDir).RenderDirectory(PrepareTemplateData(baseConfig))
+		if err != nil {
+			return nil, fmt.Errorf("failed to render the base templates: %w", err)
+		}
+	default:
+		// Unchanged templates render the base again. Embedded templates of
+		// an older ccin cannot be loaded any more, so once they changed the
+		// base can only come from git below.
+		digest, err := TemplatesDigest(templates)
+		if err != nil {
+			return nil, err
+		}
+		if digest != lock.Templates {
+			files = nil
+		}
+	}
+
+	for _, file := range files {
+		path := filepath.ToSlash(file.Path)
+		if HashContent(file.Content) == lock.Files[path] {
+			base[path] = file.Content
+		}
+	}
+
+	// Fall back to the committed versions of the files the user modified
+	for path, hash := range lock.Files {
+		if _, ok := base[path]; ok {
+			continue
+		}
+		current, err := os.ReadFile(filepath.Join(config.OutputDir, filepath.FromSlash(path)))
+		if err != nil || HashContent(current) == hash {
+			continue
+		}
+		if content := gitVersion(config.OutputDir, path, hash); content != nil {
+			base[path] = content
+		}
+	}
+	return base, nil
+}
+
+// gitVersion looks for a committed version of path in the git history of
+// dir whose content has the given hash
+func gitVersion(dir, path, hash string) []byte {
+	log, err := exec.Command("git", "-C", dir, "log", "--format=%H", "--", path).Output()
+	if err != nil {
+		return nil
+	}
+
+	for _, commit := range strings.Fields(string(log)) {
+		content, err := exec.Command("git", "-C", dir, "show", commit+":./"+path).Output()
+		if err == nil && HashContent(content) == hash {
+			return content
+		}
+	}
+	return nil
+}
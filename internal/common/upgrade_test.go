@@ -0,0 +1,212 @@
+package common
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// upgradeGenerator renders the templates of the upgrade tests, read from
+// the template source each test generates its project with
+var upgradeGenerator = NewBaseGenerator("upgrade-test", "Generator of the upgrade tests", GeneratorMetadata{DisplayName: "Upgrade Test"})
+
+func init() {
+	Registry.Register(upgradeGenerator)
+}
+
+// Templates of the upgrade tests before and after the upgrade, by file name
+var (
+	upgradeTemplatesV1 = map[string]string{
+		"merged.txt.tpl":    "one\ntwo\nthree\nfour\nfive\n",
+		"conflict.txt.tpl":  "a\nb\nc\n",
+		"updated.txt.tpl":   "old\n",
+		"deleted.txt.tpl":   "gone\n",
+		"removed.txt.tpl":   "x\n",
+		"kept.txt.tpl":      "y\n",
+		"unchanged.txt.tpl": "same\n",
+	}
+	upgradeTemplatesV2 = map[string]string{
+		"merged.txt.tpl":    "one\ntwo\nthree\nfour\nFIVE\n",
+		"conflict.txt.tpl":  "a\nB\nc\n",
+		"updated.txt.tpl":   "new\n",
+		"deleted.txt.tpl":   "gone again\n",
+		"created.txt.tpl":   "fresh\n",
+		"unchanged.txt.tpl": "same\n",
+	}
+)
+
+func TestUpgrade(t *testing.T) {
+	v1, v2 := upgradeTemplates(t, upgradeTemplatesV1), upgradeTemplates(t, upgradeTemplatesV2)
+	dir := generateUpgradeProject(t, v1)
+	editUpgradeProject(t, dir)
+
+	// A dry run reports the same outcome without writing anything
+	for _, dryRun := range []bool{true, false} {
+		result, err := Upgrade(dir, UpgradeOptions{TemplateSource: v2, DryRun: dryRun})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []UpgradeFile{
+			{Path: "conflict.txt", Action: UpgradeConflict, Conflicts: 1},
+			{Path: "created.txt", Action: UpgradeCreated},
+			{Path: "deleted.txt", Action: UpgradeDeleted},
+			{Path: "kept.txt", Action: UpgradeKept},
+			{Path: "merged.txt", Action: UpgradeMerged},
+			{Path: "removed.txt", Action: UpgradeRemoved},
+			{Path: "unchanged.txt", Action: UpgradeUnchanged},
+			{Path: "updated.txt", Action: UpgradeUpdated},
+		}
+		assertUpgradeFiles(t, result.Files, want)
+		if dryRun {
+			assertFile(t, filepath.Join(dir, "merged.txt"), "ONE\ntwo\nthree\nfour\nfive\n")
+		}
+	}
+
+	assertFile(t, filepath.Join(dir, "merged.txt"), "ONE\ntwo\nthree\nfour\nFIVE\n")
+	assertFile(t, filepath.Join(dir, "conflict.txt"), "a\n<<<<<<< existing\nmine\n=======\nB\n>>>>>>> ccin\nc\n")
+	assertFile(t, filepath.Join(dir, "updated.txt"), "new\n")
+	assertFile(t, filepath.Join(dir, "created.txt"), "fresh\n")
+	assertFile(t, filepath.Join(dir, "kept.txt"), "y mine\n")
+	for _, name := range []string{"deleted.txt", "removed.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s exists after the upgrade", name)
+		}
+	}
+
+	lock, err := ReadLock(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock.Config.TemplateSource != v2 {
+		t.Errorf("the lock records the template source %q, want %q", lock.Config.TemplateSource, v2)
+	}
+	if _, ok := lock.Files["removed.txt"]; ok {
+		t.Error("the lock still records removed.txt")
+	}
+}
+
+func TestUpgradeBase(t *testing.T) {
+	tests := []struct {
+		name   string
+		commit bool // commit the project before editing it
+		base   bool // pass the original templates as BaseTemplateSource
+		want   UpgradeFile
+	}{
+		{
+			name: "no base",
+			want: UpgradeFile{Path: "merged.txt", Action: UpgradeConflict, Conflicts: 2, NoBase: true},
+		},
+		{
+			name: "base templates",
+			base: true,
+			want: UpgradeFile{Path: "merged.txt", Action: UpgradeMerged},
+		},
+		{
+			name:   "git history",
+			commit: true,
+			want:   UpgradeFile{Path: "merged.txt", Action: UpgradeMerged},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := exec.LookPath("git"); test.commit && err != nil {
+				t.Skip("git is not installed")
+			}
+
+			// The templates change in place, so the base cannot be
+			// rendered again from the recorded source
+			source := upgradeTemplates(t, upgradeTemplatesV1)
+			dir := generateUpgradeProject(t, source)
+			if test.commit {
+				git(t, dir, "init", "--quiet")
+				git(t, dir, "add", "--all")
+				git(t, dir, "-c", "user.name=ccin", "-c", "user.email=ccin@example.com", "commit", "--quiet", "-m", "Generate")
+			}
+			editUpgradeProject(t, dir)
+			writeFiles(t, filepath.Join(source, upgradeGenerator.GetName()), map[string]string{"merged.txt.tpl": upgradeTemplatesV2["merged.txt.tpl"]})
+
+			var options UpgradeOptions
+			if test.base {
+				options.BaseTemplateSource = upgradeTemplates(t, upgradeTemplatesV1)
+			}
+
+			project, err := OpenProject(dir, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			rendered, err := project.Render()
+			if err != nil {
+				t.Fatal(err)
+			}
+			base, err := upgradeBase(project.Generator, project.Lock, project.Config, project.Templates, rendered, options.BaseTemplateSource)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := upgradeTemplatesV1["merged.txt.tpl"]
+			if test.want.NoBase {
+				want = ""
+			}
+			if got := string(base["merged.txt"]); got != want {
+				t.Errorf("base of merged.txt = %q, want %q", got, want)
+			}
+
+			result, err := Upgrade(dir, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, file := range result.Files {
+				if file.Path == test.want.Path {
+					assertUpgradeFiles(t, []UpgradeFile{file}, []UpgradeFile{test.want})
+				}
+			}
+		})
+	}
+}
+
+// upgradeTemplates writes a template root for upgradeGenerator and returns
+// its directory
+func upgradeTemplates(t *testing.T, templates map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	writeFiles(t, filepath.Join(root, upgradeGenerator.GetName()), templates)
+	return root
+}
+
+// generateUpgradeProject generates a project from the template root source
+// and returns its directory
+func generateUpgradeProject(t *testing.T, source string) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "shop")
+	if err := upgradeGenerator.Generate(&GeneratorConfig{ProjectName: "shop", OutputDir: dir, TemplateSource: source}); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// editUpgradeProject makes the user's changes to a generated project
+func editUpgradeProject(t *testing.T, dir string) {
+	t.Helper()
+	writeFiles(t, dir, map[string]string{
+		"merged.txt":   "ONE\ntwo\nthree\nfour\nfive\n",
+		"conflict.txt": "a\nmine\nc\n",
+		"kept.txt":     "y mine\n",
+	})
+	if err := os.Remove(filepath.Join(dir, "deleted.txt")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// assertUpgradeFiles checks the outcome of an upgrade
+func assertUpgradeFiles(t *testing.T, got, want []UpgradeFile) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("upgrade files = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("upgrade of %s = %+v, want %+v", want[i].Path, got[i], want[i])
+		}
+	}
+}
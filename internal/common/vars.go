@@ -0,0 +1,85 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidVariables is returned when template variables are missing or
+// fail the validation declared in the manifest
+var ErrInvalidVariables = errors.New("invalid template variables")
+
+// varNamePattern restricts variable names to what {{.Vars.name}} can reach
+var varNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ParseVars parses key=value assignments (from --set) into template
+// variables. Values stay strings until they are resolved against the
+// manifest, which coerces them to the declared type.
+func ParseVars(assignments []string) (map[string]any, error) {
+	vars := make(map[string]any, len(assignments))
+	for _, assignment := range assignments {
+		name, value, ok := strings.Cut(assignment, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid variable '%s': expected key=value", assignment)
+		}
+		name = strings.TrimSpace(name)
+		if err := validateVarName(name); err != nil {
+			return nil, err
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
+
+// LoadValuesFile reads template variables from a YAML mapping (from --values)
+func LoadValuesFile(path string) (map[string]any, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var vars map[string]any
+	if err := yaml.Unmarshal(content, &vars); err != nil {
+		return nil, fmt.Errorf("invalid values file %s: %w", path, err)
+	}
+	for name := range vars {
+		if err := validateVarName(name); err != nil {
+			return nil, fmt.Errorf("invalid values file %s: %w", path, err)
+		}
+	}
+	return vars, nil
+}
+
+// validateVarName checks that a variable can be used as {{.Vars.name}}
+func validateVarName(name string) error {
+	if !varNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid variable name '%s': use letters, digits and underscores, starting with a letter", name)
+	}
+	return nil
+}
+
+// coerceValue converts a string value of an undeclared variable to a bool or
+// number when it reads as one and formats back to the same text, so
+// "true" and "3" become typed while "007" and "1e3" stay strings
+func coerceValue(value any) any {
+	text, ok := value.(string)
+	if !ok {
+		return value
+	}
+	if b, err := strconv.ParseBool(text); err == nil && strconv.FormatBool(b) == text {
+		return b
+	}
+	if n, err := strconv.Atoi(text); err == nil && strconv.Itoa(n) == text {
+		return n
+	}
+	if f, err := strconv.ParseFloat(text, 64); err == nil && strconv.FormatFloat(f, 'f', -1, 64) == text {
+		return f
+	}
+	return text
+}
@@ -0,0 +1,101 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseVars(t *testing.T) {
+	tests := []struct {
+		assignments []string
+		want        map[string]any
+		wantErr     string
+	}{
+		{
+			assignments: []string{"team=platform", "replicas=3", " docs =true"},
+			want:        map[string]any{"team": "platform", "replicas": "3", "docs": "true"},
+		},
+		{
+			assignments: []string{"query=a=b", "empty="},
+			want:        map[string]any{"query": "a=b", "empty": ""},
+		},
+		{
+			assignments: []string{"team"},
+			wantErr:     "invalid variable 'team': expected key=value",
+		},
+		{
+			assignments: []string{"1team=x"},
+			wantErr:     "invalid variable name '1team'",
+		},
+		{
+			assignments: []string{"team-name=x"},
+			wantErr:     "invalid variable name 'team-name'",
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParseVars(test.assignments)
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("ParseVars(%q) error = %v, want one containing %q", test.assignments, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseVars(%q) = %v, %v, want %v", test.assignments, got, err, test.want)
+		}
+	}
+}
+
+func TestLoadValuesFile(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "values.yaml")
+	invalid := filepath.Join(dir, "invalid.yaml")
+	if err := os.WriteFile(valid, []byte("team: platform\nreplicas: 3\ndocs: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(invalid, []byte("team-name: platform\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	vars, err := LoadValuesFile(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]any{"team": "platform", "replicas": 3, "docs": true}; !reflect.DeepEqual(vars, want) {
+		t.Errorf("LoadValuesFile = %v, want %v", vars, want)
+	}
+	if _, err := LoadValuesFile(invalid); err == nil || !strings.Contains(err.Error(), "invalid variable name 'team-name'") {
+		t.Errorf("LoadValuesFile of an invalid name: error = %v", err)
+	}
+}
+
+func TestCoerceValue(t *testing.T) {
+	tests := []struct {
+		value any
+		want  any
+	}{
+		{"true", true},
+		{"false", false},
+		{"True", "True"},
+		{"1", 1},
+		{"-4", -4},
+		{"007", "007"},
+		{"+3", "+3"},
+		{"2.5", 2.5},
+		{"1e3", "1e3"},
+		{"2.50", "2.50"},
+		{"", ""},
+		{"platform", "platform"},
+		{3, 3},
+		{false, false},
+	}
+	for _, test := range tests {
+		if got := coerceValue(test.value); got != test.want {
+			t.Errorf("coerceValue(%#v) = %#v, want %#v", test.value, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+// Package generators links every built-in generator into the binary. Each
+// generator registers itself in common.Registry from its init function, so
+// adding a stack only requires a new package and a blank import below.
+package generators
+
+import (
+	_ "github.com/chrisloarryn/ccin/internal/generators/go-fiber"
+	_ "github.com/chrisloarryn/ccin/internal/generators/go-gin"
+	_ "github.com/chrisloarryn/ccin/internal/generators/nestjs"
+	_ "github.com/chrisloarryn/ccin/internal/generators/rust-axum"
+	_ "github.com/chrisloarryn/ccin/internal/generators/swift-vapor"
+)
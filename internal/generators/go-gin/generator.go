@@ -1,10 +1,6 @@
 package gogin
 
-import (
-	"fmt"
-
-	"github.com/chrisloarryn/ccin/internal/common"
-)
+import "github.com/chrisloarryn/ccin/internal/common"
 
 // Generator implements the Go Gin generator
 type Generator struct {
@@ -17,34 +13,43 @@ func NewGenerator() *Generator {
 		BaseGenerator: common.NewBaseGenerator(
 			"go-gin",
 			"Generate Go CRUD application with Gin framework, REST/gRPC support, and GCP integration",
+			common.GeneratorMetadata{
+				DisplayName:        "Go Gin",
+				Icon:               "🟢",
+				Summary:            "Go with Gin framework, PostgreSQL, REST/gRPC",
+				DefaultPort:        "8080",
+				SupportedDatabases: []string{"postgresql"},
+				SupportsGRPC:       true,
+				IDType:             common.FieldInt,
+				NextSteps:          []string{"go mod tidy", "make dev"},
+				Hooks: []common.Hook{
+					{Name: "Install dependencies", Command: []string{"go", "mod", "tidy"}},
+					{Name: "Organize imports", Command: []string{"goimports", "-w", "."}},
+					{Name: "Format code", Command: []string{"gofmt", "-w", "."}},
+				},
+				ProtoHooks: []common.Hook{
+					{Name: "Compile the proto contract", Command: []string{"make", "proto"}},
+				},
+				Features: []common.Feature{
+					{Name: "Go 1.25.1", Detail: "with Gin framework", Files: []string{"go.mod", "main.go"}},
+					{Name: "PostgreSQL", Detail: "with GORM"},
+					{Name: "REST API", Detail: "with JSON responses", Files: []string{"internal/api/routes.go"}},
+					{Name: "gRPC", Detail: "support (optional with --grpc)"},
+					{Name: "Clean Architecture", Detail: "layers"},
+					{Name: "GCP Metrics", Detail: "middleware (optional)"},
+					{Name: "Docker", Detail: "multi-stage production build", Files: []string{"Dockerfile"}},
+					{Name: "Makefile", Detail: "build, test and run targets", Files: []string{"Makefile"}},
+				},
+				Example:      "ccin generate go-gin orders-api --domain order --grpc",
+				NameExamples: []string{"orders-api", "inventory-service"},
+				Detect:       []common.ProjectMarker{{File: "go.mod", Pattern: `github\.com/gin-gonic/gin`}},
+				ProjectName:  common.ProjectMarker{File: "go.mod", Pattern: `(?m)^module\s+(?:\S+/)?([^/\s]+)\s*$`},
+				GRPCMarker:   common.ProjectMarker{File: "internal/grpc/server.go", Pattern: `grpc\.NewServer`},
+			},
 		),
 	}
 }
 
-// Generate generates a Go Gin project
-func (g *Generator) Generate(config *common.GeneratorConfig) error {
-	// Set defaults for Go Gin
-	if config.Port == "" {
-		config.Port = "8080"
-	}
-	if config.DatabaseType == "" {
-		config.DatabaseType = "postgresql"
-	}
-
-	// Prepare template data
-	data := common.PrepareTemplateData(config)
-
-	// Create template processor
-	processor := common.NewTemplateProcessor(config.TemplateDir, config.OutputDir)
-
-	// Process templates
-	if err := processor.ProcessDirectory(data); err != nil {
-		return fmt.Errorf("failed to process Go Gin templates: %w", err)
-	}
-
-	return nil
-}
-
 // init registers the generator
 func init() {
 	common.Registry.Register(NewGenerator())
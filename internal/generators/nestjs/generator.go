@@ -1,10 +1,6 @@
 package nestjs
 
-import (
-	"fmt"
-
-	"github.com/chrisloarryn/ccin/internal/common"
-)
+import "github.com/chrisloarryn/ccin/internal/common"
 
 // Generator implements the NestJS generator
 type Generator struct {
@@ -17,34 +13,38 @@ func NewGenerator() *Generator {
 		BaseGenerator: common.NewBaseGenerator(
 			"nestjs",
 			"Generate NestJS CRUD application with TypeScript, MongoDB, and GCP integration",
+			common.GeneratorMetadata{
+				DisplayName:        "NestJS",
+				Icon:               "📦",
+				Summary:            "NestJS with TypeScript, MongoDB, Swagger, Jest",
+				DefaultPort:        "3000",
+				SupportedDatabases: []string{"mongodb"},
+				SupportsGRPC:       false,
+				IDType:             common.FieldString,
+				NextSteps:          []string{"npm install", "npm run start:dev"},
+				Hooks: []common.Hook{
+					{Name: "Install dependencies", Command: []string{"npm", "install"}},
+					{Name: "Format code", Command: []string{"npx", "--no", "prettier", "--write", "src"}},
+				},
+				Features: []common.Feature{
+					{Name: "NestJS", Detail: "framework with TypeScript", Files: []string{"package.json", "nest-cli.json", "tsconfig.json", "tsconfig.build.json", "src/main.ts"}},
+					{Name: "MongoDB", Detail: "with Mongoose ODM"},
+					{Name: "Swagger/OpenAPI", Detail: "automatic documentation"},
+					{Name: "GCP Metrics", Detail: "interceptors (optional)"},
+					{Name: "Docker", Detail: "multi-stage production build", Files: []string{"Dockerfile"}},
+					{Name: "Makefile", Detail: "build, test and run targets", Files: []string{"Makefile"}},
+					{Name: "Jest", Detail: "testing configuration"},
+					{Name: "ESLint + Prettier", Detail: "code quality", Files: []string{".eslintrc.js", ".prettierrc"}},
+				},
+				Example:      "ccin generate nestjs my-api --domain user --gcp-project my-project",
+				NameExamples: []string{"my-api", "user-service"},
+				Detect:       []common.ProjectMarker{{File: "package.json", Pattern: `"@nestjs/core"`}},
+				ProjectName:  common.ProjectMarker{File: "package.json", Pattern: `"name":\s*"([^"]+)"`},
+			},
 		),
 	}
 }
 
-// Generate generates a NestJS project
-func (g *Generator) Generate(config *common.GeneratorConfig) error {
-	// Set defaults for NestJS
-	if config.Port == "" {
-		config.Port = "3000"
-	}
-	if config.DatabaseType == "" {
-		config.DatabaseType = "mongodb"
-	}
-
-	// Prepare template data
-	data := common.PrepareTemplateData(config)
-
-	// Create template processor
-	processor := common.NewTemplateProcessor(config.TemplateDir, config.OutputDir)
-
-	// Process templates
-	if err := processor.ProcessDirectory(data); err != nil {
-		return fmt.Errorf("failed to process NestJS templates: %w", err)
-	}
-
-	return nil
-}
-
 // init registers the generator
 func init() {
 	common.Registry.Register(NewGenerator())
@@ -1,10 +1,6 @@
 package rustaxum
 
-import (
-	"fmt"
-
-	"github.com/chrisloarryn/ccin/internal/common"
-)
+import "github.com/chrisloarryn/ccin/internal/common"
 
 // Generator implements the Rust Axum generator
 type Generator struct {
@@ -17,34 +13,37 @@ func NewGenerator() *Generator {
 		BaseGenerator: common.NewBaseGenerator(
 			"rust-axum",
 			"Generate Rust backend with Axum (REST) + optional Tonic (gRPC), Clean Architecture",
+			common.GeneratorMetadata{
+				DisplayName:        "Rust Axum",
+				Icon:               "🦀",
+				Summary:            "Rust with Axum (REST) and Tonic (gRPC)",
+				DefaultPort:        "8080",
+				SupportedDatabases: []string{"none"},
+				SupportsGRPC:       true,
+				IDType:             common.FieldInt,
+				NextSteps:          []string{"cargo build", "cargo run"},
+				Hooks: []common.Hook{
+					{Name: "Fetch dependencies", Command: []string{"cargo", "fetch"}},
+					{Name: "Format code", Command: []string{"cargo", "fmt"}},
+				},
+				Features: []common.Feature{
+					{Name: "Rust", Detail: "with Axum on Tokio/Hyper/Tower", Files: []string{"Cargo.toml", "src/main.rs"}},
+					{Name: "REST API", Detail: "with clean architecture layers (http/services/core)"},
+					{Name: "gRPC", Detail: "Tonic server, .proto and build.rs (optional with --grpc)"},
+					{Name: "Tracing + CORS", Detail: "tower-http layers"},
+					{Name: "Docker", Detail: "multi-stage production build", Files: []string{"Dockerfile"}},
+					{Name: "Makefile", Detail: "build, test and run targets", Files: []string{"Makefile"}},
+				},
+				Example:      "ccin generate rust-axum my-rust-api --domain user --grpc",
+				NameExamples: []string{"my-rust-api", "billing-service"},
+				Detect:       []common.ProjectMarker{{File: "Cargo.toml", Pattern: `(?m)^axum\s*=`}},
+				ProjectName:  common.ProjectMarker{File: "Cargo.toml", Pattern: `(?m)^name\s*=\s*"([^"]+)"`},
+				GRPCMarker:   common.ProjectMarker{File: "build.rs", Pattern: `tonic_build`},
+			},
 		),
 	}
 }
 
-// Generate generates a Rust Axum project
-func (g *Generator) Generate(config *common.GeneratorConfig) error {
-	// Set defaults for Rust Axum
-	if config.Port == "" {
-		config.Port = "8080"
-	}
-	if config.DatabaseType == "" {
-		config.DatabaseType = "none"
-	}
-
-	// Prepare template data
-	data := common.PrepareTemplateData(config)
-
-	// Create template processor
-	processor := common.NewTemplateProcessor(config.TemplateDir, config.OutputDir)
-
-	// Process templates
-	if err := processor.ProcessDirectory(data); err != nil {
-		return fmt.Errorf("failed to process Rust Axum templates: %w", err)
-	}
-
-	return nil
-}
-
 // init registers the generator
 func init() {
 	common.Registry.Register(NewGenerator())
@@ -1,10 +1,6 @@
 package swiftvapor
 
-import (
-	"fmt"
-
-	"github.com/chrisloarryn/ccin/internal/common"
-)
+import "github.com/chrisloarryn/ccin/internal/common"
 
 // Generator implements the Swift Vapor generator
 type Generator struct {
@@ -17,34 +13,35 @@ func NewGenerator() *Generator {
 		BaseGenerator: common.NewBaseGenerator(
 			"swift-vapor",
 			"Generate Swift Vapor backend (REST + optional gRPC) with Clean Architecture",
+			common.GeneratorMetadata{
+				DisplayName:        "Swift Vapor",
+				Icon:               "🐦",
+				Summary:            "Swift with Vapor framework, REST/gRPC",
+				DefaultPort:        "8080",
+				SupportedDatabases: []string{"none"},
+				SupportsGRPC:       true,
+				IDType:             common.FieldUUID,
+				NextSteps:          []string{"swift build", "swift run"},
+				Hooks: []common.Hook{
+					{Name: "Resolve dependencies", Command: []string{"swift", "package", "resolve"}},
+				},
+				Features: []common.Feature{
+					{Name: "Swift 6.1.2", Detail: "with Vapor 4 framework", Files: []string{"Package.swift"}},
+					{Name: "REST API", Detail: "with clean architecture layers (Controllers/Services/Models)"},
+					{Name: "gRPC", Detail: "scaffolding (optional with --grpc)"},
+					{Name: "Docker", Detail: "multi-stage production build", Files: []string{"Dockerfile"}},
+					{Name: "Makefile", Detail: "build, test and run targets", Files: []string{"Makefile"}},
+				},
+				Example:      "ccin generate swift-vapor catalog-api --domain product --grpc",
+				NameExamples: []string{"catalog-api", "payment-service"},
+				Detect:       []common.ProjectMarker{{File: "Package.swift", Pattern: `vapor/vapor`}},
+				ProjectName:  common.ProjectMarker{File: "Package.swift", Pattern: `name:\s*"([^"]+)"`},
+				GRPCMarker:   common.ProjectMarker{File: "Sources/App/configure.swift", Pattern: `try startGRPCServer\(app\)`},
+			},
 		),
 	}
 }
 
-// Generate generates a Swift Vapor project
-func (g *Generator) Generate(config *common.GeneratorConfig) error {
-	// Set defaults for Swift Vapor
-	if config.Port == "" {
-		config.Port = "8080"
-	}
-	if config.DatabaseType == "" {
-		config.DatabaseType = "none"
-	}
-
-	// Prepare template data
-	data := common.PrepareTemplateData(config)
-
-	// Create template processor
-	processor := common.NewTemplateProcessor(config.TemplateDir, config.OutputDir)
-
-	// Process templates
-	if err := processor.ProcessDirectory(data); err != nil {
-		return fmt.Errorf("failed to process Swift Vapor templates: %w", err)
-	}
-
-	return nil
-}
-
 // init registers the generator
 func init() {
 	common.Registry.Register(NewGenerator())
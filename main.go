@@ -2,6 +2,14 @@ package main
 
 import "github.com/chrisloarryn/ccin/cmd"
 
+// Set by the release build through -ldflags "-X main.version=..."
+var (
+	version = "dev"
+	commit  = "none"
+	date    = "unknown"
+)
+
 func main() {
+	cmd.SetVersionInfo(version, commit, date)
 	cmd.Execute()
 }
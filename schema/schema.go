@@ -0,0 +1,11 @@
+// Package schema embeds the published JSON Schemas of ccin's input files.
+package schema
+
+import _ "embed"
+
+// Service is the JSON Schema of the service spec read by
+// `ccin generate -f <file>`. Editors can validate spec files against
+// schema/service.schema.json as well.
+//
+//go:embed service.schema.json
+var Service []byte
@@ -0,0 +1,34 @@
+{{/* Go model and request payloads for the domain in scope; the validation
+   tag of required fields comes from the set's "go/validate-tag" */}}
+{{define "go/model" -}}
+package models
+
+import (
+	"time"
+)
+
+// {{.DomainTitle}} represents a {{.DomainLower}} entity
+type {{.DomainTitle}} struct {
+	ID        int       `json:"id" db:"id"`
+{{- range .Fields}}
+	{{.Pascal}} {{.GoFieldType}} `json:"{{.Snake}}" db:"{{.Snake}}"`
+{{- end}}
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Create{{.DomainTitle}}Request represents the request payload for creating a {{.DomainLower}}
+type Create{{.DomainTitle}}Request struct {
+{{- range .Fields}}
+	{{.Pascal}} {{.GoFieldType}} `json:"{{.Snake}}"{{if and .IsString (not .Optional)}} {{template "go/validate-tag"}}:"required"{{end}}`
+{{- end}}
+}
+
+// Update{{.DomainTitle}}Request represents the request payload for updating a {{.DomainLower}}.
+// Fields left out of the payload are not modified.
+type Update{{.DomainTitle}}Request struct {
+{{- range .Fields}}
+	{{.Pascal}} *{{.GoType}} `json:"{{.Snake}}"`
+{{- end}}
+}
+{{- end}}
@@ -0,0 +1,130 @@
+{{/* Go CRUD service over database/sql for the domain in scope */}}
+{{define "go/service" -}}
+{{- $item := .DomainName | camel | goIdent}}
+{{- $items := .DomainName | camel | pluralize | goIdent}}
+{{- $table := .DomainName | snake | pluralize -}}
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"{{.ModulePath}}/internal/models"
+)
+
+// {{.DomainTitle}}Service handles business logic for {{pluralize .DomainLower}}
+type {{.DomainTitle}}Service struct {
+	db *sql.DB
+}
+
+// New{{.DomainTitle}}Service creates a new {{.DomainLower}} service
+func New{{.DomainTitle}}Service(db *sql.DB) *{{.DomainTitle}}Service {
+	return &{{.DomainTitle}}Service{db: db}
+}
+
+// GetAll returns all {{pluralize .DomainLower}}
+func (s *{{.DomainTitle}}Service) GetAll() ([]models.{{.DomainTitle}}, error) {
+	query := `SELECT id, {{.Fields.Columns}}, created_at, updated_at FROM {{$table}} ORDER BY created_at DESC`
+	
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query {{pluralize .DomainLower}}: %w", err)
+	}
+	defer rows.Close()
+
+	var {{$items}} []models.{{.DomainTitle}}
+	for rows.Next() {
+		var {{$item}} models.{{.DomainTitle}}
+		err := rows.Scan(&{{$item}}.ID, {{range .Fields}}&{{$item}}.{{.Pascal}}, {{end}}&{{$item}}.CreatedAt, &{{$item}}.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan {{.DomainLower}}: %w", err)
+		}
+		{{$items}} = append({{$items}}, {{$item}})
+	}
+
+	return {{$items}}, nil
+}
+
+// GetByID returns a {{.DomainLower}} by ID
+func (s *{{.DomainTitle}}Service) GetByID(id int) (*models.{{.DomainTitle}}, error) {
+	query := `SELECT id, {{.Fields.Columns}}, created_at, updated_at FROM {{$table}} WHERE id = $1`
+	
+	var {{$item}} models.{{.DomainTitle}}
+	err := s.db.QueryRow(query, id).Scan(&{{$item}}.ID, {{range .Fields}}&{{$item}}.{{.Pascal}}, {{end}}&{{$item}}.CreatedAt, &{{$item}}.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("{{.DomainLower}} not found")
+		}
+		return nil, fmt.Errorf("failed to get {{.DomainLower}}: %w", err)
+	}
+
+	return &{{$item}}, nil
+}
+
+// Create creates a new {{.DomainLower}}
+func (s *{{.DomainTitle}}Service) Create(req *models.Create{{.DomainTitle}}Request) (*models.{{.DomainTitle}}, error) {
+	query := `INSERT INTO {{$table}} ({{.Fields.Columns}}, created_at, updated_at) 
+			  VALUES ({{.Fields.Placeholders 1}}, ${{.Fields.Next 1}}, ${{.Fields.Next 2}}) RETURNING id`
+	
+	now := time.Now()
+	var id int
+	err := s.db.QueryRow(query, {{range .Fields}}req.{{.Pascal}}, {{end}}now, now).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create {{.DomainLower}}: %w", err)
+	}
+
+	{{$item}} := &models.{{.DomainTitle}}{
+		ID:        id,
+{{- range .Fields}}
+		{{.Pascal}}: req.{{.Pascal}},
+{{- end}}
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	return {{$item}}, nil
+}
+
+// Update updates a {{.DomainLower}}
+func (s *{{.DomainTitle}}Service) Update(id int, req *models.Update{{.DomainTitle}}Request) (*models.{{.DomainTitle}}, error) {
+	// Check if {{.DomainLower}} exists
+	existing, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update fields present in the request
+{{- range .Fields}}
+	if req.{{.Pascal}} != nil {
+		existing.{{.Pascal}} = {{if .Optional}}req.{{.Pascal}}{{else}}*req.{{.Pascal}}{{end}}
+	}
+{{- end}}
+	existing.UpdatedAt = time.Now()
+
+	query := `UPDATE {{$table}} SET {{.Fields.Assignments 1}}, updated_at = ${{.Fields.Next 1}} WHERE id = ${{.Fields.Next 2}}`
+	_, err = s.db.Exec(query, {{range .Fields}}existing.{{.Pascal}}, {{end}}existing.UpdatedAt, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update {{.DomainLower}}: %w", err)
+	}
+
+	return existing, nil
+}
+
+// Delete deletes a {{.DomainLower}}
+func (s *{{.DomainTitle}}Service) Delete(id int) error {
+	// Check if {{.DomainLower}} exists
+	_, err := s.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	query := `DELETE FROM {{$table}} WHERE id = $1`
+	_, err = s.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete {{.DomainLower}}: %w", err)
+	}
+
+	return nil
+}
+{{- end}}
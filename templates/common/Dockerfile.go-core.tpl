@@ -1,4 +1,4 @@
-# Multi-stage build for Go Fiber application
+# Multi-stage build for Go {{template "go/framework"}} application
 FROM golang:1.25.1-alpine AS builder
 
 # Install git and ca-certificates (needed for go mod download)
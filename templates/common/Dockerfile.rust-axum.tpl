@@ -0,0 +1,53 @@
+# syntax=docker/dockerfile:1
+
+# Build stage
+FROM rust:1.89-slim-bookworm AS build
+WORKDIR /app
+{{- if .WithGRPC}}
+
+# protoc is required by tonic-build (see build.rs)
+RUN apt-get update && apt-get install -y --no-install-recommends protobuf-compiler \
+    && rm -rf /var/lib/apt/lists/*
+{{- end}}
+
+# Copy the manifest and sources
+COPY Cargo.toml ./
+{{- if .WithGRPC}}
+COPY build.rs ./
+COPY proto ./proto
+{{- end}}
+COPY src ./src
+
+# Build in release mode
+RUN cargo build --release
+
+# Production stage
+FROM debian:bookworm-slim AS runtime
+WORKDIR /app
+
+# Install runtime dependencies (ca-certificates, curl for the health check)
+RUN apt-get update && apt-get install -y --no-install-recommends \
+    ca-certificates curl \
+    && rm -rf /var/lib/apt/lists/*
+
+# Copy the binary
+COPY --from=build /app/target/release/{{.ProjectName}} /app/{{.ProjectName}}
+
+# Non-root user
+RUN useradd -m axum && chown -R axum:axum /app
+USER axum
+
+# Expose ports
+ENV PORT={{.Port}}
+EXPOSE {{.Port}}
+{{- if .WithGRPC}}
+ENV GRPC_PORT=50051
+EXPOSE 50051
+{{- end}}
+
+# Health check
+HEALTHCHECK --interval=30s --timeout=3s --start-period=5s --retries=3 \
+  CMD curl -f http://127.0.0.1:{{.Port}}/health || exit 1
+
+# Run
+ENTRYPOINT ["/app/{{.ProjectName}}"]
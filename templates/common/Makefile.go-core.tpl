@@ -1,6 +1,6 @@
-# Makefile for {{.ProjectName}} (Go Fiber)
+# Makefile for {{.ProjectName}} (Go {{template "go/framework"}})
 
-.PHONY: help build run test clean docker-build docker-run docker-push fmt vet lint deps
+.PHONY: help build run test clean docker-build docker-run docker-push fmt vet lint deps{{if .Proto}} proto{{end}}
 
 # Variables
 APP_NAME={{.ProjectName}}
@@ -39,6 +39,16 @@ test-coverage: ## Run tests with coverage
 	go test -v -coverprofile=coverage.out ./...
 	go tool cover -html=coverage.out -o coverage.html
 
+{{if .Proto -}}
+# gRPC
+proto: ## Generate the {{.ModulePath}}/proto package from proto/{{.Proto.File}} and tidy go.mod (requires protoc, protoc-gen-go and protoc-gen-go-grpc)
+	protoc -I proto \
+		--go_out=proto --go_opt=paths=source_relative,M{{.Proto.File}}={{.ModulePath}}/proto \
+		--go-grpc_out=proto --go-grpc_opt=paths=source_relative,M{{.Proto.File}}={{.ModulePath}}/proto \
+		proto/{{.Proto.File}}
+	go mod tidy
+
+{{end -}}
 # Build
 build: ## Build the application
 	go build -o bin/$(APP_NAME) .
@@ -93,6 +103,10 @@ clean-all: clean ## Clean everything including Docker images
 install-tools: ## Install development tools
 	go install github.com/cosmtrek/air@latest
 	go install github.com/golangci/golangci-lint/cmd/golangci-lint@latest
+{{- if .Proto}}
+	go install google.golang.org/protobuf/cmd/protoc-gen-go@latest
+	go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@latest
+{{- end}}
 
 # Production
 prod-build: ## Build for production
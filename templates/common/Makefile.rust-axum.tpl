@@ -0,0 +1,58 @@
+# Makefile for {{.ProjectName}} (Rust Axum)
+
+.PHONY: help build build-release run test fmt lint clean docker-build docker-run docker-run-detached
+
+APP_NAME={{.ProjectName}}
+VERSION?=latest
+DOCKER_IMAGE={{.ProjectName}}:$(VERSION)
+{{- if .GCPProject}}
+GCP_PROJECT={{.GCPProject}}
+DOCKER_REGISTRY=gcr.io/$(GCP_PROJECT)
+{{- end}}
+
+help:
+	@echo 'Usage: make [target]'
+	@echo ''
+	@echo 'Targets:'
+	@awk 'BEGIN {FS = ":.*?## "} /^[a-zA-Z_-]+:.*?## / {printf "  %-20s %s\n", $$1, $$2}' $(MAKEFILE_LIST)
+
+# Development
+build: ## Build the app (debug)
+	cargo build
+
+build-release: ## Build the app (release)
+	cargo build --release
+
+run: ## Run the app
+	PORT={{.Port}} cargo run
+
+dev: ## Run with auto-reload using cargo-watch if installed
+	@which cargo-watch >/dev/null 2>&1 && cargo watch -x run || echo "cargo-watch not installed"
+
+test: ## Run tests
+	cargo test
+
+fmt: ## Format Rust code
+	cargo fmt
+
+lint: ## Run clippy
+	cargo clippy -- -D warnings
+
+# Docker
+docker-build: ## Build Docker image
+	docker build -t $(DOCKER_IMAGE) .
+
+docker-run: ## Run Docker container
+	docker run -p {{.Port}}:{{.Port}} {{- if .WithGRPC}} -p 50051:50051{{- end}} $(DOCKER_IMAGE)
+
+docker-run-detached: ## Run Docker container in background
+	docker run -d -p {{.Port}}:{{.Port}} {{- if .WithGRPC}} -p 50051:50051{{- end}} $(DOCKER_IMAGE)
+
+{{- if .GCPProject}}
+docker-push: ## Push Docker image to GCR
+	docker tag $(DOCKER_IMAGE) $(DOCKER_REGISTRY)/$(DOCKER_IMAGE)
+	docker push $(DOCKER_REGISTRY)/$(DOCKER_IMAGE)
+{{- end}}
+
+clean: ## Clean build artifacts
+	cargo clean
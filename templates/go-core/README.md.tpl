@@ -1,12 +1,12 @@
 # {{.ProjectName}}
 
-A Go CRUD API built with Fiber framework.
+A Go CRUD API built with {{template "go/framework"}} framework.
 
 ## Features
 
-- ✅ REST API with Fiber
+- ✅ REST API with {{template "go/framework"}}
 - ✅ PostgreSQL database
-- ✅ CRUD operations for {{.DomainLower}}s
+- ✅ CRUD operations for {{range $i, $domain := .Domains}}{{if $i}}, {{end}}{{pluralize .DomainLower}}{{end}}
 {{- if .WithGRPC}}
 - ✅ gRPC support
 {{- end}}
@@ -30,6 +30,9 @@ A Go CRUD API built with Fiber framework.
 
 2. **Install dependencies**
    ```bash
+{{- if .Proto}}
+   make proto   # generates the proto package from proto/{{.Proto.File}} (requires protoc)
+{{- end}}
    go mod tidy
    ```
 
@@ -51,31 +54,31 @@ The gRPC server will be available at `localhost:50051`.
 {{- end}}
 
 ## API Endpoints
-
+{{range .Domains}}
 ### {{.DomainTitle}} Management
-- `GET /api/v1/{{.DomainLower}}s` - Get all {{.DomainLower}}s
-- `GET /api/v1/{{.DomainLower}}s/:id` - Get {{.DomainLower}} by ID
-- `POST /api/v1/{{.DomainLower}}s` - Create new {{.DomainLower}}
-- `PUT /api/v1/{{.DomainLower}}s/:id` - Update {{.DomainLower}}
-- `DELETE /api/v1/{{.DomainLower}}s/:id` - Delete {{.DomainLower}}
-
+- `GET /api/v1/{{.DomainName | kebab | pluralize}}` - Get all {{pluralize .DomainLower}}
+- `GET /api/v1/{{.DomainName | kebab | pluralize}}/:id` - Get {{.DomainLower}} by ID
+- `POST /api/v1/{{.DomainName | kebab | pluralize}}` - Create new {{.DomainLower}}
+- `PUT /api/v1/{{.DomainName | kebab | pluralize}}/:id` - Update {{.DomainLower}}
+- `DELETE /api/v1/{{.DomainName | kebab | pluralize}}/:id` - Delete {{.DomainLower}}
+{{end}}
 ### Health Check
 - `GET /health` - Health check endpoint
 
 ## Example Requests
-
+{{range .Domains}}
 ### Create {{.DomainLower}}
 ```bash
-curl -X POST http://localhost:{{.Port}}/api/v1/{{.DomainLower}}s \
+curl -X POST http://localhost:{{$.Port}}/api/v1/{{.DomainName | kebab | pluralize}} \
   -H "Content-Type: application/json" \
-  -d '{"name": "Example {{.DomainTitle}}", "description": "This is an example"}'
+  -d '{{.Fields.ExampleJSON}}'
 ```
 
-### Get all {{.DomainLower}}s
+### Get all {{pluralize .DomainLower}}
 ```bash
-curl http://localhost:{{.Port}}/api/v1/{{.DomainLower}}s
+curl http://localhost:{{$.Port}}/api/v1/{{.DomainName | kebab | pluralize}}
 ```
-
+{{end}}
 ## Environment Variables
 
 | Variable | Description | Default |
@@ -85,6 +88,7 @@ curl http://localhost:{{.Port}}/api/v1/{{.DomainLower}}s
 | GRPC_PORT | gRPC server port | 50051 |
 {{- end}}
 | DATABASE_URL | PostgreSQL connection string | postgres://localhost/{{.ProjectName}}_dev?sslmode=disable |
+{{- template "go/env-docs" .}}
 {{- if .GCPProject}}
 | GCP_PROJECT | GCP project ID for metrics | {{.GCPProject}} |
 {{- end}}
@@ -158,4 +162,4 @@ make docker-run     # Run Docker container
 
 ## License
 
-This project is licensed under the MIT License - see the LICENSE file for details.
+This project is licensed under the {{or .License "MIT"}} License - see the LICENSE file for details.
@@ -0,0 +1,5 @@
+{{/* Framework hooks of the Go core. Sets extending go-core redefine them in
+   a partial of their own, e.g. _partials/go/gin.tpl. */}}
+{{define "go/framework"}}Go{{end}}
+{{define "go/validate-tag"}}validate{{end}}
+{{define "go/env-docs"}}{{end}}
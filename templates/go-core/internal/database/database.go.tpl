@@ -34,15 +34,20 @@ func Initialize(databaseURL string) (*DB, error) {
 // createTables creates the necessary tables
 func createTables(db *sql.DB) error {
 	query := `
-	CREATE TABLE IF NOT EXISTS {{.DomainLower}}s (
-		id SERIAL PRIMARY KEY,
-		name VARCHAR(255) NOT NULL,
-		description TEXT,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);
+{{- range .Domains}}{{template "tables" ($.ForDomain .)}}{{end}}
+	-- ccin:tables
 	`
 
 	_, err := db.Exec(query)
 	return err
 }
+{{- define "tables"}}
+	CREATE TABLE IF NOT EXISTS {{.DomainName | snake | pluralize}} (
+		id SERIAL PRIMARY KEY,
+{{- range .Fields}}
+		{{.SQLColumn}},
+{{- end}}
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+{{- end}}
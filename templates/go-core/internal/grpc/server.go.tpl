@@ -0,0 +1,82 @@
+package grpc
+
+import (
+{{- if and .Proto .Proto.HasUnary}}
+	"context"
+{{- end}}
+	"database/sql"
+	"fmt"
+	"log"
+	"net"
+{{if or (not .Proto) .Proto.ServesDomains}}
+	"{{.ModulePath}}/internal/services"
+{{- end}}
+	pb "{{.ModulePath}}/proto"
+
+	"google.golang.org/grpc"
+{{- if .Proto}}
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+{{- if .Proto.UsesEmpty}}
+	"google.golang.org/protobuf/types/known/emptypb"
+{{- end}}
+{{- end}}
+)
+{{if .Proto}}{{range .Proto.Services}}{{template "grpc-service" .}}{{end}}{{else}}{{range .Domains}}{{template "grpc-servers" ($.ForDomain .)}}{{end}}{{end}}
+// ccin:grpc-servers
+
+// StartServer starts the gRPC server
+func StartServer(port string, db *sql.DB) error {
+	lis, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return fmt.Errorf("failed to listen on port %s: %w", port, err)
+	}
+
+	s := grpc.NewServer()
+{{- if .Proto}}{{range .Proto.Services}}{{template "grpc-service-register" .}}{{end}}{{else}}{{range .Domains}}{{template "grpc-register" ($.ForDomain .)}}{{end}}{{end}}
+	// ccin:grpc-register
+
+	log.Printf("gRPC server listening on port %s", port)
+	return s.Serve(lis)
+}
+{{- define "grpc-servers"}}{{if not .Proto}}
+// {{.DomainLower}}Server implements the {{.DomainTitle}} gRPC service
+type {{.DomainLower}}Server struct {
+	pb.Unimplemented{{.DomainTitle}}ServiceServer
+	service *services.{{.DomainTitle}}Service
+}
+{{end}}{{end}}
+{{- define "grpc-register"}}{{if not .Proto}}
+	pb.Register{{.DomainTitle}}ServiceServer(s, &{{.DomainLower}}Server{service: services.New{{.DomainTitle}}Service(db)})
+{{- end}}{{end}}
+{{- define "grpc-service"}}{{$service := .}}
+// {{camel .Name}}Server implements the {{.Name}} service of the proto contract
+type {{camel .Name}}Server struct {
+	pb.Unimplemented{{.Name}}Server
+{{- range .Domains}}
+	{{goIdent (camel .)}} *services.{{pascal .}}Service
+{{- end}}
+}
+{{range .RPCs}}
+// {{.Name}} serves the {{.Name}} rpc
+{{- if not .Streaming}}
+func (s *{{camel $service.Name}}Server) {{.Name}}(ctx context.Context, req *{{template "grpc-message" .Input}}) (*{{template "grpc-message" .Output}}, error) {
+{{- else if not .ClientStreaming}}
+func (s *{{camel $service.Name}}Server) {{.Name}}(req *{{template "grpc-message" .Input}}, stream pb.{{$service.Name}}_{{.Name}}Server) error {
+{{- else}}
+func (s *{{camel $service.Name}}Server) {{.Name}}(stream pb.{{$service.Name}}_{{.Name}}Server) error {
+{{- end}}
+{{- if .Domain}}
+	// TODO: map the request to s.{{goIdent (camel .Domain)}} and its result to the response
+{{- else}}
+	// TODO: implement the rpc
+{{- end}}
+	return {{if not .Streaming}}nil, {{end}}status.Errorf(codes.Unimplemented, "method {{.Name}} not implemented")
+}
+{{end}}{{end}}
+{{- define "grpc-service-register"}}
+	pb.Register{{.Name}}Server(s, &{{camel .Name}}Server{
+{{- range $i, $domain := .Domains}}{{if $i}}, {{end}}{{goIdent (camel $domain)}}: services.New{{pascal $domain}}Service(db){{end -}}
+})
+{{- end}}
+{{- define "grpc-message"}}{{if eq . "google.protobuf.Empty"}}emptypb.Empty{{else}}pb.{{.}}{{end}}{{end}}
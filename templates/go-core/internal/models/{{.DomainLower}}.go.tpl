@@ -0,0 +1 @@
+{{template "go/model" .}}
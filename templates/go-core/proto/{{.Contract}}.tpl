@@ -0,0 +1 @@
+{{.Proto.Source}}
\ No newline at end of file
@@ -1 +0,0 @@
-../common/Dockerfile.go-fiber.tpl
\ No newline at end of file
@@ -1 +0,0 @@
-../common/Makefile.go-fiber.tpl
\ No newline at end of file
@@ -0,0 +1,2 @@
+{{/* Go core hooks for Fiber, see go-core/_partials/go/framework.tpl */}}
+{{define "go/framework"}}Fiber{{end}}
@@ -3,9 +3,9 @@ package api
 import (
 	"database/sql"
 
-	"{{.ProjectName}}/internal/handlers"
-	"{{.ProjectName}}/internal/middleware"
-	"{{.ProjectName}}/internal/services"
+	"{{.ModulePath}}/internal/handlers"
+	"{{.ModulePath}}/internal/middleware"
+	"{{.ModulePath}}/internal/services"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/logger"
@@ -30,17 +30,36 @@ func SetupRoutes(app *fiber.App, db *sql.DB) {
 		})
 	})
 
+{{- if .Contract}}
+
+	// API routes of {{.Contract}}
+{{- else}}
+
 	// API v1 routes
 	v1 := app.Group("/api/v1")
-
+{{- end}}
+{{range .Domains}}{{template "routes" ($.ForDomain .)}}{{end}}
+	// ccin:routes
+}
+{{- define "routes"}}
+{{- if .Operations}}
+	// {{.DomainTitle}} routes
+	{{.DomainLower}}Service := services.New{{.DomainTitle}}Service(db)
+	{{.DomainLower}}Handler := handlers.New{{.DomainTitle}}Handler({{.DomainLower}}Service)
+{{$domain := .}}
+{{- range .Operations}}
+	app.{{.Method | pascal}}("{{.ColonPath}}", {{$domain.DomainLower}}Handler.{{.Name}})
+{{- end}}
+{{else}}
 	// {{.DomainTitle}} routes
 	{{.DomainLower}}Service := services.New{{.DomainTitle}}Service(db)
 	{{.DomainLower}}Handler := handlers.New{{.DomainTitle}}Handler({{.DomainLower}}Service)
 
-	{{.DomainLower}}Routes := v1.Group("/{{.DomainLower}}s")
+	{{.DomainLower}}Routes := v1.Group("/{{.DomainName | kebab | pluralize}}")
 	{{.DomainLower}}Routes.Get("/", {{.DomainLower}}Handler.GetAll)
 	{{.DomainLower}}Routes.Get("/:id", {{.DomainLower}}Handler.GetByID)
 	{{.DomainLower}}Routes.Post("/", {{.DomainLower}}Handler.Create)
 	{{.DomainLower}}Routes.Put("/:id", {{.DomainLower}}Handler.Update)
 	{{.DomainLower}}Routes.Delete("/:id", {{.DomainLower}}Handler.Delete)
-}
+{{end}}
+{{- end}}
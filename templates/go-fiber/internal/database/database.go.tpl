@@ -1,48 +0,0 @@
-package database
-
-import (
-	"database/sql"
-	"fmt"
-
-	_ "github.com/lib/pq"
-)
-
-// DB represents the database connection
-type DB struct {
-	*sql.DB
-}
-
-// Initialize initializes the database connection
-func Initialize(databaseURL string) (*DB, error) {
-	db, err := sql.Open("postgres", databaseURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
-	}
-
-	if err := db.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
-	}
-
-	// Create tables if they don't exist
-	if err := createTables(db); err != nil {
-		return nil, fmt.Errorf("failed to create tables: %w", err)
-	}
-
-	return &DB{db}, nil
-}
-
-// createTables creates the necessary tables
-func createTables(db *sql.DB) error {
-	query := `
-	CREATE TABLE IF NOT EXISTS {{.DomainLower}}s (
-		id SERIAL PRIMARY KEY,
-		name VARCHAR(255) NOT NULL,
-		description TEXT,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);
-	`
-
-	_, err := db.Exec(query)
-	return err
-}
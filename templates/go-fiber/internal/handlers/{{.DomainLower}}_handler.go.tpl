@@ -1,15 +1,125 @@
+{{- define "handlers"}}
+{{- $item := .DomainName | camel | goIdent}}
+{{- $items := .DomainName | camel | pluralize | goIdent -}}
 package handlers
 
 import (
+{{- if .Operations.Has "get" "update" "delete"}}
 	"strconv"
+{{end}}
+{{- if .Operations.Has "create" "update"}}
+	"{{.ModulePath}}/internal/models"
+{{- end}}
+	"{{.ModulePath}}/internal/services"
 
-	"{{.ProjectName}}/internal/models"
-	"{{.ProjectName}}/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// {{.DomainTitle}}Handler handles HTTP requests for {{pluralize .DomainLower}} as {{.Contract}} declares them
+type {{.DomainTitle}}Handler struct {
+	service *services.{{.DomainTitle}}Service
+}
+
+// New{{.DomainTitle}}Handler creates a new {{.DomainLower}} handler
+func New{{.DomainTitle}}Handler(service *services.{{.DomainTitle}}Service) *{{.DomainTitle}}Handler {
+	return &{{.DomainTitle}}Handler{service: service}
+}
+{{- range .Operations}}
+
+// {{.Name}} handles {{.Method}} {{.Path}}{{with .Summary}} ({{.}}){{end}}
+func (h *{{$.DomainTitle}}Handler) {{.Name}}(c *fiber.Ctx) error {
+{{- if eq .Action "list"}}
+	{{$items}}, err := h.service.GetAll()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status({{.Status}}).JSON({{$items}})
+{{- else if eq .Action "custom"}}
+	// TODO: implement with h.service
+	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
+		"error": "{{.Name}} is not implemented",
+	})
+{{- else}}
+{{- if ne .Action "create"}}
+	id, err := strconv.Atoi(c.Params("{{.Param}}"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid ID",
+		})
+	}
+{{end}}
+{{- if .Request}}
+	var req models.{{if eq .Action "create"}}Create{{else}}Update{{end}}{{$.DomainTitle}}Request
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+{{end}}
+{{- if eq .Action "get"}}
+	{{$item}}, err := h.service.GetByID(id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+{{- else if eq .Action "create"}}
+{{- if .Response}}
+	{{$item}}, err := h.service.Create(&req)
+	if err != nil {
+{{- else}}
+	if _, err := h.service.Create(&req); err != nil {
+{{- end}}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+{{- else if eq .Action "update"}}
+{{- if .Response}}
+	{{$item}}, err := h.service.Update(id, &req)
+	if err != nil {
+{{- else}}
+	if _, err := h.service.Update(id, &req); err != nil {
+{{- end}}
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+{{- else if eq .Action "delete"}}
+	if err := h.service.Delete(id); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+{{- end}}
+{{if .Response}}
+	return c.Status({{.Status}}).JSON({{$item}})
+{{- else}}
+	return c.SendStatus({{.Status}})
+{{- end}}
+{{- end}}
+}
+{{- end}}
+{{end}}
+{{- if .Operations}}{{template "handlers" .}}{{else}}
+{{- $item := .DomainName | camel | goIdent}}
+{{- $items := .DomainName | camel | pluralize | goIdent}}
+{{- $route := .DomainName | kebab | pluralize -}}
+package handlers
+
+import (
+	"strconv"
+
+	"{{.ModulePath}}/internal/models"
+	"{{.ModulePath}}/internal/services"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// {{.DomainTitle}}Handler handles HTTP requests for {{.DomainLower}}s
+// {{.DomainTitle}}Handler handles HTTP requests for {{pluralize .DomainLower}}
 type {{.DomainTitle}}Handler struct {
 	service *services.{{.DomainTitle}}Service
 }
@@ -19,9 +129,9 @@ func New{{.DomainTitle}}Handler(service *services.{{.DomainTitle}}Service) *{{.D
 	return &{{.DomainTitle}}Handler{service: service}
 }
 
-// GetAll handles GET /{{.DomainLower}}s
+// GetAll handles GET /{{$route}}
 func (h *{{.DomainTitle}}Handler) GetAll(c *fiber.Ctx) error {
-	{{.DomainLower}}s, err := h.service.GetAll()
+	{{$items}}, err := h.service.GetAll()
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -29,11 +139,11 @@ func (h *{{.DomainTitle}}Handler) GetAll(c *fiber.Ctx) error {
 	}
 
 	return c.JSON(fiber.Map{
-		"data": {{.DomainLower}}s,
+		"data": {{$items}},
 	})
 }
 
-// GetByID handles GET /{{.DomainLower}}s/:id
+// GetByID handles GET /{{$route}}/:id
 func (h *{{.DomainTitle}}Handler) GetByID(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
@@ -42,7 +152,7 @@ func (h *{{.DomainTitle}}Handler) GetByID(c *fiber.Ctx) error {
 		})
 	}
 
-	{{.DomainLower}}, err := h.service.GetByID(id)
+	{{$item}}, err := h.service.GetByID(id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": err.Error(),
@@ -50,11 +160,11 @@ func (h *{{.DomainTitle}}Handler) GetByID(c *fiber.Ctx) error {
 	}
 
 	return c.JSON(fiber.Map{
-		"data": {{.DomainLower}},
+		"data": {{$item}},
 	})
 }
 
-// Create handles POST /{{.DomainLower}}s
+// Create handles POST /{{$route}}
 func (h *{{.DomainTitle}}Handler) Create(c *fiber.Ctx) error {
 	var req models.Create{{.DomainTitle}}Request
 	if err := c.BodyParser(&req); err != nil {
@@ -64,13 +174,15 @@ func (h *{{.DomainTitle}}Handler) Create(c *fiber.Ctx) error {
 	}
 
 	// Basic validation
-	if req.Name == "" {
+{{- range .Fields}}{{if and .IsString (not .Optional)}}
+	if req.{{.Pascal}} == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Name is required",
+			"error": "{{.Snake}} is required",
 		})
 	}
+{{- end}}{{end}}
 
-	{{.DomainLower}}, err := h.service.Create(&req)
+	{{$item}}, err := h.service.Create(&req)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -78,11 +190,11 @@ func (h *{{.DomainTitle}}Handler) Create(c *fiber.Ctx) error {
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"data": {{.DomainLower}},
+		"data": {{$item}},
 	})
 }
 
-// Update handles PUT /{{.DomainLower}}s/:id
+// Update handles PUT /{{$route}}/:id
 func (h *{{.DomainTitle}}Handler) Update(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
@@ -98,7 +210,7 @@ func (h *{{.DomainTitle}}Handler) Update(c *fiber.Ctx) error {
 		})
 	}
 
-	{{.DomainLower}}, err := h.service.Update(id, &req)
+	{{$item}}, err := h.service.Update(id, &req)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": err.Error(),
@@ -106,11 +218,11 @@ func (h *{{.DomainTitle}}Handler) Update(c *fiber.Ctx) error {
 	}
 
 	return c.JSON(fiber.Map{
-		"data": {{.DomainLower}},
+		"data": {{$item}},
 	})
 }
 
-// Delete handles DELETE /{{.DomainLower}}s/:id
+// Delete handles DELETE /{{$route}}/:id
 func (h *{{.DomainTitle}}Handler) Delete(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
@@ -129,3 +241,4 @@ func (h *{{.DomainTitle}}Handler) Delete(c *fiber.Ctx) error {
 		"message": "{{.DomainTitle}} deleted successfully",
 	})
 }
+{{end}}
\ No newline at end of file
@@ -5,14 +5,14 @@ import (
 	"log"
 	"os"
 
-	"{{.ProjectName}}/internal/api"
-	"{{.ProjectName}}/internal/config"
-	"{{.ProjectName}}/internal/database"
+	"{{.ModulePath}}/internal/api"
+	"{{.ModulePath}}/internal/config"
+	"{{.ModulePath}}/internal/database"
 	{{- if .WithGRPC}}
-	"{{.ProjectName}}/internal/grpc"
+	"{{.ModulePath}}/internal/grpc"
 	{{- end}}
 	{{- if .GCPProject}}
-	"{{.ProjectName}}/internal/metrics"
+	"{{.ModulePath}}/internal/metrics"
 	{{- end}}
 	
 	"github.com/gofiber/fiber/v2"
@@ -1 +0,0 @@
-../common/Dockerfile.go-gin.tpl
\ No newline at end of file
@@ -1 +0,0 @@
-../common/Makefile.go-gin.tpl
\ No newline at end of file
@@ -0,0 +1,6 @@
+{{/* Go core hooks for Gin, see go-core/_partials/go/framework.tpl */}}
+{{define "go/framework"}}Gin{{end}}
+{{define "go/validate-tag"}}binding{{end}}
+{{define "go/env-docs"}}
+| GIN_MODE | Gin mode (debug/release) | debug |
+{{- end}}
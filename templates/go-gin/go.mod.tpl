@@ -1,4 +1,4 @@
-module {{.ProjectName}}
+module {{.ModulePath}}
 
 go 1.25.1
 
@@ -3,9 +3,9 @@ package api
 import (
 	"database/sql"
 
-	"{{.ProjectName}}/internal/handlers"
-	"{{.ProjectName}}/internal/middleware"
-	"{{.ProjectName}}/internal/services"
+	"{{.ModulePath}}/internal/handlers"
+	"{{.ModulePath}}/internal/middleware"
+	"{{.ModulePath}}/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
@@ -24,14 +24,35 @@ func SetupRoutes(router *gin.Engine, db *sql.DB) {
 		c.JSON(200, gin.H{"status": "healthy"})
 	})
 
+{{- if .Contract}}
+
+	// API routes of {{.Contract}}
+	{
+{{- else}}
+
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
+{{- end}}
+{{- range .Domains}}{{template "routes" ($.ForDomain .)}}{{end}}
+		// ccin:routes
+	}
+}
+{{- define "routes"}}
+{{- if .Operations}}
+		// {{.DomainTitle}} routes
+		{{.DomainLower}}Service := services.New{{.DomainTitle}}Service(db)
+		{{.DomainLower}}Handler := handlers.New{{.DomainTitle}}Handler({{.DomainLower}}Service)
+{{$domain := .}}
+{{- range .Operations}}
+		router.{{.Method}}("{{.ColonPath}}", {{$domain.DomainLower}}Handler.{{.Name}})
+{{- end}}
+{{else}}
 		// {{.DomainTitle}} routes
 		{{.DomainLower}}Service := services.New{{.DomainTitle}}Service(db)
 		{{.DomainLower}}Handler := handlers.New{{.DomainTitle}}Handler({{.DomainLower}}Service)
 
-		{{.DomainLower}}Routes := v1.Group("/{{.DomainLower}}s")
+		{{.DomainLower}}Routes := v1.Group("/{{.DomainName | kebab | pluralize}}")
 		{
 			{{.DomainLower}}Routes.GET("", {{.DomainLower}}Handler.GetAll)
 			{{.DomainLower}}Routes.GET("/:id", {{.DomainLower}}Handler.GetByID)
@@ -39,5 +60,5 @@ func SetupRoutes(router *gin.Engine, db *sql.DB) {
 			{{.DomainLower}}Routes.PUT("/:id", {{.DomainLower}}Handler.Update)
 			{{.DomainLower}}Routes.DELETE("/:id", {{.DomainLower}}Handler.Delete)
 		}
-	}
-}
+{{end}}
+{{- end}}
@@ -1,16 +1,118 @@
+{{- define "handlers"}}
+{{- $item := .DomainName | camel | goIdent}}
+{{- $items := .DomainName | camel | pluralize | goIdent -}}
 package handlers
 
 import (
 	"net/http"
+{{- if .Operations.Has "get" "update" "delete"}}
 	"strconv"
+{{- end}}
+{{if .Operations.Has "create" "update"}}
+	"{{.ModulePath}}/internal/models"
+{{- end}}
+	"{{.ModulePath}}/internal/services"
 
-	"{{.ProjectName}}/internal/models"
-	"{{.ProjectName}}/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// {{.DomainTitle}}Handler handles HTTP requests for {{pluralize .DomainLower}} as {{.Contract}} declares them
+type {{.DomainTitle}}Handler struct {
+	service *services.{{.DomainTitle}}Service
+}
+
+// New{{.DomainTitle}}Handler creates a new {{.DomainLower}} handler
+func New{{.DomainTitle}}Handler(service *services.{{.DomainTitle}}Service) *{{.DomainTitle}}Handler {
+	return &{{.DomainTitle}}Handler{service: service}
+}
+{{- range .Operations}}
+
+// {{.Name}} handles {{.Method}} {{.Path}}{{with .Summary}} ({{.}}){{end}}
+func (h *{{$.DomainTitle}}Handler) {{.Name}}(c *gin.Context) {
+{{- if eq .Action "list"}}
+	{{$items}}, err := h.service.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON({{.Status}}, {{$items}})
+{{- else if eq .Action "custom"}}
+	// TODO: implement with h.service
+	c.JSON(http.StatusNotImplemented, gin.H{"error": "{{.Name}} is not implemented"})
+{{- else}}
+{{- if ne .Action "create"}}
+	id, err := strconv.Atoi(c.Param("{{.Param}}"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+{{end}}
+{{- if .Request}}
+	var req models.{{if eq .Action "create"}}Create{{else}}Update{{end}}{{$.DomainTitle}}Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+{{end}}
+{{- if eq .Action "get"}}
+	{{$item}}, err := h.service.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+{{- else if eq .Action "create"}}
+{{- if .Response}}
+	{{$item}}, err := h.service.Create(&req)
+	if err != nil {
+{{- else}}
+	if _, err := h.service.Create(&req); err != nil {
+{{- end}}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+{{- else if eq .Action "update"}}
+{{- if .Response}}
+	{{$item}}, err := h.service.Update(id, &req)
+	if err != nil {
+{{- else}}
+	if _, err := h.service.Update(id, &req); err != nil {
+{{- end}}
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+{{- else if eq .Action "delete"}}
+	if err := h.service.Delete(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+{{- end}}
+{{if .Response}}
+	c.JSON({{.Status}}, {{$item}})
+{{- else}}
+	c.Status({{.Status}})
+{{- end}}
+{{- end}}
+}
+{{- end}}
+{{end}}
+{{- if .Operations}}{{template "handlers" .}}{{else}}
+{{- $item := .DomainName | camel | goIdent}}
+{{- $items := .DomainName | camel | pluralize | goIdent}}
+{{- $route := .DomainName | kebab | pluralize -}}
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"{{.ModulePath}}/internal/models"
+	"{{.ModulePath}}/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
-// {{.DomainTitle}}Handler handles HTTP requests for {{.DomainLower}}s
+// {{.DomainTitle}}Handler handles HTTP requests for {{pluralize .DomainLower}}
 type {{.DomainTitle}}Handler struct {
 	service *services.{{.DomainTitle}}Service
 }
@@ -20,18 +122,18 @@ func New{{.DomainTitle}}Handler(service *services.{{.DomainTitle}}Service) *{{.D
 	return &{{.DomainTitle}}Handler{service: service}
 }
 
-// GetAll handles GET /{{.DomainLower}}s
+// GetAll handles GET /{{$route}}
 func (h *{{.DomainTitle}}Handler) GetAll(c *gin.Context) {
-	{{.DomainLower}}s, err := h.service.GetAll()
+	{{$items}}, err := h.service.GetAll()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": {{.DomainLower}}s})
+	c.JSON(http.StatusOK, gin.H{"data": {{$items}}})
 }
 
-// GetByID handles GET /{{.DomainLower}}s/:id
+// GetByID handles GET /{{$route}}/:id
 func (h *{{.DomainTitle}}Handler) GetByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -39,16 +141,16 @@ func (h *{{.DomainTitle}}Handler) GetByID(c *gin.Context) {
 		return
 	}
 
-	{{.DomainLower}}, err := h.service.GetByID(id)
+	{{$item}}, err := h.service.GetByID(id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": {{.DomainLower}}})
+	c.JSON(http.StatusOK, gin.H{"data": {{$item}}})
 }
 
-// Create handles POST /{{.DomainLower}}s
+// Create handles POST /{{$route}}
 func (h *{{.DomainTitle}}Handler) Create(c *gin.Context) {
 	var req models.Create{{.DomainTitle}}Request
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -56,16 +158,16 @@ func (h *{{.DomainTitle}}Handler) Create(c *gin.Context) {
 		return
 	}
 
-	{{.DomainLower}}, err := h.service.Create(&req)
+	{{$item}}, err := h.service.Create(&req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"data": {{.DomainLower}}})
+	c.JSON(http.StatusCreated, gin.H{"data": {{$item}}})
 }
 
-// Update handles PUT /{{.DomainLower}}s/:id
+// Update handles PUT /{{$route}}/:id
 func (h *{{.DomainTitle}}Handler) Update(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -79,16 +181,16 @@ func (h *{{.DomainTitle}}Handler) Update(c *gin.Context) {
 		return
 	}
 
-	{{.DomainLower}}, err := h.service.Update(id, &req)
+	{{$item}}, err := h.service.Update(id, &req)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": {{.DomainLower}}})
+	c.JSON(http.StatusOK, gin.H{"data": {{$item}}})
 }
 
-// Delete handles DELETE /{{.DomainLower}}s/:id
+// Delete handles DELETE /{{$route}}/:id
 func (h *{{.DomainTitle}}Handler) Delete(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -103,3 +205,4 @@ func (h *{{.DomainTitle}}Handler) Delete(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "{{.DomainTitle}} deleted successfully"})
 }
+{{end}}
\ No newline at end of file
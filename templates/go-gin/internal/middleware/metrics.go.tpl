@@ -4,7 +4,7 @@ package middleware
 import (
 	"time"
 
-	"{{.ProjectName}}/internal/metrics"
+	"{{.ModulePath}}/internal/metrics"
 
 	"github.com/gin-gonic/gin"
 )
@@ -1 +0,0 @@
-../common/Dockerfile.nestjs.tpl
\ No newline at end of file
@@ -1 +0,0 @@
-../common/Makefile.nestjs.tpl
\ No newline at end of file
@@ -33,51 +33,61 @@ The API will be available at `http://localhost:{{.Port}}`.
 ### Health Check
 - `GET /health` — returns `OK`
 
+{{- range .Domains}}
+
 ### {{.DomainTitle}} Management
-The RESTful routes are mounted under `/api/v1/{{.DomainLower}}s`.
+The RESTful routes are mounted under `/api/v1/{{.DomainName | kebab | pluralize}}`.
 
-- `GET /api/v1/{{.DomainLower}}s` — List all
-- `GET /api/v1/{{.DomainLower}}s/:id` — Get by ID
-- `POST /api/v1/{{.DomainLower}}s` — Create
-- `PUT /api/v1/{{.DomainLower}}s/:id` — Update by ID
-- `DELETE /api/v1/{{.DomainLower}}s/:id` — Delete by ID
+- `GET /api/v1/{{.DomainName | kebab | pluralize}}` — List all
+- `GET /api/v1/{{.DomainName | kebab | pluralize}}/:id` — Get by ID
+- `POST /api/v1/{{.DomainName | kebab | pluralize}}` — Create
+- `PUT /api/v1/{{.DomainName | kebab | pluralize}}/:id` — Update by ID
+- `DELETE /api/v1/{{.DomainName | kebab | pluralize}}/:id` — Delete by ID
+{{- end}}
 
 ### Example Requests
-
+{{range .Domains}}
 Create {{.DomainLower}}:
 ```bash
-curl -X POST http://localhost:{{.Port}}/api/v1/{{.DomainLower}}s \
+curl -X POST http://localhost:{{$.Port}}/api/v1/{{.DomainName | kebab | pluralize}} \
   -H "Content-Type: application/json" \
-  -d '{"name": "Example {{.DomainTitle}}"}'
+  -d '{ {{- range $i, $f := .Fields}}{{if $i}}, {{end}}"{{.Camel}}": {{.Example}}{{end -}} }'
 ```
 
 List {{.DomainLower}}:
 ```bash
-curl http://localhost:{{.Port}}/api/v1/{{.DomainLower}}s
+curl http://localhost:{{$.Port}}/api/v1/{{.DomainName | kebab | pluralize}}
 ```
-
+{{end}}
 ## Project Structure
 
 ```
 {{.ProjectName}}/
+├── .eslintrc.js                   # ESLint rules (with Prettier)
+├── .prettierrc                    # Prettier formatting
 ├── Dockerfile                     # Multi-stage Docker build
 ├── Makefile                       # Build & run helpers
+├── nest-cli.json                  # Nest CLI configuration
 ├── package.json                   # Scripts and dependencies
+├── tsconfig.json                  # TypeScript compiler options
+├── tsconfig.build.json            # Build-only TypeScript options
 └── src/
     ├── main.ts                    # Entry point
     ├── app.module.ts              # Root module
-    ├── common/
-    │   └── interceptors/
-    │       └── gcp-metrics.interceptor.ts   # (Optional) GCP metrics
-    └── {{.DomainLower}}/
-        ├── dto/
-        │   ├── create-{{.DomainLower}}.dto.ts
-        │   └── update-{{.DomainLower}}.dto.ts
-        ├── entities/
-        │   └── {{.DomainLower}}.entity.ts
-        ├── {{.DomainLower}}.controller.ts
-        ├── {{.DomainLower}}.module.ts
-        └── {{.DomainLower}}.service.ts
+{{- range .Domains}}
+    ├── {{.DomainLower}}/
+    │   ├── dto/
+    │   │   ├── create-{{.DomainLower}}.dto.ts
+    │   │   └── update-{{.DomainLower}}.dto.ts
+    │   ├── entities/
+    │   │   └── {{.DomainLower}}.entity.ts
+    │   ├── {{.DomainLower}}.controller.ts
+    │   ├── {{.DomainLower}}.module.ts
+    │   └── {{.DomainLower}}.service.ts
+{{- end}}
+    └── common/
+        └── interceptors/
+            └── gcp-metrics.interceptor.ts   # (Optional) GCP metrics
 ```
 
 ## Makefile Commands
@@ -109,4 +119,4 @@ docker run -p {{.Port}}:{{.Port}} {{.ProjectName}}
 {{- end}}
 
 ## License
-This project is licensed under the MIT License - see the LICENSE file for details.
+This project is licensed under the {{or .License "MIT"}} License - see the LICENSE file for details.
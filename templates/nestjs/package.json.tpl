@@ -2,9 +2,9 @@
   "name": "{{.ProjectName}}",
   "version": "0.0.1",
   "description": "{{.ProjectName}} - NestJS CRUD API",
-  "author": "",
+  "author": "{{.Author}}",
   "private": true,
-  "license": "UNLICENSED",
+  "license": "{{or .License "UNLICENSED"}}",
   "scripts": {
     "build": "nest build",
     "format": "prettier --write \"src/**/*.ts\" \"test/**/*.ts\"",
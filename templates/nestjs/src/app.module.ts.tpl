@@ -1,11 +1,19 @@
 import { Module } from '@nestjs/common';
 import { MongooseModule } from '@nestjs/mongoose';
-import { {{.DomainTitle}}Module } from './{{.DomainLower}}/{{.DomainLower}}.module';
+{{- range .Domains}}{{template "module-imports" ($.ForDomain .)}}{{end}}
+// ccin:module-imports
 
 @Module({
   imports: [
     MongooseModule.forRoot(process.env.MONGODB_URI || '{{.DatabaseType}}://localhost:27017/{{.ProjectName}}'),
-    {{.DomainTitle}}Module,
+{{- range .Domains}}{{template "modules" ($.ForDomain .)}}{{end}}
+    // ccin:modules
   ],
 })
 export class AppModule {}
+{{- define "module-imports"}}
+import { {{.DomainTitle}}Module } from './{{.DomainLower}}/{{.DomainLower}}.module';
+{{- end}}
+{{- define "modules"}}
+    {{.DomainTitle}}Module,
+{{- end}}
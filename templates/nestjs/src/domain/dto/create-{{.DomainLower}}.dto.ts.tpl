@@ -1,16 +1,17 @@
-import { IsString, IsOptional, IsBoolean } from 'class-validator';
+import { {{.Fields.TSValidatorImports "IsOptional" "IsBoolean"}} } from 'class-validator';
 import { ApiProperty, ApiPropertyOptional } from '@nestjs/swagger';
 
 export class Create{{.DomainTitle}}Dto {
-  @ApiProperty({ description: 'Name of the {{.DomainLower}}' })
-  @IsString()
-  name: string;
-
-  @ApiPropertyOptional({ description: 'Description of the {{.DomainLower}}' })
+{{- range .Fields}}
+  {{- if .Optional}}
+  @ApiPropertyOptional({ description: '{{.Camel}} of the {{$.DomainLower}}' })
   @IsOptional()
-  @IsString()
-  description?: string;
-
+  {{- else}}
+  @ApiProperty({ description: '{{.Camel}} of the {{$.DomainLower}}' })
+  {{- end}}
+  @{{.TSValidator}}()
+  {{.Camel}}{{if .Optional}}?{{end}}: {{.TSDtoType}};
+{{end}}
   @ApiPropertyOptional({ description: 'Is {{.DomainLower}} active', default: true })
   @IsOptional()
   @IsBoolean()
@@ -5,12 +5,10 @@ export type {{.DomainTitle}}Document = {{.DomainTitle}} & Document;
 
 @Schema({ timestamps: true })
 export class {{.DomainTitle}} {
-  @Prop({ required: true })
-  name: string;
-
-  @Prop()
-  description?: string;
-
+{{- range .Fields}}
+  @Prop({{.MongoPropOptions}})
+  {{.Camel}}{{if .Optional}}?{{end}}: {{.TSType}};
+{{end}}
   @Prop({ default: true })
   isActive: boolean;
 
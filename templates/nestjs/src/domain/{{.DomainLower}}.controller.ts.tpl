@@ -1,3 +1,83 @@
+{{- define "controller"}}
+{{- $service := printf "%sService" .DomainLower -}}
+import {
+  Controller,
+{{- range .Operations.Methods}}
+  {{. | pascal}},
+{{- end}}
+{{- if .Operations.Has "create" "update"}}
+  Body,
+{{- end}}
+{{- if .Operations.Has "get" "update" "delete"}}
+  Param,
+{{- end}}
+  HttpCode,
+{{- if .Operations.Has "custom"}}
+  NotImplementedException,
+{{- end}}
+} from '@nestjs/common';
+import { ApiTags, ApiOperation, ApiResponse } from '@nestjs/swagger';
+import { {{.DomainTitle}}Service } from './{{.DomainLower}}.service';
+{{- if .Operations.Has "create"}}
+import { Create{{.DomainTitle}}Dto } from './dto/create-{{.DomainLower}}.dto';
+{{- end}}
+{{- if .Operations.Has "update"}}
+import { Update{{.DomainTitle}}Dto } from './dto/update-{{.DomainLower}}.dto';
+{{- end}}
+
+// Routes of {{pluralize .DomainLower}} as {{.Contract}} declares them
+@ApiTags('{{.DomainLower}}')
+@Controller()
+export class {{.DomainTitle}}Controller {
+  constructor(private readonly {{$service}}: {{.DomainTitle}}Service) {}
+{{- range .Operations}}
+
+  @{{.Method | pascal}}('{{.RelativePath}}')
+  @HttpCode({{.Status}})
+  @ApiOperation({ summary: '{{if .Summary}}{{js .Summary}}{{else}}{{.Name}}{{end}}' })
+  @ApiResponse({ status: {{.Status}}, description: '{{.StatusText}}.' })
+{{- if eq .Action "list"}}
+  {{camel .Name}}() {
+    return this.{{$service}}.findAll();
+  }
+{{- else if eq .Action "get"}}
+  {{camel .Name}}(@Param('{{.Param}}') id: string) {
+    return this.{{$service}}.findOne(id);
+  }
+{{- else if eq .Action "create"}}
+{{- if .Response}}
+  {{camel .Name}}(@Body() create{{$.DomainTitle}}Dto: Create{{$.DomainTitle}}Dto) {
+    return this.{{$service}}.create(create{{$.DomainTitle}}Dto);
+  }
+{{- else}}
+  async {{camel .Name}}(@Body() create{{$.DomainTitle}}Dto: Create{{$.DomainTitle}}Dto) {
+    await this.{{$service}}.create(create{{$.DomainTitle}}Dto);
+  }
+{{- end}}
+{{- else if eq .Action "update"}}
+{{- if .Response}}
+  {{camel .Name}}(@Param('{{.Param}}') id: string, @Body() update{{$.DomainTitle}}Dto: Update{{$.DomainTitle}}Dto) {
+    return this.{{$service}}.update(id, update{{$.DomainTitle}}Dto);
+  }
+{{- else}}
+  async {{camel .Name}}(@Param('{{.Param}}') id: string, @Body() update{{$.DomainTitle}}Dto: Update{{$.DomainTitle}}Dto) {
+    await this.{{$service}}.update(id, update{{$.DomainTitle}}Dto);
+  }
+{{- end}}
+{{- else if eq .Action "delete"}}
+  {{camel .Name}}(@Param('{{.Param}}') id: string) {
+    return this.{{$service}}.remove(id);
+  }
+{{- else}}
+  {{camel .Name}}() {
+    // TODO: implement with this.{{$service}}
+    throw new NotImplementedException('{{.Name}} is not implemented');
+  }
+{{- end}}
+{{- end}}
+}
+{{end}}
+{{- if .Operations}}{{template "controller" .}}{{else -}}
 import {
   Controller,
   Get,
@@ -15,7 +95,7 @@ import { Create{{.DomainTitle}}Dto } from './dto/create-{{.DomainLower}}.dto';
 import { Update{{.DomainTitle}}Dto } from './dto/update-{{.DomainLower}}.dto';
 
 @ApiTags('{{.DomainLower}}')
-@Controller('{{.DomainLower}}')
+@Controller('{{.DomainName | kebab | pluralize}}')
 export class {{.DomainTitle}}Controller {
   constructor(private readonly {{.DomainLower}}Service: {{.DomainTitle}}Service) {}
 
@@ -27,8 +107,8 @@ export class {{.DomainTitle}}Controller {
   }
 
   @Get()
-  @ApiOperation({ summary: 'Get all {{.DomainLower}}s' })
-  @ApiResponse({ status: 200, description: 'Return all {{.DomainLower}}s.' })
+  @ApiOperation({ summary: 'Get all {{pluralize .DomainLower}}' })
+  @ApiResponse({ status: 200, description: 'Return all {{pluralize .DomainLower}}.' })
   findAll() {
     return this.{{.DomainLower}}Service.findAll();
   }
@@ -58,3 +138,4 @@ export class {{.DomainTitle}}Controller {
     return this.{{.DomainLower}}Service.remove(id);
   }
 }
+{{end}}
\ No newline at end of file
@@ -1,3 +1,4 @@
+{{- $item := .DomainName | camel | tsIdent -}}
 import { Injectable, NotFoundException } from '@nestjs/common';
 import { InjectModel } from '@nestjs/mongoose';
 import { Model } from 'mongoose';
@@ -21,11 +22,11 @@ export class {{.DomainTitle}}Service {
   }
 
   async findOne(id: string): Promise<{{.DomainTitle}}> {
-    const {{.DomainLower}} = await this.{{.DomainLower}}Model.findById(id).exec();
-    if (!{{.DomainLower}}) {
+    const {{$item}} = await this.{{.DomainLower}}Model.findById(id).exec();
+    if (!{{$item}}) {
       throw new NotFoundException('{{.DomainTitle}} not found');
     }
-    return {{.DomainLower}};
+    return {{$item}};
   }
 
   async update(id: string, update{{.DomainTitle}}Dto: Update{{.DomainTitle}}Dto): Promise<{{.DomainTitle}}> {
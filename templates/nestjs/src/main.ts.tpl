@@ -22,7 +22,8 @@ async function bootstrap() {
     .setTitle('{{.ProjectName}} API')
     .setDescription('{{.ProjectName}} CRUD API documentation')
     .setVersion('1.0')
-    .addTag('{{.DomainLower}}')
+{{- range .Domains}}{{template "swagger-tags" ($.ForDomain .)}}{{end}}
+    // ccin:swagger-tags
     .build();
   const document = SwaggerModule.createDocument(app, config);
   SwaggerModule.setup('api', app, document);
@@ -32,3 +33,6 @@ async function bootstrap() {
   console.log(`Application is running on: ${port}`);
 }
 bootstrap();
+{{- define "swagger-tags"}}
+    .addTag('{{.DomainLower}}')
+{{- end}}
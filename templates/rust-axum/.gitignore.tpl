@@ -0,0 +1,8 @@
+# Cargo
+/target/
+
+# Env
+.env
+
+# OS
+.DS_Store
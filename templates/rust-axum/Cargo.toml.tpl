@@ -2,6 +2,12 @@
 name = "{{.ProjectName}}"
 version = "0.1.0"
 edition = "2021"
+{{- with .Author}}
+authors = ["{{.}}"]
+{{- end}}
+{{- with .License}}
+license = "{{.}}"
+{{- end}}
 
 [dependencies]
 tokio = { version = "1", features = ["rt-multi-thread", "macros"] }
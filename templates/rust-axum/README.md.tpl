@@ -6,29 +6,77 @@ Clean structure with separation of concerns:
 
 - src/
   - http/: routers, handlers
-  - grpc/: gRPC service ({{ if .WithGRPC }}enabled{{ else }}scaffolded{{ end }})
-  - domain/: models
+{{- if .WithGRPC }}
+  - grpc/: Tonic gRPC service backed by the same service layer
+{{- end }}
+  - core/: domain models
   - services/: business logic
   - middleware/: common layers (tracing, cors)
   - main.rs: boot HTTP {{ if .WithGRPC }}and gRPC {{ end }}servers
-- proto/: Protobuf contracts {{ if .WithGRPC }}(compiled with tonic-build){{ end }}
+{{- if .WithGRPC }}
+- proto/: Protobuf contracts (compiled by build.rs with tonic-build)
+{{- end }}
+- Dockerfile: multi-stage production build
+- Makefile: build, run and Docker helpers
+
+## Prerequisites
+
+- Rust (stable) with Cargo
+{{- if .WithGRPC }}
+- protoc (`brew install protobuf` or `apt-get install protobuf-compiler`) for build.rs
+{{- end }}
 
 ## Run
 
 ```bash
 cargo run
+# or
+make run
 ```
 
+The API will be available at `http://localhost:{{.Port}}`.
+{{- if .WithGRPC }}
+The gRPC server will be available at `localhost:50051`.
+{{- end }}
+
 ## REST Examples
 
 - GET /health
+{{- range .Domains}}
 - GET /api/{{.DomainLower}}
 - POST /api/{{.DomainLower}}
-
-{{ if .WithGRPC }}
+{{- end}}
+{{range .Domains}}
+```bash
+curl -X POST http://localhost:{{$.Port}}/api/{{.DomainLower}} \
+  -H "Content-Type: application/json" \
+  -d '{{.Fields.ExampleJSON}}'
+```
+{{end}}{{ if .WithGRPC }}
 ## gRPC
 
-Proto: proto/{{.DomainLower}}.proto
+Protos: {{range $i, $domain := .Domains}}{{if $i}}, {{end}}proto/{{.DomainLower}}.proto{{end}}
 
 Run server with REST and gRPC concurrently on Tokio runtime.
-{{ end }}
+{{range .Domains}}
+```bash
+grpcurl -plaintext -import-path proto -proto {{.DomainLower}}.proto \
+  localhost:50051 {{.DomainLower}}.{{.DomainTitle}}Service/List
+```
+{{end}}{{ end }}
+## Docker
+
+```bash
+make docker-build
+make docker-run
+```
+
+## Environment
+
+| Variable | Description | Default |
+|----------|-------------|---------|
+| PORT | HTTP server port | {{.Port}} |
+{{- if .WithGRPC }}
+| GRPC_PORT | gRPC server port | 50051 |
+{{- end }}
+| RUST_LOG | Tracing filter | info |
@@ -0,0 +1,11 @@
+// Compiles the proto/ definitions into Rust with tonic-build (requires protoc)
+fn main() -> Result<(), Box<dyn std::error::Error>> {
+{{- if .Proto}}
+    tonic_build::compile_protos("proto/{{.Proto.File}}")?;
+{{- else}}{{range .Domains}}{{template "protos" ($.ForDomain .)}}{{end}}{{end}}
+    // ccin:protos
+    Ok(())
+}
+{{- define "protos"}}{{if not .Proto}}
+    tonic_build::compile_protos("proto/{{.DomainLower}}.proto")?;
+{{- end}}{{end}}
@@ -0,0 +1,24 @@
+syntax = "proto3";
+package {{.DomainLower}};
+
+// {{.DomainTitle}} service exposed over gRPC (mirrors the REST endpoints)
+service {{.DomainTitle}}Service {
+  rpc List(List{{.DomainTitle}}Request) returns (List{{.DomainTitle}}Response);
+  rpc Create(Create{{.DomainTitle}}Request) returns ({{.DomainTitle}}Message);
+}
+
+message {{.DomainTitle}}Message {
+  uint64 id = 1;
+{{- range .Fields}}
+  {{if .Optional}}optional {{end}}{{.ProtoType}} {{.Snake}} = {{.ProtoNumber}};
+{{- end}}
+}
+
+message List{{.DomainTitle}}Request {}
+message List{{.DomainTitle}}Response { repeated {{.DomainTitle}}Message items = 1; }
+
+message Create{{.DomainTitle}}Request {
+{{- range .Fields}}
+  {{if .Optional}}optional {{end}}{{.ProtoType}} {{.Snake}} = {{.Position}};
+{{- end}}
+}
@@ -1 +1,6 @@
-pub mod {{.DomainLower}};
+// Domain modules
+{{- range .Domains}}{{template "modules" ($.ForDomain .)}}{{end}}
+// ccin:modules
+{{- define "modules"}}
+pub mod {{rustIdent .DomainLower}};
+{{- end}}
@@ -3,11 +3,16 @@ use serde::{Serialize, Deserialize};
 #[derive(Clone, Debug, Serialize, Deserialize)]
 pub struct {{.DomainTitle}} {
     pub id: u64,
-    pub name: String,
+{{- range .Fields}}
+    {{- if ne (rustIdent .Snake) .Snake}}
+    #[serde(rename = "{{.Snake}}")]
+    {{- end}}
+    pub {{rustIdent .Snake}}: {{.RustFieldType}},
+{{- end}}
 }
 
 impl {{.DomainTitle}} {
-    pub fn new(id: u64, name: String) -> Self {
-        Self { id, name }
+    pub fn new(id: u64{{range .Fields}}, {{rustIdent .Snake}}: {{.RustFieldType}}{{end}}) -> Self {
+        Self { id{{range .Fields}}, {{rustIdent .Snake}}{{end}} }
     }
 }
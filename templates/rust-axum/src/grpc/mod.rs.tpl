@@ -0,0 +1,40 @@
+{{- if .Proto}}// gRPC services of proto/{{.Proto.File}}
+use tonic::{Request, Response, Status};
+
+pub mod pb {
+    tonic::include_proto!("{{or .Proto.Package "_"}}");
+}
+{{range .Proto.Services}}
+pub use pb::{{snake .Name}}_server::{{pascal .Name}}Server;
+{{- end}}
+{{range .Proto.Services}}{{template "grpc-service" .}}{{end}}
+// ccin:modules
+{{- else}}// gRPC services, one per domain
+{{- range .Domains}}{{template "modules" ($.ForDomain .)}}{{end}}
+// ccin:modules
+{{- end}}
+{{- define "modules"}}{{if not .Proto}}
+pub mod {{.DomainLower}}_service;
+{{- end}}{{end}}
+{{- define "grpc-service"}}
+/// Serves the {{.Name}} service; its rpcs answer Unimplemented until they are
+/// mapped to {{if .Domains}}{{range $i, $domain := .Domains}}{{if $i}}, {{end}}crate::services::{{snake $domain}}_service{{end}}{{else}}the services{{end}}
+#[derive(Debug, Default)]
+pub struct {{pascal .Name}}Grpc;
+
+#[tonic::async_trait]
+impl pb::{{snake .Name}}_server::{{pascal .Name}} for {{pascal .Name}}Grpc {
+{{- range $i, $rpc := .RPCs}}{{if $i}}
+{{end}}{{if .ServerStreaming}}
+    type {{pascal .Name}}Stream = tonic::codegen::BoxStream<{{template "grpc-message" .Output}}>;
+{{end}}
+    async fn {{rustIdent (snake .Name)}}(
+        &self,
+        _request: Request<{{if .ClientStreaming}}tonic::Streaming<{{template "grpc-message" .Input}}>{{else}}{{template "grpc-message" .Input}}{{end}}>,
+    ) -> Result<Response<{{if .ServerStreaming}}Self::{{pascal .Name}}Stream{{else}}{{template "grpc-message" .Output}}{{end}}>, Status> {
+        Err(Status::unimplemented("{{.Name}} is not implemented"))
+    }
+{{- end}}
+}
+{{end}}
+{{- define "grpc-message"}}{{if eq . "google.protobuf.Empty"}}(){{else}}pb::{{pascal .}}{{end}}{{end}}
@@ -0,0 +1,53 @@
+use tonic::{Request, Response, Status};
+
+use crate::core::{{rustIdent .DomainLower}}::{{.DomainTitle}};
+use crate::services::{{.DomainLower}}_service::{{.DomainTitle}}Service;
+
+pub mod pb {
+    tonic::include_proto!("{{.DomainLower}}");
+}
+
+pub use pb::{{.DomainLower}}_service_server::{{.DomainTitle}}ServiceServer;
+
+use pb::{{.DomainLower}}_service_server::{{.DomainTitle}}Service as {{.DomainTitle}}ServiceApi;
+use pb::{Create{{.DomainTitle}}Request, List{{.DomainTitle}}Request, List{{.DomainTitle}}Response, {{.DomainTitle}}Message};
+
+#[derive(Debug, Default)]
+pub struct {{.DomainTitle}}GrpcService;
+
+#[tonic::async_trait]
+impl {{.DomainTitle}}ServiceApi for {{.DomainTitle}}GrpcService {
+    async fn list(
+        &self,
+        _request: Request<List{{.DomainTitle}}Request>,
+    ) -> Result<Response<List{{.DomainTitle}}Response>, Status> {
+        let items = {{.DomainTitle}}Service::list()
+            .into_iter()
+            .map(to_message)
+            .collect();
+        Ok(Response::new(List{{.DomainTitle}}Response { items }))
+    }
+
+    async fn create(
+        &self,
+        request: Request<Create{{.DomainTitle}}Request>,
+    ) -> Result<Response<{{.DomainTitle}}Message>, Status> {
+        let req = request.into_inner();
+{{- range .Fields}}{{if and .IsString (not .Optional)}}
+        if req.{{rustIdent .Snake}}.is_empty() {
+            return Err(Status::invalid_argument("{{.Snake}} is required"));
+        }
+{{- end}}{{end}}
+        let item = {{.DomainTitle}}Service::create({{range $i, $f := .Fields}}{{if $i}}, {{end}}req.{{rustIdent .Snake}}{{end}});
+        Ok(Response::new(to_message(item)))
+    }
+}
+
+fn to_message(item: {{.DomainTitle}}) -> {{.DomainTitle}}Message {
+    {{.DomainTitle}}Message {
+        id: item.id,
+{{- range .Fields}}
+        {{rustIdent .Snake}}: item.{{rustIdent .Snake}},
+{{- end}}
+    }
+}
@@ -0,0 +1,6 @@
+// Domain modules
+{{- range .Domains}}{{template "modules" ($.ForDomain .)}}{{end}}
+// ccin:modules
+{{- define "modules"}}
+pub mod {{.DomainLower}}_handler;
+{{- end}}
@@ -1,12 +1,94 @@
+{{- define "handlers" -}}
+use axum::{
+{{- if .Operations.Has "get"}}extract::Path, {{end}}http::StatusCode
+{{- if .Operations.Has "list" "get" "create"}}, Json{{end}}};
+{{- if .Operations.Has "create"}}
+use serde::Deserialize;
+{{- end}}
+{{- if .Operations.Has "list" "get" "create"}}
+
+use crate::services::{{.DomainLower}}_service::{{.DomainTitle}}Service;
+use crate::core::{{rustIdent .DomainLower}}::{{.DomainTitle}};
+{{- end}}
+{{- if .Operations.Has "create"}}
+
+#[derive(Deserialize)]
+pub struct Create{{.DomainTitle}}Request {
+{{- range .Fields}}
+    {{- if .Optional}}
+    #[serde(default)]
+    {{- end}}
+    {{- if ne (rustIdent .Snake) .Snake}}
+    #[serde(rename = "{{.Snake}}")]
+    {{- end}}
+    pub {{rustIdent .Snake}}: {{.RustFieldType}},
+{{- end}}
+}
+{{- end}}
+{{- range .Operations}}
+
+/// {{.Name}} handles {{.Method}} {{.Path}}{{with .Summary}} ({{.}}){{end}}
+{{- if eq .Action "list"}}
+pub async fn {{.Name | snake | rustIdent}}() -> (StatusCode, Json<Vec<{{$.DomainTitle}}>>) {
+    (StatusCode::{{screaming .StatusText}}, Json({{$.DomainTitle}}Service::list()))
+}
+{{- else if eq .Action "get"}}
+pub async fn {{.Name | snake | rustIdent}}(
+    Path(id): Path<u64>,
+) -> Result<(StatusCode, Json<{{$.DomainTitle}}>), (StatusCode, String)> {
+    {{$.DomainTitle}}Service::list()
+        .into_iter()
+        .find(|item| item.id == id)
+        .map(|item| (StatusCode::{{screaming .StatusText}}, Json(item)))
+        .ok_or((StatusCode::NOT_FOUND, "{{$.DomainTitle}} not found".to_string()))
+}
+{{- else if eq .Action "create"}}
+pub async fn {{.Name | snake | rustIdent}}(
+    Json(req): Json<Create{{$.DomainTitle}}Request>,
+{{- if .Response}}
+) -> Result<(StatusCode, Json<{{$.DomainTitle}}>), (StatusCode, String)> {
+{{- else}}
+) -> Result<StatusCode, (StatusCode, String)> {
+{{- end}}
+{{- range $.Fields}}{{if and .IsString (not .Optional)}}
+    if req.{{rustIdent .Snake}}.is_empty() {
+        return Err((StatusCode::BAD_REQUEST, "{{.Snake}} is required".to_string()));
+    }
+{{- end}}{{end}}
+{{- if .Response}}
+    let item = {{$.DomainTitle}}Service::create({{range $i, $f := $.Fields}}{{if $i}}, {{end}}req.{{rustIdent .Snake}}{{end}});
+    Ok((StatusCode::{{screaming .StatusText}}, Json(item)))
+{{- else}}
+    {{$.DomainTitle}}Service::create({{range $i, $f := $.Fields}}{{if $i}}, {{end}}req.{{rustIdent .Snake}}{{end}});
+    Ok(StatusCode::{{screaming .StatusText}})
+{{- end}}
+}
+{{- else}}
+pub async fn {{.Name | snake | rustIdent}}() -> (StatusCode, &'static str) {
+    // TODO: implement with {{$.DomainTitle}}Service
+    (StatusCode::NOT_IMPLEMENTED, "{{.Name}} is not implemented")
+}
+{{- end}}
+{{- end}}
+{{end}}
+{{- if .Operations}}{{template "handlers" .}}{{else -}}
 use axum::{Json, http::StatusCode};
 use serde::{Serialize, Deserialize};
 
 use crate::services::{{.DomainLower}}_service::{{.DomainTitle}}Service;
-use crate::core::{{.DomainLower}}::{{.DomainTitle}};
+use crate::core::{{rustIdent .DomainLower}}::{{.DomainTitle}};
 
 #[derive(Deserialize)]
 pub struct Create{{.DomainTitle}}Request {
-    pub name: String,
+{{- range .Fields}}
+    {{- if .Optional}}
+    #[serde(default)]
+    {{- end}}
+    {{- if ne (rustIdent .Snake) .Snake}}
+    #[serde(rename = "{{.Snake}}")]
+    {{- end}}
+    pub {{rustIdent .Snake}}: {{.RustFieldType}},
+{{- end}}
 }
 
 #[derive(Serialize)]
@@ -19,7 +101,15 @@ pub async fn list() -> Json<ApiResponse<Vec<{{.DomainTitle}}>>> {
     Json(ApiResponse { data: items })
 }
 
-pub async fn create(Json(req): Json<Create{{.DomainTitle}}Request>) -> (StatusCode, Json<ApiResponse<{{.DomainTitle}}>>) {
-    let item = {{.DomainTitle}}Service::create(req.name);
-    (StatusCode::CREATED, Json(ApiResponse { data: item }))
+pub async fn create(
+    Json(req): Json<Create{{.DomainTitle}}Request>,
+) -> Result<(StatusCode, Json<ApiResponse<{{.DomainTitle}}>>), (StatusCode, String)> {
+{{- range .Fields}}{{if and .IsString (not .Optional)}}
+    if req.{{rustIdent .Snake}}.is_empty() {
+        return Err((StatusCode::BAD_REQUEST, "{{.Snake}} is required".to_string()));
+    }
+{{- end}}{{end}}
+    let item = {{.DomainTitle}}Service::create({{range $i, $f := .Fields}}{{if $i}}, {{end}}req.{{rustIdent .Snake}}{{end}});
+    Ok((StatusCode::CREATED, Json(ApiResponse { data: item })))
 }
+{{end}}
\ No newline at end of file
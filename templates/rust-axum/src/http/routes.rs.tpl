@@ -1,10 +1,30 @@
-use axum::{routing::{get, post}, Router};
+{{- if .Contract -}}
+use axum::{routing::{self, get}, Router};
+{{- else -}}
+use axum::{routing::get, Router};
+{{- end}}
 
-use crate::http::handlers::{{.DomainLower}}_handler;
+use crate::http::handlers;
 
 pub fn create_router() -> Router {
     Router::new()
         .route("/health", get(|| async { "ok" }))
-        .route("/api/{{.DomainLower}}", get({{.DomainLower}}_handler::list))
-        .route("/api/{{.DomainLower}}", post({{.DomainLower}}_handler::create))
+{{- range .Domains}}{{template "routes" ($.ForDomain .)}}{{end}}
+        // ccin:routes
 }
+{{- define "routes"}}
+{{- if .Operations}}
+{{- $handler := printf "handlers::%s_handler" .DomainLower}}
+{{- range .Operations.ByPath}}
+        .route(
+            "{{.ColonPath}}",
+            {{range $i, $op := .Operations}}{{if $i}}.{{else}}routing::{{end}}{{lower .Method}}({{$handler}}::{{.Name | snake | rustIdent}}){{end}},
+        )
+{{- end}}
+{{- else}}
+        .route(
+            "/api/{{.DomainLower}}",
+            get(handlers::{{.DomainLower}}_handler::list).post(handlers::{{.DomainLower}}_handler::create),
+        )
+{{- end}}
+{{- end}}
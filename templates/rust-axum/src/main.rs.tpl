@@ -1,6 +1,5 @@
 use std::net::SocketAddr;
 
-use axum::{routing::{get, post}, Router};
 use tower_http::trace::TraceLayer;
 use tracing_subscriber::EnvFilter;
 
@@ -20,13 +19,14 @@ async fn main() -> Result<(), Box<dyn std::error::Error>> {
 
     // Build router
     let app = http::routes::create_router()
+        .layer(middleware::cors())
         .layer(TraceLayer::new_for_http());
 
     // HTTP address
     let port: u16 = std::env::var("PORT")
         .ok()
         .and_then(|s| s.parse().ok())
-        .unwrap_or(8080);
+        .unwrap_or({{.Port}});
     let http_addr: SocketAddr = ([0, 0, 0, 0], port).into();
 
     let http_task = async move {
@@ -46,11 +46,12 @@ async fn main() -> Result<(), Box<dyn std::error::Error>> {
     let grpc_addr: SocketAddr = ([0, 0, 0, 0], grpc_port).into();
 
     let grpc_task = async move {
-        let svc = grpc::service::{{.DomainTitle}}GrpcService::default();
-        let server = grpc::service::pb::{{.DomainTitle}}ServiceServer::new(svc);
         tracing::info!("gRPC listening on {}", grpc_addr);
         Server::builder()
-            .add_service(server)
+{{- if .Proto}}{{range .Proto.Services}}
+            .add_service(grpc::{{pascal .Name}}Server::new(grpc::{{pascal .Name}}Grpc::default()))
+{{- end}}{{else}}{{range .Domains}}{{template "grpc-services" ($.ForDomain .)}}{{end}}{{end}}
+            // ccin:grpc-services
             .serve(grpc_addr)
             .await?;
         Ok::<(), Box<dyn std::error::Error>>(())
@@ -63,3 +64,8 @@ async fn main() -> Result<(), Box<dyn std::error::Error>> {
 
     Ok(())
 }
+{{- define "grpc-services"}}{{if not .Proto}}
+            .add_service(grpc::{{.DomainLower}}_service::{{.DomainTitle}}ServiceServer::new(
+                grpc::{{.DomainLower}}_service::{{.DomainTitle}}GrpcService::default(),
+            ))
+{{- end}}{{end}}
@@ -1,13 +1,13 @@
-use crate::core::{{.DomainLower}}::{{.DomainTitle}};
+use crate::core::{{rustIdent .DomainLower}}::{{.DomainTitle}};
 
 pub struct {{.DomainTitle}}Service;
 
 impl {{.DomainTitle}}Service {
     pub fn list() -> Vec<{{.DomainTitle}}> {
-        vec![{{.DomainTitle}}::new(1, format!("sample-{{.DomainLower}}"))]
+        vec![{{.DomainTitle}}::new(1{{range .Fields}}, {{.RustExample}}{{end}})]
     }
 
-    pub fn create(name: String) -> {{.DomainTitle}} {
-        {{.DomainTitle}}::new(1, name)
+    pub fn create({{range $i, $f := .Fields}}{{if $i}}, {{end}}{{rustIdent .Snake}}: {{.RustFieldType}}{{end}}) -> {{.DomainTitle}} {
+        {{.DomainTitle}}::new(1{{range .Fields}}, {{rustIdent .Snake}}{{end}})
     }
 }
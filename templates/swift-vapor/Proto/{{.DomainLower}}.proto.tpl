@@ -12,7 +12,9 @@ service {{.DomainTitle}}Service {
 
 message {{.DomainTitle}}Item {
   string id = 1;
-  string name = 2;
+{{- range .Fields}}
+  {{if .Optional}}optional {{end}}{{.ProtoType}} {{.Snake}} = {{.ProtoNumber}};
+{{- end}}
 }
 
 message {{.DomainTitle}}ListRequest {}
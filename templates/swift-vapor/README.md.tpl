@@ -45,6 +45,8 @@ The gRPC endpoint (scaffold) is planned at `localhost:50051` (see gRPC section b
 ### Health Check
 - `GET /health` — returns `OK`
 
+{{- range .Domains}}
+
 ### {{.DomainTitle}} Management
 The RESTful routes are mounted under `/api/v1/{{.DomainLower}}`.
 
@@ -53,21 +55,22 @@ The RESTful routes are mounted under `/api/v1/{{.DomainLower}}`.
 - `POST /api/v1/{{.DomainLower}}` — Create
 - `PUT /api/v1/{{.DomainLower}}/:id` — Update by ID
 - `DELETE /api/v1/{{.DomainLower}}/:id` — Delete by ID
+{{- end}}
 
 ### Example Requests
-
+{{range .Domains}}
 Create {{.DomainLower}}:
 ```bash
-curl -X POST http://localhost:{{.Port}}/api/v1/{{.DomainLower}} \
+curl -X POST http://localhost:{{$.Port}}/api/v1/{{.DomainLower}} \
   -H "Content-Type: application/json" \
-  -d '{"name": "Example {{.DomainTitle}}"}'
+  -d '{ {{- range $i, $f := .Fields}}{{if $i}}, {{end}}"{{.Camel}}": {{.Example}}{{end -}} }'
 ```
 
 List {{.DomainLower}}:
 ```bash
-curl http://localhost:{{.Port}}/api/v1/{{.DomainLower}}
+curl http://localhost:{{$.Port}}/api/v1/{{.DomainLower}}
 ```
-
+{{end}}
 ## Project Structure
 
 ```
@@ -87,8 +90,10 @@ curl http://localhost:{{.Port}}/api/v1/{{.DomainLower}}
 │   └── Run/
 │       └── main.swift               # Entry point
 {{- if .WithGRPC}}
-├── Proto/
-│   └── {{.DomainLower}}.proto       # Example proto definitions
+├── Proto/                          # Example proto definitions
+{{- range .Domains}}
+│   ├── {{.DomainLower}}.proto
+{{- end}}
 {{- end}}
 ├── Dockerfile
 ├── Makefile
@@ -117,8 +122,10 @@ docker run -p {{.Port}}:{{.Port}} {{- if .WithGRPC}} -p 50051:50051{{- end}} {{.
 ## gRPC (Optional)
 {{- if .WithGRPC}}
 This template includes:
+{{- range .Domains}}
 - Proto file at `Proto/{{.DomainLower}}.proto`
 - Placeholder Swift file at `Sources/App/GRPC/{{.DomainTitle}}GRPCService.swift`
+{{- end}}
 
 To enable gRPC:
 1. Install `protoc` and `grpc-swift` plugins.
@@ -126,12 +133,14 @@ To enable gRPC:
    ```bash
    protoc \
      --swift_out=./Sources/App/GRPC \
-     --swift-grpc_out=./Sources/App/GRPC \
+     --swift-grpc_out=./Sources/App/GRPC
+     {{- range .Domains}} \
      Proto/{{.DomainLower}}.proto
+     {{- end}}
    ```
 3. Implement and bind your service provider in `configure.swift` (see `startGRPCServer`).
 {{- else}}
-You can enable gRPC generation using the CLI flag `--grpc` when generating the project: `ccin generate swift-vapor {{.ProjectName}} --domain {{.DomainLower}} --grpc`.
+You can enable gRPC generation using the CLI flag `--grpc` when generating the project: `ccin generate swift-vapor {{.ProjectName}} --domain {{range $i, $domain := .Domains}}{{if $i}},{{end}}{{.DomainLower}}{{end}} --grpc`.
 {{- end}}
 
 ## Environment
@@ -145,4 +154,4 @@ You can enable gRPC generation using the CLI flag `--grpc` when generating the p
 - The MetricsMiddleware logs request durations to help with basic observability.
 
 ## License
-This project is licensed under the MIT License - see the LICENSE file for details.
+This project is licensed under the {{or .License "MIT"}} License - see the LICENSE file for details.
@@ -1,3 +1,63 @@
+{{- define "controller" -}}
+import Vapor
+
+// {{.DomainTitle}}Controller serves {{pluralize .DomainLower}} as {{.Contract}} declares them
+struct {{.DomainTitle}}Controller {
+    let service: {{.DomainTitle}}Service
+
+    func boot(routes: RoutesBuilder) throws {
+{{- range .Operations}}
+        routes.on(.{{.Method}}, {{range $i, $s := .Segments}}{{if $i}}, {{end}}"{{$s}}"{{end}}, use: {{.Name | camel | swiftIdent}})
+{{- end}}
+    }
+{{- range .Operations}}
+
+    // {{.Name}} handles {{.Method}} {{.Path}}{{with .Summary}} ({{.}}){{end}}
+{{- $name := .Name | camel | swiftIdent}}
+{{- if eq .Action "custom"}}
+    func {{$name}}(req: Request) async throws -> HTTPStatus {
+        // TODO: implement with service
+        throw Abort(.notImplemented, reason: "{{.Name}} is not implemented")
+    }
+{{- else}}
+{{- if and (eq .Status 200) .Response}}
+    func {{$name}}(req: Request) async throws -> {{if eq .Response "list"}}[{{$.DomainTitle}}]{{else}}{{$.DomainTitle}}{{end}} {
+{{- else if .Response}}
+    func {{$name}}(req: Request) async throws -> Response {
+{{- else}}
+    func {{$name}}(req: Request) async throws -> HTTPStatus {
+{{- end}}
+{{- if and (ne .Action "list") (ne .Action "create")}}
+        guard let id = req.parameters.get("{{.Param}}", as: UUID.self) else {
+            throw Abort(.badRequest, reason: "Invalid ID")
+        }
+{{- end}}
+{{- if .Request}}
+        try {{$.DomainTitle}}.validate(content: req)
+        let dto = try req.content.decode({{$.DomainTitle}}.self)
+{{- end}}
+{{- $call := ""}}
+{{- if eq .Action "list"}}{{$call = "service.list(req: req)"}}
+{{- else if eq .Action "get"}}{{$call = "service.get(id: id, req: req)"}}
+{{- else if eq .Action "create"}}{{$call = "service.create(dto, req: req)"}}
+{{- else if eq .Action "update"}}{{$call = "service.update(id: id, dto, req: req)"}}
+{{- else}}{{$call = "service.delete(id: id, req: req)"}}
+{{- end}}
+{{- if not .Response}}
+        {{if ne .Action "delete"}}_ = {{end}}try await {{$call}}
+        return .init(statusCode: {{.Status}})
+{{- else if eq .Status 200}}
+        return try await {{$call}}
+{{- else}}
+        let item = try await {{$call}}
+        return try await item.encodeResponse(status: .init(statusCode: {{.Status}}), for: req)
+{{- end}}
+    }
+{{- end}}
+{{- end}}
+}
+{{end}}
+{{- if .Operations}}{{template "controller" .}}{{else -}}
 import Vapor
 
 struct {{.DomainTitle}}Controller {
@@ -24,6 +84,7 @@ struct {{.DomainTitle}}Controller {
     }
 
     func create(req: Request) async throws -> {{.DomainTitle}} {
+        try {{.DomainTitle}}.validate(content: req)
         let dto = try req.content.decode({{.DomainTitle}}.self)
         return try await service.create(dto, req: req)
     }
@@ -32,6 +93,7 @@ struct {{.DomainTitle}}Controller {
         guard let id = req.parameters.get("id", as: UUID.self) else {
             throw Abort(.badRequest, reason: "Invalid ID")
         }
+        try {{.DomainTitle}}.validate(content: req)
         let dto = try req.content.decode({{.DomainTitle}}.self)
         return try await service.update(id: id, dto, req: req)
     }
@@ -44,3 +106,4 @@ struct {{.DomainTitle}}Controller {
         return .noContent
     }
 }
+{{end}}
\ No newline at end of file
@@ -0,0 +1,35 @@
+#if canImport(GRPC)
+import GRPC
+import NIO
+import Foundation
+
+// These are placeholders for the services of Proto/{{.Proto.File}}.
+// After generating Swift code from it using protoc with grpc-swift, uncomment
+// the conformances and implement each rpc with your domain Services.
+{{- $prefix := .Proto.SwiftPrefix}}
+{{- range .Proto.Services}}
+
+final class {{pascal .Name}}GRPCProvider /*: {{$prefix}}{{pascal .Name}}Provider*/ {
+{{- range .Domains}}
+    // let {{camel .}}Service: {{pascal .}}Service
+{{- end}}
+{{- if .Domains}}
+    // init({{range $i, $domain := .Domains}}{{if $i}}, {{end}}{{camel $domain}}Service: {{pascal $domain}}Service{{end}}) { {{range $i, $domain := .Domains}}{{if $i}}; {{end}}self.{{camel $domain}}Service = {{camel $domain}}Service{{end}} }
+{{- end}}
+{{- range .RPCs}}
+{{- $input := printf "%s%s" $prefix (pascal .Input)}}{{if eq .Input "google.protobuf.Empty"}}{{$input = "Google_Protobuf_Empty"}}{{end}}
+{{- $output := printf "%s%s" $prefix (pascal .Output)}}{{if eq .Output "google.protobuf.Empty"}}{{$output = "Google_Protobuf_Empty"}}{{end}}
+    //
+{{- if .ClientStreaming}}
+    // func {{swiftIdent (camel .Name)}}(context: {{if .ServerStreaming}}StreamingResponseCallContext{{else}}UnaryResponseCallContext{{end}}<{{$output}}>) -> EventLoopFuture<(StreamEvent<{{$input}}>) -> Void> {
+{{- else if .ServerStreaming}}
+    // func {{swiftIdent (camel .Name)}}(request: {{$input}}, context: StreamingResponseCallContext<{{$output}}>) -> EventLoopFuture<GRPCStatus> {
+{{- else}}
+    // func {{swiftIdent (camel .Name)}}(request: {{$input}}, context: StatusOnlyCallContext) -> EventLoopFuture<{{$output}}> {
+{{- end}}
+    //     context.eventLoop.makeFailedFuture(GRPCStatus(code: .unimplemented, message: "{{.Name}} is not implemented"))
+    // }
+{{- end}}
+}
+{{- end}}
+#endif
@@ -3,14 +3,30 @@ import Vapor
 
 struct {{.DomainTitle}}: Content, Equatable, Codable, Identifiable {
     var id: UUID?
-    var name: String
+{{- range .Fields}}
+    var {{swiftIdent .Camel}}: {{.SwiftFieldType}}
+{{- end}}
     var createdAt: Date?
     var updatedAt: Date?
 
-    init(id: UUID? = nil, name: String, createdAt: Date? = nil, updatedAt: Date? = nil) {
+    init(id: UUID? = nil{{range .Fields}}, {{swiftIdent .Camel}}: {{.SwiftFieldType}}{{if .Optional}} = nil{{end}}{{end}}, createdAt: Date? = nil, updatedAt: Date? = nil) {
         self.id = id
-        self.name = name
+{{- range .Fields}}
+        self.{{.Camel}} = {{swiftIdent .Camel}}
+{{- end}}
         self.createdAt = createdAt
         self.updatedAt = updatedAt
     }
 }
+
+extension {{.DomainTitle}}: Validatable {
+    static func validations(_ validations: inout Validations) {
+{{- range .Fields}}
+        {{- if and .IsString (not .Optional) (ne .Type "uuid")}}
+        validations.add("{{.Camel}}", as: String.self, is: !.empty)
+        {{- else if not .Optional}}
+        validations.add("{{.Camel}}", as: {{.SwiftType}}.self, required: true)
+        {{- end}}
+{{- end}}
+    }
+}
@@ -29,7 +29,9 @@ final class {{.DomainTitle}}Service {
         guard var existing = storage[id] else {
             throw Abort(.notFound, reason: "{{.DomainTitle}} not found")
         }
-        existing.name = dto.name
+{{- range .Fields}}
+        existing.{{.Camel}} = dto.{{.Camel}}
+{{- end}}
         existing.updatedAt = Date()
         storage[id] = existing
         return existing
@@ -13,7 +13,7 @@ public func configure(_ app: Application) throws {
     try routes(app)
 
     {{- if .WithGRPC}}
-    // gRPC scaffold (requires generating Swift gRPC code from Proto/{{.DomainLower}}.proto)
+    // gRPC scaffold (requires generating Swift gRPC code from the Proto/ definitions)
     try startGRPCServer(app)
     {{- end}}
 }
@@ -23,13 +23,21 @@ import GRPC
 
 // Placeholder gRPC bootstrap. Add your generated service providers here.
 func startGRPCServer(_ app: Application) throws {
-    app.logger.info("gRPC scaffold enabled. TODO: generate service stubs from Proto/{{.DomainLower}}.proto and bind the server.")
+    app.logger.info("gRPC scaffold enabled. TODO: generate service stubs from the Proto/ definitions and bind the server.")
     // Example (after generating providers):
     // let group = app.eventLoopGroup
     // let server = Server.insecure(group: group)
-    // let provider = {{.DomainTitle}}ServiceProvider() // from generated code
-    // _ = try server.withServiceProviders([provider]).bind(host: "0.0.0.0", port: 50051).wait()
+    // let providers: [CallHandlerProvider] = [
+{{- if .Proto}}{{range .Proto.Services}}
+    //     {{pascal .Name}}GRPCProvider(), // from GRPC/ProtoServices.swift
+{{- end}}{{else}}{{range .Domains}}{{template "grpc-providers" ($.ForDomain .)}}{{end}}{{end}}
+    //     ccin:grpc-providers
+    // ]
+    // _ = try server.withServiceProviders(providers).bind(host: "0.0.0.0", port: 50051).wait()
 }
 #else
 func startGRPCServer(_ app: Application) throws { /* gRPC not available */ }
 #endif
+{{- define "grpc-providers"}}{{if not .Proto}}
+    //     {{.DomainTitle}}ServiceProvider(), // from generated code
+{{- end}}{{end}}
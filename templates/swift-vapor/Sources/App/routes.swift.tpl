@@ -6,11 +6,27 @@ public func routes(_ app: Application) throws {
         return "OK"
     }
 
+{{- if .Contract}}
+
+    // API routes of {{.Contract}}
+{{- else}}
+
     // API v1 routes
     let api = app.grouped("api", "v1")
-
+{{- end}}
+{{range .Domains}}{{template "routes" ($.ForDomain .)}}{{end}}
+    // ccin:routes
+}
+{{- define "routes"}}
+{{- if .Operations}}
+    // Domain routes: {{.DomainLower}}
+    let {{.DomainLower}}Service = {{.DomainTitle}}Service()
+    let {{.DomainLower}}Controller = {{.DomainTitle}}Controller(service: {{.DomainLower}}Service)
+    try {{.DomainLower}}Controller.boot(routes: app)
+{{else}}
     // Domain routes: /api/v1/{{.DomainLower}}
     let {{.DomainLower}}Service = {{.DomainTitle}}Service()
     let {{.DomainLower}}Controller = {{.DomainTitle}}Controller(service: {{.DomainLower}}Service)
     try {{.DomainLower}}Controller.boot(routes: api)
-}
+{{end}}
+{{- end}}
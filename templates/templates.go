@@ -0,0 +1,12 @@
+// Package templates embeds the project templates into the ccin binary.
+package templates
+
+import "embed"
+
+// FS holds every template set, keyed by generator name at the top level
+// (e.g. "go-gin/main.go.tpl"), next to base sets such as go-core that
+// generators extend and the shared _partials. The all: prefix keeps dotfiles
+// such as .env.example.tpl and .nvmrc, and the _partials directory.
+//
+//go:embed all:_partials all:common all:go-core all:go-fiber all:go-gin all:nestjs all:rust-axum all:swift-vapor
+var FS embed.FS